@@ -38,16 +38,27 @@ if test $IS_TERMUX -eq 0; and set -q BREW_BIN; and test -f $BREW_BIN
     eval ($BREW_BIN shellenv)
 end
 
+# Installer overrides (written when configs are linked with --link)
+if test -f ~/.config/gentleman/config.fish
+    source ~/.config/gentleman/config.fish
+end
+
+# >>> gentleman.dots:multiplexer >>>
 # Start selected terminal multiplexer
-if status is-interactive; and command -q tmux; and not set -q TMUX; and not set -q ZELLIJ; and not set -q HERDR_ENV
+if status is-interactive; and command -q tmux; and not set -q TMUX; and not set -q ZELLIJ; and not set -q HERDR_ENV; and not set -q GENTLEMAN_WM_HANDLED
     tmux new-session -A -s main
 end
+# <<< gentleman.dots:multiplexer <<<
 
 # Initialize tools
 starship init fish | source
 zoxide init fish | source
 atuin init fish | source
+# >>> gentleman.dots:fzf >>>
+if command -v fzf &> /dev/null
 fzf --fish | source
+end
+# <<< gentleman.dots:fzf <<<
 
 set -x PATH $HOME/.cargo/bin $PATH
 
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
 	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/tui"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -25,7 +28,25 @@ type cliFlags struct {
 	windowMgr      string
 	nvim           bool
 	font           bool
+	nerdFont       string
+	fontVersion    string
 	backup         bool
+	link           bool
+	repoDir        string
+	nix            bool
+	nixOut         string
+	mirror         string
+	proxy          string
+	noProxy        string
+	caBundle       string
+	githubMirror   string
+	brewMirror     string
+	allowScripts   bool
+	allowUnpinned  bool
+	cacheDir       string
+	skipPreflight  bool
+	theme          string
+	herdrVersion   string
 }
 
 func parseFlags() *cliFlags {
@@ -40,11 +61,29 @@ func parseFlags() *cliFlags {
 	flag.BoolVar(&flags.dryRun, "dry-run", false, "Show what would be installed without doing it")
 	flag.BoolVar(&flags.nonInteractive, "non-interactive", false, "Run without TUI, use CLI flags")
 	flag.StringVar(&flags.terminal, "terminal", "", "Terminal: alacritty, wezterm, kitty, ghostty, none")
-	flag.StringVar(&flags.shell, "shell", "", "Shell: fish, zsh, nushell")
+	flag.StringVar(&flags.shell, "shell", "", "Shell: fish, zsh, nushell, bash")
 	flag.StringVar(&flags.windowMgr, "wm", "", "Window manager: tmux, zellij, herdr, none")
 	flag.BoolVar(&flags.nvim, "nvim", false, "Install Neovim configuration")
 	flag.BoolVar(&flags.font, "font", false, "Install Nerd Font")
+	flag.StringVar(&flags.nerdFont, "nerd-font", "", "Nerd Font to install (implies --font): "+strings.Join(tui.ValidNerdFonts, ", "))
+	flag.StringVar(&flags.fontVersion, "font-version", "", "nerd-fonts release to download, e.g. v3.3.0")
 	flag.BoolVar(&flags.backup, "backup", true, "Backup existing configs (default: true)")
+	flag.BoolVar(&flags.link, "link", false, "Symlink configs from a persistent clone instead of copying")
+	flag.StringVar(&flags.repoDir, "repo-dir", "", "Persistent clone location for --link (default: ~/.local/share/gentleman-dots)")
+	flag.BoolVar(&flags.nix, "nix", false, "Generate a home-manager module instead of installing packages")
+	flag.StringVar(&flags.nixOut, "nix-out", "", "Output directory for --nix (default: ~/.config/home-manager/gentleman)")
+	flag.StringVar(&flags.mirror, "mirror", "", "Local package mirror URL to verify in pre-flight checks")
+	flag.StringVar(&flags.proxy, "proxy", "", "HTTP(S) proxy for every download (default: $HTTPS_PROXY)")
+	flag.StringVar(&flags.noProxy, "no-proxy", "", "Comma-separated hosts that bypass the proxy")
+	flag.StringVar(&flags.caBundle, "ca-bundle", "", "PEM file with extra CA certificates to trust (TLS interception)")
+	flag.StringVar(&flags.githubMirror, "github-mirror", "", "Base URL that replaces https://github.com")
+	flag.StringVar(&flags.brewMirror, "brew-mirror", "", "Homebrew bottle mirror (HOMEBREW_BOTTLE_DOMAIN)")
+	flag.BoolVar(&flags.allowScripts, "allow-install-scripts", false, "Allow unverifiable vendor installers (Homebrew, rustup, Claude Code, OpenCode)")
+	flag.BoolVar(&flags.allowUnpinned, "allow-unpinned", false, "Trust the checksum a release publishes for downloads without a pinned one")
+	flag.StringVar(&flags.theme, "theme", "", "Color theme applied to every config: "+strings.Join(tui.ValidThemes, ", "))
+	flag.StringVar(&flags.herdrVersion, "herdr-version", "", "Herdr release to install: latest or a version such as 0.7.1")
+	flag.BoolVar(&flags.skipPreflight, "skip-preflight", false, "Continue --non-interactive runs when pre-flight checks fail")
+	flag.StringVar(&flags.cacheDir, "cache-dir", "", "Download cache directory, or a bundle from 'cache export' (default: ~/.cache/gentleman-dots)")
 
 	flag.Parse()
 	return flags
@@ -72,6 +111,44 @@ func main() {
 		fmt.Println("🧪 Dry-run mode: No actual installations will be performed")
 	}
 
+	if flags.link {
+		tui.SetLinkMode(true, flags.repoDir)
+	}
+
+	if flags.nixOut != "" {
+		tui.SetNixOutputDir(flags.nixOut)
+	}
+
+	system.SetAllowInstallScripts(flags.allowScripts)
+	system.SetAllowUnpinned(flags.allowUnpinned)
+	tui.SetSkipPreflight(flags.skipPreflight)
+
+	if err := tui.SetHerdrVersion(flags.herdrVersion); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if flags.cacheDir != "" {
+		if err := system.UseCacheDir(flags.cacheDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := configureNetwork(flags); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Commands reconfigure an existing installation: gentleman.dots set wm zellij
+	if args := flag.Args(); len(args) > 0 {
+		if err := runCommand(args, flags); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Non-interactive mode: run installation directly with provided flags
 	if flags.nonInteractive {
 		if err := runNonInteractive(flags); err != nil {
@@ -96,10 +173,29 @@ func main() {
 	}
 }
 
+// configureNetwork layers the environment, ~/.config/gentleman/network.conf
+// and flags (highest precedence) into the network settings for every fetch
+func configureNetwork(flags *cliFlags) error {
+	profile, err := system.LoadNetworkProfile(system.NetworkProfilePath())
+	if err != nil {
+		return err
+	}
+	cfg := system.NetworkFromEnv().Merge(profile).Merge(system.NetworkConfig{
+		HTTPProxy:        flags.proxy,
+		HTTPSProxy:       flags.proxy,
+		NoProxy:          flags.noProxy,
+		CABundle:         flags.caBundle,
+		GitHubMirror:     flags.githubMirror,
+		BrewBottleMirror: flags.brewMirror,
+		PackageMirror:    flags.mirror,
+	})
+	return system.SetNetwork(cfg)
+}
+
 func runNonInteractive(flags *cliFlags) error {
 	// Validate required flags
 	if flags.shell == "" {
-		return fmt.Errorf("--shell is required (fish, zsh, nushell)")
+		return fmt.Errorf("--shell is required (fish, zsh, nushell, bash)")
 	}
 
 	// Normalize inputs
@@ -109,18 +205,32 @@ func runNonInteractive(flags *cliFlags) error {
 
 	// Validate values
 	validTerminals := map[string]bool{"alacritty": true, "wezterm": true, "kitty": true, "ghostty": true, "none": true, "": true}
-	validShells := map[string]bool{"fish": true, "zsh": true, "nushell": true}
+	validShells := map[string]bool{"fish": true, "zsh": true, "nushell": true, "bash": true}
 	validWMs := map[string]bool{"tmux": true, "zellij": true, "herdr": true, "none": true, "": true}
 
 	if !validTerminals[terminal] {
 		return fmt.Errorf("invalid terminal: %s (valid: alacritty, wezterm, kitty, ghostty, none)", terminal)
 	}
 	if !validShells[shell] {
-		return fmt.Errorf("invalid shell: %s (valid: fish, zsh, nushell)", shell)
+		return fmt.Errorf("invalid shell: %s (valid: fish, zsh, nushell, bash)", shell)
 	}
 	if !validWMs[wm] {
 		return fmt.Errorf("invalid window manager: %s (valid: tmux, zellij, herdr, none)", wm)
 	}
+	nerdFont := strings.ToLower(flags.nerdFont)
+	if nerdFont != "" && !contains(tui.ValidNerdFonts, nerdFont) {
+		return fmt.Errorf("invalid nerd font: %s (valid: %s)", nerdFont, strings.Join(tui.ValidNerdFonts, ", "))
+	}
+	if flags.fontVersion != "" && !tui.ValidFontVersion(flags.fontVersion) {
+		return fmt.Errorf("unsupported font version: %s", flags.fontVersion)
+	}
+	theme := strings.ToLower(flags.theme)
+	if theme != "" && !contains(tui.ValidThemes, theme) {
+		return fmt.Errorf("invalid theme: %s (valid: %s)", theme, strings.Join(tui.ValidThemes, ", "))
+	}
+	if theme != "" && flags.nix {
+		return fmt.Errorf("--theme cannot be combined with --nix; the module ships the configs as-is")
+	}
 
 	// Default empty values to "none"
 	if terminal == "" {
@@ -136,8 +246,12 @@ func runNonInteractive(flags *cliFlags) error {
 		Shell:        shell,
 		WindowMgr:    wm,
 		InstallNvim:  flags.nvim,
-		InstallFont:  flags.font,
-		CreateBackup: flags.backup,
+		InstallFont:  flags.font || nerdFont != "",
+		Font:         nerdFont,
+		FontVersion:  flags.fontVersion,
+		CreateBackup: flags.backup && !flags.nix,
+		NixMode:      flags.nix,
+		Theme:        theme,
 	}
 
 	fmt.Println("🚀 Gentleman.Dots Non-Interactive Installer")
@@ -146,8 +260,21 @@ func runNonInteractive(flags *cliFlags) error {
 	fmt.Printf("  Shell:       %s\n", choices.Shell)
 	fmt.Printf("  Window Mgr:  %s\n", choices.WindowMgr)
 	fmt.Printf("  Neovim:      %v\n", choices.InstallNvim)
-	fmt.Printf("  Font:        %v\n", choices.InstallFont)
+	if choices.InstallFont {
+		fmt.Printf("  Font:        %s\n", tui.FontDescription(choices))
+	} else {
+		fmt.Printf("  Font:        %v\n", choices.InstallFont)
+	}
+	if choices.Theme != "" {
+		fmt.Printf("  Theme:       %s\n", choices.Theme)
+	}
 	fmt.Printf("  Backup:      %v\n", choices.CreateBackup)
+	if flags.link {
+		fmt.Printf("  Link from:   %s\n", tui.LinkRepoDir())
+	}
+	if flags.nix {
+		fmt.Printf("  Nix module:  %s\n", tui.NixOutputDir())
+	}
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 
@@ -155,6 +282,256 @@ func runNonInteractive(flags *cliFlags) error {
 	return tui.RunNonInteractive(choices)
 }
 
+func runCommand(args []string, flags *cliFlags) error {
+	switch args[0] {
+	case "set":
+		return runSet(args[1:])
+	case "cache":
+		return runCache(args[1:])
+	case "ghostty":
+		return runGhostty(args[1:])
+	case "herdr":
+		return runHerdr(args[1:], flags.herdrVersion)
+	case "tmux":
+		return runTmux(args[1:])
+	default:
+		return fmt.Errorf("unknown command: %s (run with --help for usage)", args[0])
+	}
+}
+
+func runSet(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: gentleman.dots set <setting> <value> (settings: %s)", strings.Join(tui.ReconfigureSettings, ", "))
+	}
+	setting := strings.ToLower(args[0])
+	value := strings.ToLower(args[1])
+
+	var choices tui.UserChoices
+	if key, ok := strings.CutPrefix(setting, "terminal."); ok {
+		s, ok := system.LookupTerminalSetting(key)
+		if !ok {
+			return fmt.Errorf("unknown setting: %s (valid: %s)", setting, strings.Join(tui.ReconfigureSettings, ", "))
+		}
+		v, err := s.Parse(value)
+		if err != nil {
+			return err
+		}
+		choices.TerminalSettings = map[string]float64{key: v}
+		fmt.Printf("🔧 Setting %s to %s\n\n", setting, system.FormatSetting(v))
+		return tui.RunReconfigure("terminal", choices)
+	}
+	if key, ok := strings.CutPrefix(setting, "herdr."); ok {
+		b, ok := system.LookupHerdrBinding(key)
+		if !ok {
+			return fmt.Errorf("unknown setting: %s (valid: %s)", setting, strings.Join(tui.ReconfigureSettings, ", "))
+		}
+		spec, err := b.Parse(value)
+		if err != nil {
+			return err
+		}
+		choices.HerdrKeys = map[string]string{key: spec}
+		fmt.Printf("🔧 Setting %s to %s\n\n", setting, spec)
+		return tui.RunReconfigure("herdrkeys", choices)
+	}
+
+	switch setting {
+	case "wm":
+		if !contains(tui.ValidWMs, value) {
+			return fmt.Errorf("invalid window manager: %s (valid: %s)", value, strings.Join(tui.ValidWMs, ", "))
+		}
+		choices.WindowMgr = value
+	case "shell":
+		if !contains(tui.ValidShells, value) {
+			return fmt.Errorf("invalid shell: %s (valid: %s)", value, strings.Join(tui.ValidShells, ", "))
+		}
+		choices.Shell = value
+	case "theme":
+		if !contains(tui.ValidThemes, value) {
+			return fmt.Errorf("invalid theme: %s (valid: %s)", value, strings.Join(tui.ValidThemes, ", "))
+		}
+		choices.Theme = value
+	case "layout":
+		layouts, _, err := tui.InstalledZellijLayouts()
+		if err != nil {
+			return err
+		}
+		if !contains(layouts, value) {
+			return fmt.Errorf("invalid layout: %s (valid: %s)", value, strings.Join(layouts, ", "))
+		}
+		choices.ZellijLayout = value
+	default:
+		return fmt.Errorf("unknown setting: %s (valid: %s)", setting, strings.Join(tui.ReconfigureSettings, ", "))
+	}
+
+	fmt.Printf("🔧 Setting %s to %s\n\n", setting, value)
+	return tui.RunReconfigure(setting, choices)
+}
+
+const shadersUsage = "usage: gentleman.dots ghostty shaders [list | set <shader>... | add <shader> | remove <shader> | off]"
+
+func runGhostty(args []string) error {
+	if len(args) == 0 || args[0] != "shaders" {
+		return errors.New(shadersUsage)
+	}
+	action, args := "list", args[1:]
+	if len(args) > 0 {
+		action, args = strings.ToLower(args[0]), args[1:]
+	}
+
+	current, err := tui.CurrentGhosttyShaders()
+	if err != nil {
+		return err
+	}
+
+	var shaders []string
+	switch action {
+	case "list":
+		printShaders(current)
+		return nil
+	case "set":
+		if len(args) == 0 {
+			return errors.New(shadersUsage)
+		}
+		for _, name := range args {
+			file, err := tui.ResolveShader(name)
+			if err != nil {
+				return err
+			}
+			if !contains(shaders, file) {
+				shaders = append(shaders, file)
+			}
+		}
+	case "add":
+		if len(args) != 1 {
+			return errors.New(shadersUsage)
+		}
+		file, err := tui.ResolveShader(args[0])
+		if err != nil {
+			return err
+		}
+		if contains(current, file) {
+			return fmt.Errorf("%s is already enabled", file)
+		}
+		shaders = append(current, file)
+	case "remove":
+		if len(args) != 1 {
+			return errors.New(shadersUsage)
+		}
+		file := system.ShaderFile(args[0])
+		if !contains(current, file) {
+			return fmt.Errorf("%s is not enabled", file)
+		}
+		for _, f := range current {
+			if f != file {
+				shaders = append(shaders, f)
+			}
+		}
+	case "off":
+	default:
+		return errors.New(shadersUsage)
+	}
+
+	if len(shaders) == 0 {
+		fmt.Printf("✨ Disabling Ghostty shaders\n\n")
+	} else {
+		fmt.Printf("✨ Setting Ghostty shaders: %s\n\n", strings.Join(shaders, " → "))
+	}
+	return tui.RunReconfigure("shaders", tui.UserChoices{Shaders: shaders})
+}
+
+// printShaders lists the catalog by kind, numbering the enabled shaders in order
+func printShaders(enabled []string) {
+	position := func(file string) string {
+		for i, f := range enabled {
+			if f == file {
+				return fmt.Sprintf("[%d]", i+1)
+			}
+		}
+		return "[ ]"
+	}
+	for _, kind := range []system.ShaderKind{system.ShaderCursor, system.ShaderBackground, system.ShaderPost} {
+		fmt.Printf("%s\n", strings.ToUpper(string(kind)))
+		for _, s := range system.GhosttyShaders {
+			if s.Kind != kind {
+				continue
+			}
+			fmt.Printf("  %-4s %-28s %-26s %s\n", position(s.File), s.Name(), s.Tags(), s.Description)
+		}
+		fmt.Println()
+	}
+	for _, file := range enabled {
+		if _, ok := system.LookupShader(file); !ok {
+			fmt.Printf("  %-4s %s (not in the catalog)\n", position(file), strings.TrimSuffix(file, ".glsl"))
+		}
+	}
+}
+
+func runHerdr(args []string, version string) error {
+	if len(args) == 0 || len(args) > 2 || args[0] != "update" {
+		return fmt.Errorf("usage: gentleman.dots herdr update [latest | <version>]")
+	}
+	if len(args) == 2 {
+		version = args[1]
+	}
+	if version == "" {
+		version = "latest"
+	}
+	if err := tui.SetHerdrVersion(version); err != nil {
+		return err
+	}
+	fmt.Printf("🔧 Updating Herdr to %s\n\n", version)
+	return tui.RunHerdrUpdate()
+}
+
+func runTmux(args []string) error {
+	if len(args) == 0 || len(args) > 2 || args[0] != "lock" {
+		return fmt.Errorf("usage: gentleman.dots tmux lock [<repo-dir>]")
+	}
+	repoDir := "."
+	if len(args) == 2 {
+		repoDir = args[1]
+	}
+	fmt.Printf("🔒 Pinning the tmux plugins of %s\n", filepath.Join(repoDir, "GentlemanTmux", "tmux.conf"))
+	lock, err := tui.LockTmuxPlugins(repoDir)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(lock))
+	for name := range lock {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  %-20s %s %s\n", name, lock[name].Branch, lock[name].Commit)
+	}
+	fmt.Printf("✓ Wrote %s; commit it so every install gets these commits\n", filepath.Join(repoDir, "GentlemanTmux", "tmux-lock.json"))
+	return nil
+}
+
+func runCache(args []string) error {
+	if len(args) != 2 || args[0] != "export" {
+		return fmt.Errorf("usage: gentleman.dots cache export <bundle.tar.gz>")
+	}
+	if !system.FileExists(system.CacheDir()) {
+		return fmt.Errorf("nothing cached yet in %s; run an installation first", system.CacheDir())
+	}
+	fmt.Printf("📦 Exporting %s to %s\n", system.CacheDir(), args[1])
+	if err := system.ExportCacheBundle(args[1]); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Bundle written; use it offline with --cache-dir=%s\n", args[1])
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func setupTestMode() {
 	// Create a temporary test directory
 	testDir := filepath.Join(os.TempDir(), "gentleman-dots-test")
@@ -182,6 +559,10 @@ func printHelp() {
 
 Usage:
   gentleman.dots [flags]
+  gentleman.dots [flags] set <setting> <value>
+  gentleman.dots [flags] cache export <bundle.tar.gz>
+  gentleman.dots [flags] ghostty shaders [list | set | add | remove | off]
+  gentleman.dots tmux lock [<repo-dir>]
 
 Interactive Mode (default):
   Just run 'gentleman.dots' to start the TUI installer.
@@ -189,19 +570,57 @@ Interactive Mode (default):
 Non-Interactive Mode:
   gentleman.dots --non-interactive --shell=<shell> [options]
 
+Commands (change an existing installation; flags go before the command):
+  set wm <wm>          Switch multiplexer: tmux, zellij, herdr, none
+  set shell <shell>    Switch shell, keeping the multiplexer: fish, zsh, nushell, bash
+  set theme <theme>    Recolor terminal, multiplexer, prompt and Neovim configs together
+  set layout <layout>  Start Zellij with a layout from ~/.config/zellij/layouts
+  set terminal.<key> <value>
+                       Tune every installed terminal: font-size (6-72), opacity (0-1),
+                       padding (px), blur (0 disables)
+  set herdr.<key> <binding>
+                       Rebind Herdr: prefix, previous_agent, next_agent, focus_agent
+  cache export <file>  Bundle cached downloads and git mirrors for offline machines
+  ghostty shaders      List the Ghostty shaders; set/add/remove/off change the enabled ones
+  herdr update [<version>]
+                       Replace Herdr with a verified release (default: latest)
+  tmux lock [<repo-dir>]
+                       Pin the repo's tmux plugins in GentlemanTmux/tmux-lock.json (default: .)
+
 Flags:
   -h, --help           Show this help message
   -v, --version        Show version information
   -t, --test           Run in test mode (uses temporary directory)
   --dry-run            Show what would be installed without doing it
   --non-interactive    Run without TUI, use CLI flags instead
+  --link               Symlink configs from a persistent clone (stow-style)
+  --repo-dir=<path>    Clone location for --link (default: ~/.local/share/gentleman-dots)
+  --nix                Generate a home-manager module instead of installing (NixOS)
+  --nix-out=<path>     Output directory for --nix (default: ~/.config/home-manager/gentleman)
+  --mirror=<url>       Local package mirror to check for reachability before installing
+  --proxy=<url>        HTTP(S) proxy for every download (default: $HTTPS_PROXY)
+  --no-proxy=<hosts>   Comma-separated hosts that bypass the proxy
+  --ca-bundle=<file>   Extra CA certificates to trust (corporate TLS interception)
+  --github-mirror=<url> Base URL that replaces https://github.com
+  --brew-mirror=<url>  Homebrew bottle mirror
+  --allow-install-scripts
+                       Allow unverifiable vendor installers (Homebrew, rustup, Claude Code, OpenCode)
+  --allow-unpinned     Trust the checksum a release publishes for downloads without a pinned one
+  --cache-dir=<path>   Download cache, or a bundle from 'cache export' (default: ~/.cache/gentleman-dots)
+  --skip-preflight     Continue --non-interactive runs when pre-flight checks fail
 
 Non-Interactive Options:
-  --shell=<shell>      Shell to install (required): fish, zsh, nushell
+  --shell=<shell>      Shell to install (required): fish, zsh, nushell, bash
   --terminal=<term>    Terminal: alacritty, wezterm, kitty, ghostty, none
   --wm=<wm>            Window manager: tmux, zellij, herdr, none
   --nvim               Install Neovim configuration
-  --font               Install Nerd Font
+  --font               Install Nerd Font (Iosevka Term)
+  --nerd-font=<font>   Install another Nerd Font: jetbrains-mono, fira-code, caskaydia-cove, hack
+  --font-version=<v>   nerd-fonts release to download (default: v3.3.0)
+  --theme=<theme>      Color theme: gentleman, kanagawa-wave, kanagawa-dragon, catppuccin-mocha,
+                       catppuccin-macchiato, catppuccin-frappe, catppuccin-latte, tokyonight, rose-pine
+  --herdr-version=<v>  Herdr release: latest or a version (default: 0.7.1); builds with
+                       cargo where no release binary fits (Termux, musl, other CPUs)
   --backup=false       Disable config backup (default: true)
 
 Examples:
@@ -214,6 +633,39 @@ Examples:
   # Test mode with Zsh + Tmux (no terminal, no nvim)
   gentleman.dots --test --non-interactive --shell=zsh --wm=tmux
 
+  # Keep a fork at ~/.local/share/gentleman-dots and symlink configs from it
+  gentleman.dots --non-interactive --link --shell=zsh --wm=tmux
+
+  # NixOS: declare everything in a home-manager module
+  gentleman.dots --non-interactive --nix --shell=zsh --wm=tmux --nvim
+
+  # Switch an existing install from tmux to zellij
+  gentleman.dots set wm zellij
+
+  # Move from Zsh to Fish (old config is backed up first)
+  gentleman.dots set shell fish
+
+  # Switch every tool to Catppuccin Mocha
+  gentleman.dots set theme catppuccin-mocha
+
+  # Open new Zellij sessions with the Sakura layout
+  gentleman.dots set layout work_sakura
+
+  # Bigger font and a more transparent window in every installed terminal
+  gentleman.dots set terminal.font-size 16
+  gentleman.dots set terminal.opacity 0.85
+
+  # Use the tmux prefix in Herdr and jump to agents with Prefix Alt+1-9
+  gentleman.dots set herdr.prefix ctrl+b
+  gentleman.dots set herdr.focus_agent prefix+alt+1..9
+
+  # Starfield background with the Gentleman cursor smear on top
+  gentleman.dots ghostty shaders set starfield cursor_smear_gentleman
+
+  # Seed a bundle online, then provision an offline lab machine from it
+  gentleman.dots cache export gentleman-cache.tar.gz
+  gentleman.dots --cache-dir=gentleman-cache.tar.gz --non-interactive --shell=zsh --wm=tmux
+
   # Verbose output (shows all command logs)
   GENTLEMAN_VERBOSE=1 gentleman.dots --non-interactive --shell=fish --nvim
 
@@ -0,0 +1,205 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Syntax identifies the comment style of a config file holding managed blocks
+type Syntax string
+
+const (
+	SyntaxSh   Syntax = "sh"
+	SyntaxFish Syntax = "fish"
+	SyntaxNu   Syntax = "nu"
+	SyntaxTmux Syntax = "tmux"
+	SyntaxKDL  Syntax = "kdl"
+	SyntaxTOML Syntax = "toml"
+	SyntaxLua  Syntax = "lua"
+)
+
+// blockTag prefixes every marker so blocks from other tools never collide
+const blockTag = "gentleman.dots:"
+
+func (s Syntax) comment() string {
+	switch s {
+	case SyntaxKDL:
+		return "//"
+	case SyntaxLua:
+		return "--"
+	}
+	return "#"
+}
+
+// BlockMarkers returns the begin and end marker lines for a named block
+func BlockMarkers(syntax Syntax, name string) (string, string) {
+	c := syntax.comment()
+	return fmt.Sprintf("%s >>> %s%s >>>", c, blockTag, name),
+		fmt.Sprintf("%s <<< %s%s <<<", c, blockTag, name)
+}
+
+// HasBlock reports whether content contains the named block
+func HasBlock(content string, syntax Syntax, name string) bool {
+	begin, _ := BlockMarkers(syntax, name)
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == begin {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockBody returns the body of the first copy of the named block and
+// whether the block exists at all.
+func BlockBody(content string, syntax Syntax, name string) (string, bool) {
+	begin, end := BlockMarkers(syntax, name)
+	lines := strings.Split(content, "\n")
+	start, stop, err := findBlock(lines, begin, end, 0)
+	if err != nil || start < 0 {
+		return "", false
+	}
+	return strings.Join(lines[start+1:stop], "\n"), true
+}
+
+// findBlock returns the line indexes of the first begin/end marker pair
+// starting at from, or -1 when the block is absent.
+func findBlock(lines []string, begin, end string, from int) (int, int, error) {
+	for i := from; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != begin {
+			continue
+		}
+		for j := i + 1; j < len(lines); j++ {
+			if strings.TrimSpace(lines[j]) == end {
+				return i, j, nil
+			}
+		}
+		return -1, -1, fmt.Errorf("managed block %q is missing its end marker", begin)
+	}
+	return -1, -1, nil
+}
+
+// UpsertBlock replaces the body of the named block, or appends the block
+// when it is missing. Duplicate copies left by older runs are removed so
+// applying the same block any number of times yields the same content.
+func UpsertBlock(content string, syntax Syntax, name, body string) (string, error) {
+	begin, end := BlockMarkers(syntax, name)
+	lines := strings.Split(content, "\n")
+
+	block := []string{begin}
+	if body = strings.TrimRight(body, "\n"); body != "" {
+		block = append(block, strings.Split(body, "\n")...)
+	}
+	block = append(block, end)
+
+	start, stop, err := findBlock(lines, begin, end, 0)
+	if err != nil {
+		return "", err
+	}
+	if start < 0 {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + strings.Join(block, "\n") + "\n", nil
+	}
+
+	out := append([]string{}, lines[:start]...)
+	out = append(out, block...)
+	rest := lines[stop+1:]
+	for {
+		s, e, err := findBlock(rest, begin, end, 0)
+		if err != nil {
+			return "", err
+		}
+		if s < 0 {
+			break
+		}
+		out = append(out, rest[:s]...)
+		rest = rest[e+1:]
+	}
+	out = append(out, rest...)
+	return strings.Join(out, "\n"), nil
+}
+
+// RemoveBlock deletes every copy of the named block, markers included
+func RemoveBlock(content string, syntax Syntax, name string) (string, error) {
+	begin, end := BlockMarkers(syntax, name)
+	lines := strings.Split(content, "\n")
+	var out []string
+	for {
+		s, e, err := findBlock(lines, begin, end, 0)
+		if err != nil {
+			return "", err
+		}
+		if s < 0 {
+			break
+		}
+		out = append(out, lines[:s]...)
+		lines = lines[e+1:]
+	}
+	out = append(out, lines...)
+	return strings.Join(out, "\n"), nil
+}
+
+// UpsertBlockFile applies UpsertBlock to a file, creating it when missing.
+// The file is only rewritten when its content actually changes.
+func UpsertBlockFile(path string, syntax Syntax, name, body string) error {
+	return patchFileBlock(path, syntax, name, body, nil)
+}
+
+// RemoveBlockFile removes the named block from a file if present
+func RemoveBlockFile(path string, syntax Syntax, name string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	updated, err := RemoveBlock(string(data), syntax, name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if updated == string(data) {
+		return nil
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// adoptLegacy rewrites unmarked installer content into an empty managed block.
+// isLegacy reports how many lines starting at i belong to the legacy region
+// (0 when line i is unrelated). The first region becomes the block position
+// and later regions are dropped. Content that already has the block is
+// returned unchanged.
+func adoptLegacy(lines []string, syntax Syntax, name string, isLegacy func(lines []string, i int) int) []string {
+	if HasBlock(strings.Join(lines, "\n"), syntax, name) {
+		return lines
+	}
+	begin, end := BlockMarkers(syntax, name)
+	var out []string
+	placed := false
+	for i := 0; i < len(lines); {
+		n := isLegacy(lines, i)
+		if n == 0 && placed && strings.TrimSpace(lines[i]) == "" {
+			// Blank lines separating two legacy regions belong to the block
+			j := i
+			for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+				j++
+			}
+			if j < len(lines) && isLegacy(lines, j) > 0 {
+				n = j - i
+			}
+		}
+		if n == 0 {
+			out = append(out, lines[i])
+			i++
+			continue
+		}
+		if !placed {
+			out = append(out, begin, end)
+			placed = true
+		}
+		i += n
+	}
+	return out
+}
@@ -0,0 +1,105 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBlockMarkers(t *testing.T) {
+	begin, end := BlockMarkers(SyntaxSh, "homebrew")
+	if begin != "# >>> gentleman.dots:homebrew >>>" || end != "# <<< gentleman.dots:homebrew <<<" {
+		t.Errorf("unexpected sh markers: %q %q", begin, end)
+	}
+	begin, _ = BlockMarkers(SyntaxKDL, "default-shell")
+	if !strings.HasPrefix(begin, "// ") {
+		t.Errorf("KDL markers should use // comments, got %q", begin)
+	}
+}
+
+func TestUpsertBlock(t *testing.T) {
+	t.Run("appends missing block", func(t *testing.T) {
+		got, err := UpsertBlock("alias ll='ls -l'", SyntaxSh, "test", "export A=1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "alias ll='ls -l'\n# >>> gentleman.dots:test >>>\nexport A=1\n# <<< gentleman.dots:test <<<\n"
+		if got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("replaces body in place", func(t *testing.T) {
+		content := "before\n# >>> gentleman.dots:test >>>\nold\n# <<< gentleman.dots:test <<<\nafter\n"
+		got, err := UpsertBlock(content, SyntaxSh, "test", "new")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "before\n# >>> gentleman.dots:test >>>\nnew\n# <<< gentleman.dots:test <<<\nafter\n"
+		if got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("is idempotent", func(t *testing.T) {
+		once, _ := UpsertBlock("x\n", SyntaxFish, "test", "a\nb")
+		twice, _ := UpsertBlock(once, SyntaxFish, "test", "a\nb")
+		if once != twice {
+			t.Errorf("second upsert changed content:\n%s\nvs\n%s", once, twice)
+		}
+	})
+
+	t.Run("removes duplicate blocks", func(t *testing.T) {
+		block := "# >>> gentleman.dots:test >>>\nold\n# <<< gentleman.dots:test <<<\n"
+		got, err := UpsertBlock(block+"middle\n"+block, SyntaxSh, "test", "new")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Count(got, ">>> gentleman.dots:test") != 1 || !strings.Contains(got, "middle") {
+			t.Errorf("expected a single block and surrounding content kept, got:\n%s", got)
+		}
+	})
+
+	t.Run("errors on unterminated block", func(t *testing.T) {
+		if _, err := UpsertBlock("# >>> gentleman.dots:test >>>\nbody\n", SyntaxSh, "test", "new"); err == nil {
+			t.Error("expected error for missing end marker")
+		}
+	})
+}
+
+func TestRemoveBlock(t *testing.T) {
+	content := "a\n// >>> gentleman.dots:test >>>\nx\n// <<< gentleman.dots:test <<<\nb\n"
+	got, err := RemoveBlock(content, SyntaxKDL, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "a\nb\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestUpsertBlockFile_CreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.toml")
+	if err := UpsertBlockFile(path, SyntaxTOML, "test", `key = "value"`); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !HasBlock(string(data), SyntaxTOML, "test") {
+		t.Errorf("expected block in created file, got:\n%s", data)
+	}
+}
+
+func TestBlockBody(t *testing.T) {
+	content := "a\n# >>> gentleman.dots:test >>>\nx\ny\n# <<< gentleman.dots:test <<<\n"
+	body, ok := BlockBody(content, SyntaxSh, "test")
+	if !ok || body != "x\ny" {
+		t.Errorf("got %q, %v", body, ok)
+	}
+	if _, ok := BlockBody("a\n", SyntaxSh, "test"); ok {
+		t.Error("expected missing block to report false")
+	}
+}
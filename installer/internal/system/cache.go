@@ -0,0 +1,297 @@
+package system
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// cacheDir overrides the default cache location (--cache-dir)
+var cacheDir string
+
+// SetCacheDir points every cache (downloads, git mirrors, timings) at dir
+func SetCacheDir(dir string) {
+	cacheDir = dir
+}
+
+// CacheDir is the root shared by all installer caches
+func CacheDir() string {
+	if cacheDir != "" {
+		return cacheDir
+	}
+	return filepath.Join(os.Getenv("HOME"), ".cache", "gentleman-dots")
+}
+
+// bundleEntries are the parts of the cache that make up a portable bundle
+var bundleEntries = []string{"downloads", "git", "index.json"}
+
+var indexMu sync.Mutex
+
+func cacheIndexPath() string {
+	return filepath.Join(CacheDir(), "index.json")
+}
+
+// loadCacheIndex maps artifact URLs to the SHA256 of their verified content
+func loadCacheIndex() map[string]string {
+	index := map[string]string{}
+	if data, err := os.ReadFile(cacheIndexPath()); err == nil {
+		json.Unmarshal(data, &index)
+	}
+	return index
+}
+
+// cachedSHA256 returns the checksum a URL was verified against, if its content is cached
+func cachedSHA256(url string) (string, bool) {
+	indexMu.Lock()
+	sum, ok := loadCacheIndex()[url]
+	indexMu.Unlock()
+	if !ok || !FileExists(filepath.Join(DownloadCacheDir(), sum)) {
+		return "", false
+	}
+	return sum, true
+}
+
+// recordCacheEntry remembers that url resolved to content with the given checksum
+func recordCacheEntry(url, sum string) error {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	index := loadCacheIndex()
+	if index[url] == sum {
+		return nil
+	}
+	index[url] = sum
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := EnsureDir(CacheDir()); err != nil {
+		return err
+	}
+	tmp := cacheIndexPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cacheIndexPath())
+}
+
+// GitMirrorDir is the bare repository cached for a URL
+func GitMirrorDir(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	name := strings.TrimSuffix(path.Base(strings.TrimSuffix(url, "/")), ".git")
+	return filepath.Join(CacheDir(), "git", fmt.Sprintf("%s-%s.git", name, hex.EncodeToString(sum[:])[:12]))
+}
+
+// HasGitMirror reports whether url can be cloned from the cache
+func HasGitMirror(url string) bool {
+	return FileExists(filepath.Join(GitMirrorDir(url), "HEAD"))
+}
+
+// SyncGitMirror creates or refreshes the cached mirror of url. Offline, an
+// existing mirror is used as-is.
+func SyncGitMirror(url string, onLine func(string)) error {
+	if onLine == nil {
+		onLine = func(string) {}
+	}
+	mirror := GitMirrorDir(url)
+	if HasGitMirror(url) {
+		onLine("Updating cached mirror of " + url)
+		if result := RunWithLogs(fmt.Sprintf("git -C %q fetch --prune", mirror), nil, onLine); result.Error != nil {
+			onLine("Warning: could not update the cached mirror, using it as-is")
+		}
+		return nil
+	}
+	if err := EnsureDir(filepath.Dir(mirror)); err != nil {
+		return err
+	}
+	// A bare clone rather than --mirror: GitHub mirrors would drag in every pull request ref
+	result := RunWithLogs(fmt.Sprintf("git clone --bare --progress %s %q", url, mirror), nil, onLine)
+	if result.Error == nil {
+		result = Run(fmt.Sprintf("git -C %q config remote.origin.fetch '+refs/heads/*:refs/heads/*'", mirror), nil)
+	}
+	if result.Error != nil {
+		os.RemoveAll(mirror)
+	}
+	return result.Error
+}
+
+// GitClone clones url into dest through a cached mirror. The mirror is
+// refreshed when the network allows; offline, the cached copy is used as-is.
+func GitClone(url, dest string, onLine func(string)) error {
+	if onLine == nil {
+		onLine = func(string) {}
+	}
+	if err := SyncGitMirror(url, onLine); err != nil {
+		return err
+	}
+	if result := RunWithLogs(fmt.Sprintf("git clone --progress %q %q", GitMirrorDir(url), dest), nil, onLine); result.Error != nil {
+		return result.Error
+	}
+	// Point the working copy at the real remote so later pulls bypass the cache
+	return Run(fmt.Sprintf("git -C %q remote set-url origin %s", dest, url), nil).Error
+}
+
+// ExportCacheBundle writes the cached downloads and git mirrors to a .tar.gz
+// that an offline machine can use with --cache-dir
+func ExportCacheBundle(dest string) error {
+	root := CacheDir()
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	for _, entry := range bundleEntries {
+		base := filepath.Join(root, entry)
+		if !FileExists(base) {
+			continue
+		}
+		err := filepath.Walk(base, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasSuffix(p, ".part") || !(info.Mode().IsRegular() || info.IsDir()) {
+				return nil // skip unfinished downloads and special files
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			hdr, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(rel)
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			src, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer src.Close()
+			_, err = io.Copy(tw, src)
+			return err
+		})
+		if err != nil {
+			os.Remove(dest)
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// ImportCacheBundle unpacks a bundle made by ExportCacheBundle into the cache
+func ImportCacheBundle(src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%s is not a cache bundle: %w", src, err)
+	}
+	defer gz.Close()
+
+	root := CacheDir()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) || !isBundleEntry(name) {
+			return fmt.Errorf("unexpected entry in cache bundle: %s", hdr.Name)
+		}
+		if name == "index.json" {
+			if err := mergeCacheIndex(tr); err != nil {
+				return err
+			}
+			continue
+		}
+		target := filepath.Join(root, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := EnsureDir(target); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := EnsureDir(filepath.Dir(target)); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode).Perm())
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// mergeCacheIndex adds a bundle's index entries to the local index
+func mergeCacheIndex(r io.Reader) error {
+	var imported map[string]string
+	if err := json.NewDecoder(r).Decode(&imported); err != nil {
+		return fmt.Errorf("reading bundle index: %w", err)
+	}
+	for url, sum := range imported {
+		if err := recordCacheEntry(url, sum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isBundleEntry(name string) bool {
+	top := strings.SplitN(filepath.ToSlash(name), "/", 2)[0]
+	for _, entry := range bundleEntries {
+		if top == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// UseCacheDir applies --cache-dir: a directory becomes the cache, a bundle
+// file is unpacked into the default cache first
+func UseCacheDir(p string) error {
+	info, err := os.Stat(p)
+	if err != nil {
+		return fmt.Errorf("cache dir: %w", err)
+	}
+	if info.IsDir() {
+		SetCacheDir(p)
+		return nil
+	}
+	return ImportCacheBundle(p)
+}
@@ -0,0 +1,121 @@
+package system
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDownload_ManifestOffline(t *testing.T) {
+	withUnpinnedAllowed(t)
+	payload := []byte("nerd font")
+	srv, _, _ := artifactServer(t, payload, 0)
+	a := Artifact{URL: srv.URL + "/file.bin", ChecksumManifest: srv.URL + "/SHA-256.txt"}
+
+	if err := Download(a, filepath.Join(t.TempDir(), "first"), nil); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	srv.Close()
+
+	// The index remembers the verified checksum, so neither the manifest nor the file is fetched
+	dest := filepath.Join(t.TempDir(), "second")
+	if err := Download(a, dest, nil); err != nil {
+		t.Fatalf("offline Download: %v", err)
+	}
+	if got, _ := os.ReadFile(dest); string(got) != string(payload) {
+		t.Errorf("unexpected content %q", got)
+	}
+}
+
+func TestCacheBundleRoundTrip(t *testing.T) {
+	withUnpinnedAllowed(t)
+	payload := []byte("herdr binary")
+	srv, _, _ := artifactServer(t, payload, 0)
+	a := Artifact{URL: srv.URL + "/file.bin", ChecksumManifest: srv.URL + "/SHA-256.txt"}
+	if err := Download(a, filepath.Join(t.TempDir(), "herdr"), nil); err != nil {
+		t.Fatal(err)
+	}
+	bundle := filepath.Join(t.TempDir(), "cache.tar.gz")
+	if err := ExportCacheBundle(bundle); err != nil {
+		t.Fatalf("ExportCacheBundle: %v", err)
+	}
+	srv.Close()
+
+	// A fresh machine without network
+	t.Setenv("HOME", t.TempDir())
+	if err := UseCacheDir(bundle); err != nil {
+		t.Fatalf("UseCacheDir: %v", err)
+	}
+	dest := filepath.Join(t.TempDir(), "herdr")
+	if err := Download(a, dest, nil); err != nil {
+		t.Fatalf("Download from bundle: %v", err)
+	}
+
+	// A directory is used in place
+	dir := t.TempDir()
+	t.Cleanup(func() { SetCacheDir("") })
+	if err := UseCacheDir(dir); err != nil || CacheDir() != dir {
+		t.Errorf("expected %s as cache dir, got %s (err=%v)", dir, CacheDir(), err)
+	}
+}
+
+func TestImportCacheBundle_RejectsForeignEntries(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, name := range []string{"../escape", "downloads/../../escape", ".bashrc"} {
+		bundle := filepath.Join(t.TempDir(), "evil.tar.gz")
+		f, _ := os.Create(bundle)
+		gz := gzip.NewWriter(f)
+		tw := tar.NewWriter(gz)
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 2, Typeflag: tar.TypeReg})
+		tw.Write([]byte("hi"))
+		tw.Close()
+		gz.Close()
+		f.Close()
+
+		if err := ImportCacheBundle(bundle); err == nil || !strings.Contains(err.Error(), "unexpected entry") {
+			t.Errorf("%s: expected rejection, got %v", name, err)
+		}
+	}
+}
+
+func TestGitClone_UsesMirror(t *testing.T) {
+	if !CommandExists("git") {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	upstream := filepath.Join(t.TempDir(), "tpm")
+	os.MkdirAll(upstream, 0755)
+	os.WriteFile(filepath.Join(upstream, "tpm"), []byte("#!/bin/sh\n"), 0755)
+	if r := Run("git init -q "+upstream+" && git -C "+upstream+" add . && git -C "+upstream+" commit -qm init", nil); r.Error != nil {
+		t.Fatalf("creating upstream: %v", r.Error)
+	}
+
+	first := filepath.Join(t.TempDir(), "first")
+	if err := GitClone(upstream, first, nil); err != nil {
+		t.Fatalf("GitClone: %v", err)
+	}
+	if !HasGitMirror(upstream) {
+		t.Fatal("expected a cached mirror")
+	}
+	if r := Run("git -C "+first+" remote get-url origin", nil); strings.TrimSpace(r.Output) != upstream {
+		t.Errorf("expected origin to point upstream, got %q", r.Output)
+	}
+
+	// Upstream gone: the clone still works from the cache
+	os.RemoveAll(upstream)
+	second := filepath.Join(t.TempDir(), "second")
+	if err := GitClone(upstream, second, nil); err != nil {
+		t.Fatalf("offline GitClone: %v", err)
+	}
+	if !FileExists(filepath.Join(second, "tpm")) {
+		t.Error("expected the cached content in the clone")
+	}
+}
@@ -16,6 +16,9 @@ const (
 	OSDebian  // Debian-based (Debian, Ubuntu, etc.)
 	OSFedora  // Fedora/RHEL-based (Fedora, CentOS, RHEL, etc.)
 	OSTermux  // Termux on Android
+	OSSUSE    // openSUSE/SLES (zypper)
+	OSAlpine  // Alpine Linux (apk, musl libc)
+	OSVoid    // Void Linux (xbps, glibc or musl)
 	OSUnknown
 )
 
@@ -31,6 +34,21 @@ type SystemInfo struct {
 	HasXcode  bool
 	UserShell string
 	Prefix    string // Termux $PREFIX or empty for other systems
+
+	// Linux distro details from /etc/os-release (empty elsewhere)
+	DistroID      string       // os-release ID, e.g. "manjaro"
+	DistroName    string       // os-release PRETTY_NAME
+	DistroVersion string       // os-release VERSION_ID
+	DistroFamily  DistroFamily // arch, debian, fedora, suse, alpine, void, nixos
+
+	// Capability probes
+	PackageManagers []PackageManager // every known package manager on PATH
+	Libc            string           // "glibc", "musl", "bionic" (Termux) or empty on macOS
+	IsContainer     bool
+	IsVM            bool
+	IsProot         bool // a distro run by proot, e.g. proot-distro inside Termux
+	HasSudo         bool
+	IsRoot          bool
 }
 
 func Detect() *SystemInfo {
@@ -42,14 +60,17 @@ func Detect() *SystemInfo {
 		Prefix:  os.Getenv("PREFIX"),
 	}
 
-	// Check for Termux FIRST (it runs on Linux but is special)
-	if isTermux() {
+	// Check for Termux FIRST (it runs on Linux but is special). A distro run
+	// by proot inside Termux still sees Termux paths but is a regular Linux.
+	if isTermux() && !detectProot() {
 		info.OS = OSTermux
 		info.OSName = "Termux"
 		info.IsTermux = true
 		info.HasPkg = checkPkg()
 		info.HasBrew = false // Termux doesn't use Homebrew
 		info.UserShell = detectCurrentShell()
+		info.Libc = "bionic"
+		info.HasSudo = CommandExists("sudo")
 		return info
 	}
 
@@ -63,24 +84,66 @@ func Detect() *SystemInfo {
 		info.OSName = "Linux"
 		info.IsWSL = checkWSL()
 
-		if isArchLinux() {
+		if release, ok := readOSRelease(); ok {
+			info.DistroID = release.ID
+			info.DistroName = release.PrettyName
+			info.DistroVersion = release.VersionID
+			info.DistroFamily = release.Family()
+		}
+		// Distros without a usable os-release still ship their release file
+		if info.DistroFamily == FamilyUnknown {
+			info.DistroFamily = legacyDistroFamily()
+		}
+
+		switch info.DistroFamily {
+		case FamilyArch:
 			info.OS = OSArch
 			info.OSName = "Arch Linux"
-		} else if isFedora() {
+		case FamilyFedora:
 			info.OS = OSFedora
 			info.OSName = "Fedora/RHEL"
-		} else if isDebian() {
+		case FamilyDebian:
 			info.OS = OSDebian
 			info.OSName = "Debian/Ubuntu"
+		case FamilySUSE:
+			info.OS = OSSUSE
+			info.OSName = "openSUSE"
+		case FamilyAlpine:
+			info.OS = OSAlpine
+			info.OSName = "Alpine Linux"
+		case FamilyVoid:
+			info.OS = OSVoid
+			info.OSName = "Void Linux"
 		}
+
+		info.Libc = detectLibc()
+		info.IsContainer = detectContainer()
+		info.IsVM = detectVM()
+		info.IsProot = detectProot()
 	}
 
+	info.PackageManagers = detectPackageManagers()
+	info.HasSudo = CommandExists("sudo")
+	info.IsRoot = os.Geteuid() == 0
 	info.HasBrew = checkBrew()
 	info.UserShell = detectCurrentShell()
 
 	return info
 }
 
+// legacyDistroFamily classifies distros by their release marker files
+func legacyDistroFamily() DistroFamily {
+	switch {
+	case isArchLinux():
+		return FamilyArch
+	case isFedora():
+		return FamilyFedora
+	case isDebian():
+		return FamilyDebian
+	}
+	return FamilyUnknown
+}
+
 func checkWSL() bool {
 	data, err := os.ReadFile("/proc/version")
 	if err != nil {
@@ -16,7 +16,7 @@ func TestOSTypes(t *testing.T) {
 	})
 
 	t.Run("all OS types should be distinct", func(t *testing.T) {
-		osTypes := []OSType{OSMac, OSLinux, OSArch, OSDebian, OSFedora, OSTermux, OSUnknown}
+		osTypes := []OSType{OSMac, OSLinux, OSArch, OSDebian, OSFedora, OSTermux, OSSUSE, OSAlpine, OSVoid, OSUnknown}
 		seen := make(map[OSType]bool)
 		for _, ot := range osTypes {
 			if seen[ot] {
@@ -56,7 +56,7 @@ func TestDetect(t *testing.T) {
 				t.Errorf("Expected OSName to be 'macOS', got '%s'", info.OSName)
 			}
 		case "linux":
-			validNames := []string{"Linux", "Arch Linux", "Debian/Ubuntu", "Fedora/RHEL", "Termux"}
+			validNames := []string{"Linux", "Arch Linux", "Debian/Ubuntu", "Fedora/RHEL", "openSUSE", "Alpine Linux", "Void Linux", "Termux"}
 			found := false
 			for _, name := range validNames {
 				if info.OSName == name {
@@ -69,6 +69,21 @@ func TestDetect(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("Linux should report distro and capabilities", func(t *testing.T) {
+		if runtime.GOOS != "linux" || info.IsTermux {
+			t.Skip("Linux only")
+		}
+		if info.Libc != "glibc" && info.Libc != "musl" {
+			t.Errorf("Unexpected libc: %q", info.Libc)
+		}
+		if _, err := os.Stat("/etc/os-release"); err == nil && info.DistroID == "" {
+			t.Error("DistroID should be read from /etc/os-release")
+		}
+		if info.HasSudo != CommandExists("sudo") {
+			t.Error("HasSudo should match sudo on PATH")
+		}
+	})
 }
 
 func TestCommandExists(t *testing.T) {
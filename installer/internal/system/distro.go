@@ -0,0 +1,273 @@
+package system
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// OSRelease holds the /etc/os-release fields used to identify a distro
+type OSRelease struct {
+	ID         string
+	IDLike     []string
+	VersionID  string
+	PrettyName string
+}
+
+// DistroFamily groups distros that share a package manager and package names
+type DistroFamily string
+
+const (
+	FamilyUnknown DistroFamily = ""
+	FamilyArch    DistroFamily = "arch"
+	FamilyDebian  DistroFamily = "debian"
+	FamilyFedora  DistroFamily = "fedora"
+	FamilySUSE    DistroFamily = "suse"
+	FamilyAlpine  DistroFamily = "alpine"
+	FamilyVoid    DistroFamily = "void"
+	FamilyNixOS   DistroFamily = "nixos"
+)
+
+// PackageManager names a system package manager found on PATH
+type PackageManager string
+
+const (
+	PkgPacman PackageManager = "pacman"
+	PkgDnf    PackageManager = "dnf"
+	PkgApt    PackageManager = "apt"
+	PkgZypper PackageManager = "zypper"
+	PkgApk    PackageManager = "apk"
+	PkgXbps   PackageManager = "xbps"
+	PkgNix    PackageManager = "nix"
+)
+
+// packageManagerBinaries maps each package manager to the binary that proves it exists
+var packageManagerBinaries = []struct {
+	manager PackageManager
+	binary  string
+}{
+	{PkgPacman, "pacman"},
+	{PkgDnf, "dnf"},
+	{PkgApt, "apt-get"},
+	{PkgZypper, "zypper"},
+	{PkgApk, "apk"},
+	{PkgXbps, "xbps-install"},
+	{PkgNix, "nix"},
+}
+
+// familyIDs maps os-release IDs (and ID_LIKE entries) to their family
+var familyIDs = map[string]DistroFamily{
+	"arch":                FamilyArch,
+	"manjaro":             FamilyArch,
+	"endeavouros":         FamilyArch,
+	"garuda":              FamilyArch,
+	"artix":               FamilyArch,
+	"cachyos":             FamilyArch,
+	"debian":              FamilyDebian,
+	"ubuntu":              FamilyDebian,
+	"pop":                 FamilyDebian,
+	"linuxmint":           FamilyDebian,
+	"elementary":          FamilyDebian,
+	"raspbian":            FamilyDebian,
+	"kali":                FamilyDebian,
+	"fedora":              FamilyFedora,
+	"rhel":                FamilyFedora,
+	"centos":              FamilyFedora,
+	"rocky":               FamilyFedora,
+	"almalinux":           FamilyFedora,
+	"ol":                  FamilyFedora,
+	"amzn":                FamilyFedora,
+	"suse":                FamilySUSE,
+	"opensuse":            FamilySUSE,
+	"opensuse-tumbleweed": FamilySUSE,
+	"opensuse-leap":       FamilySUSE,
+	"sles":                FamilySUSE,
+	"alpine":              FamilyAlpine,
+	"postmarketos":        FamilyAlpine,
+	"void":                FamilyVoid,
+	"nixos":               FamilyNixOS,
+}
+
+// osReleasePaths lists where os-release may live, in lookup order
+var osReleasePaths = []string{"/etc/os-release", "/usr/lib/os-release"}
+
+// ParseOSRelease parses the KEY=value format of os-release(5)
+func ParseOSRelease(content string) OSRelease {
+	var release OSRelease
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = unquoteOSReleaseValue(value)
+		switch key {
+		case "ID":
+			release.ID = strings.ToLower(value)
+		case "ID_LIKE":
+			release.IDLike = strings.Fields(strings.ToLower(value))
+		case "VERSION_ID":
+			release.VersionID = value
+		case "PRETTY_NAME":
+			release.PrettyName = value
+		}
+	}
+	return release
+}
+
+func unquoteOSReleaseValue(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		return value[1 : len(value)-1]
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// Family resolves the distro family from ID first, then ID_LIKE in order
+func (r OSRelease) Family() DistroFamily {
+	if family, ok := familyIDs[r.ID]; ok {
+		return family
+	}
+	if strings.HasPrefix(r.ID, "opensuse") {
+		return FamilySUSE
+	}
+	for _, like := range r.IDLike {
+		if family, ok := familyIDs[like]; ok {
+			return family
+		}
+	}
+	return FamilyUnknown
+}
+
+// readOSRelease loads the first os-release file that exists
+func readOSRelease() (OSRelease, bool) {
+	for _, path := range osReleasePaths {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return ParseOSRelease(string(data)), true
+		}
+	}
+	return OSRelease{}, false
+}
+
+// detectPackageManagers returns every known package manager on PATH
+func detectPackageManagers() []PackageManager {
+	var managers []PackageManager
+	for _, pm := range packageManagerBinaries {
+		if _, err := exec.LookPath(pm.binary); err == nil {
+			managers = append(managers, pm.manager)
+		}
+	}
+	return managers
+}
+
+// HasPackageManager reports whether pm was found during detection
+func (s *SystemInfo) HasPackageManager(pm PackageManager) bool {
+	for _, m := range s.PackageManagers {
+		if m == pm {
+			return true
+		}
+	}
+	return false
+}
+
+// detectLibc reports "musl" or "glibc" for the running Linux system
+func detectLibc() string {
+	if matches, _ := filepath.Glob("/lib/ld-musl-*.so.1"); len(matches) > 0 {
+		return "musl"
+	}
+	out, _ := exec.Command("ldd", "--version").CombinedOutput()
+	return libcFromLdd(string(out))
+}
+
+// libcFromLdd classifies `ldd --version` output (musl prints to stderr and exits 1)
+func libcFromLdd(output string) string {
+	if strings.Contains(strings.ToLower(output), "musl") {
+		return "musl"
+	}
+	return "glibc"
+}
+
+// detectContainer reports whether we run inside a container
+func detectContainer() bool {
+	if os.Getenv("container") != "" {
+		return true
+	}
+	for _, marker := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	data, err := os.ReadFile("/proc/1/cgroup")
+	return err == nil && cgroupIsContainer(string(data))
+}
+
+func cgroupIsContainer(content string) bool {
+	for _, marker := range []string{"docker", "kubepods", "containerd", "lxc", "libpod"} {
+		if strings.Contains(content, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectProot reports whether we run under proot, e.g. a proot-distro
+// desktop started from Termux: proot traces every process it runs
+func detectProot() bool {
+	data, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return false
+	}
+	pid := tracerPid(string(data))
+	if pid == "" {
+		return false
+	}
+	comm, err := os.ReadFile("/proc/" + pid + "/comm")
+	return err == nil && strings.HasPrefix(strings.TrimSpace(string(comm)), "proot")
+}
+
+// tracerPid returns the TracerPid of a /proc/<pid>/status, "" when untraced
+func tracerPid(status string) string {
+	for _, line := range strings.Split(status, "\n") {
+		if value, ok := strings.CutPrefix(line, "TracerPid:"); ok {
+			if value = strings.TrimSpace(value); value != "0" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// detectVM reports whether the machine is a virtual machine
+func detectVM() bool {
+	for _, path := range []string{"/sys/class/dmi/id/product_name", "/sys/class/dmi/id/sys_vendor"} {
+		data, err := os.ReadFile(path)
+		if err == nil && dmiIsVM(string(data)) {
+			return true
+		}
+	}
+	data, err := os.ReadFile("/proc/cpuinfo")
+	return err == nil && strings.Contains(string(data), " hypervisor")
+}
+
+func dmiIsVM(content string) bool {
+	content = strings.ToLower(content)
+	for _, marker := range []string{"qemu", "kvm", "virtualbox", "vmware", "virtual machine", "xen", "parallels", "bochs"} {
+		if strings.Contains(content, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseOSRelease(t *testing.T) {
+	content := `# comment
+NAME="Rocky Linux"
+ID="rocky"
+ID_LIKE="rhel centos fedora"
+VERSION_ID='9.3'
+PRETTY_NAME="Rocky Linux 9.3 (Blue Onyx)"
+ANSI_COLOR="0;32"
+`
+	got := ParseOSRelease(content)
+	want := OSRelease{
+		ID:         "rocky",
+		IDLike:     []string{"rhel", "centos", "fedora"},
+		VersionID:  "9.3",
+		PrettyName: "Rocky Linux 9.3 (Blue Onyx)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestOSReleaseFamily(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    DistroFamily
+	}{
+		{"Arch", "ID=arch", FamilyArch},
+		{"Manjaro", "ID=manjaro\nID_LIKE=arch", FamilyArch},
+		{"EndeavourOS", "ID=endeavouros\nID_LIKE=arch", FamilyArch},
+		{"Ubuntu", "ID=ubuntu\nID_LIKE=debian", FamilyDebian},
+		{"Pop!_OS", "ID=pop\nID_LIKE=\"ubuntu debian\"", FamilyDebian},
+		{"Fedora", "ID=fedora", FamilyFedora},
+		{"Rocky", "ID=\"rocky\"\nID_LIKE=\"rhel centos fedora\"", FamilyFedora},
+		{"openSUSE Tumbleweed", "ID=\"opensuse-tumbleweed\"\nID_LIKE=\"opensuse suse\"", FamilySUSE},
+		{"openSUSE MicroOS", "ID=\"opensuse-microos\"", FamilySUSE},
+		{"Alpine", "ID=alpine", FamilyAlpine},
+		{"Void", "ID=\"void\"", FamilyVoid},
+		{"NixOS", "ID=nixos", FamilyNixOS},
+		{"unknown derivative falls back to ID_LIKE", "ID=myos\nID_LIKE=\"ubuntu\"", FamilyDebian},
+		{"unknown", "ID=plan9", FamilyUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseOSRelease(tt.content).Family(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOSRelease(t *testing.T) {
+	original := osReleasePaths
+	t.Cleanup(func() { osReleasePaths = original })
+
+	dir := t.TempDir()
+	fallback := filepath.Join(dir, "usr-lib-os-release")
+	os.WriteFile(fallback, []byte("ID=void\n"), 0644)
+	osReleasePaths = []string{filepath.Join(dir, "missing"), fallback}
+
+	release, ok := readOSRelease()
+	if !ok || release.ID != "void" {
+		t.Errorf("expected fallback os-release to be read, got %+v, %v", release, ok)
+	}
+
+	osReleasePaths = []string{filepath.Join(dir, "missing")}
+	if _, ok := readOSRelease(); ok {
+		t.Error("expected no os-release")
+	}
+}
+
+func TestLibcFromLdd(t *testing.T) {
+	if got := libcFromLdd("musl libc (x86_64)\nVersion 1.2.4"); got != "musl" {
+		t.Errorf("expected musl, got %q", got)
+	}
+	if got := libcFromLdd("ldd (Debian GLIBC 2.36-9) 2.36"); got != "glibc" {
+		t.Errorf("expected glibc, got %q", got)
+	}
+}
+
+func TestContainerAndVMMarkers(t *testing.T) {
+	if !cgroupIsContainer("0::/system.slice/docker-abc.scope") {
+		t.Error("docker cgroup should be a container")
+	}
+	if cgroupIsContainer("0::/init.scope") {
+		t.Error("init.scope should not be a container")
+	}
+	if !dmiIsVM("QEMU Standard PC (Q35 + ICH9, 2009)\n") || !dmiIsVM("VMware, Inc.") {
+		t.Error("expected hypervisor DMI strings to be VMs")
+	}
+	if dmiIsVM("ThinkPad X1 Carbon Gen 11") {
+		t.Error("bare-metal product should not be a VM")
+	}
+}
+
+func TestTracerPid(t *testing.T) {
+	if got := tracerPid("Name:\tbash\nState:\tS (sleeping)\nTracerPid:\t4242\n"); got != "4242" {
+		t.Errorf("expected 4242, got %q", got)
+	}
+	if got := tracerPid("Name:\tbash\nTracerPid:\t0\n"); got != "" {
+		t.Errorf("untraced process should have no tracer, got %q", got)
+	}
+}
+
+func TestHasPackageManager(t *testing.T) {
+	info := &SystemInfo{PackageManagers: []PackageManager{PkgApt, PkgNix}}
+	if !info.HasPackageManager(PkgNix) || info.HasPackageManager(PkgPacman) {
+		t.Errorf("unexpected package managers: %v", info.PackageManagers)
+	}
+}
@@ -0,0 +1,419 @@
+package system
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Artifact is a binary or archive fetched from the network, verified against
+// the SHA256 pinned in the installer. The checksum manifest or GitHub digests
+// published with the release are only used with --allow-unpinned: they come
+// from the same origin as the file, so they catch corruption, not tampering.
+type Artifact struct {
+	URL              string
+	SHA256           string
+	ChecksumManifest string // URL of a "<sha256>  <file>" list, e.g. nerd-fonts' SHA-256.txt
+	ReleaseAPI       string // GitHub API URL of the release whose asset digests list the file
+}
+
+// DownloadProgress reports bytes received; total is -1 when unknown
+type DownloadProgress func(done, total int64)
+
+// ErrUnpinned is returned for artifacts without a checksum source
+var ErrUnpinned = errors.New("artifact has no pinned checksum")
+
+// ErrUnpinnedRefused is returned for artifacts without a pinned SHA256 unless
+// --allow-unpinned trusts the checksum their release publishes
+var ErrUnpinnedRefused = errors.New("no pinned checksum (use --allow-unpinned to trust the checksum the release publishes)")
+
+// allowUnpinned permits verifying artifacts against their release's own checksums
+var allowUnpinned bool
+
+// SetAllowUnpinned sets the policy for artifacts without a pinned SHA256
+func SetAllowUnpinned(allow bool) {
+	allowUnpinned = allow
+}
+
+// UnpinnedAllowed reports whether artifacts without a pinned SHA256 may be installed
+func UnpinnedAllowed() bool {
+	return allowUnpinned
+}
+
+var (
+	downloadAttempts = 4
+	downloadBackoff  = time.Second // doubled after every failed attempt
+)
+
+// DownloadCacheDir holds verified downloads, named by their SHA256
+func DownloadCacheDir() string {
+	return filepath.Join(CacheDir(), "downloads")
+}
+
+// Download fetches a into dest after verifying its checksum. Verified files
+// are cached, interrupted transfers resume, and failures are retried with backoff.
+func Download(a Artifact, dest string, progress DownloadProgress) error {
+	want, err := a.expectedSHA256()
+	if err != nil {
+		return err
+	}
+
+	cached := filepath.Join(DownloadCacheDir(), want)
+	if got, err := fileSHA256(cached); err == nil && got == want {
+		if progress != nil {
+			if fi, err := os.Stat(cached); err == nil {
+				progress(fi.Size(), fi.Size())
+			}
+		}
+		if err := recordCacheEntry(a.URL, want); err != nil {
+			return err
+		}
+		return copyAtomic(cached, dest)
+	}
+
+	if err := EnsureDir(DownloadCacheDir()); err != nil {
+		return err
+	}
+	part := cached + ".part"
+	if err := fetchWithRetry(GitHubURL(a.URL), part, progress); err != nil {
+		return fmt.Errorf("downloading %s: %w", a.URL, err)
+	}
+
+	got, err := fileSHA256(part)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		os.Remove(part)
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", a.URL, want, got)
+	}
+	if err := os.Rename(part, cached); err != nil {
+		return err
+	}
+	if err := recordCacheEntry(a.URL, want); err != nil {
+		return err
+	}
+	return copyAtomic(cached, dest)
+}
+
+// expectedSHA256 resolves the pinned checksum; with --allow-unpinned, the
+// one the release publishes
+func (a Artifact) expectedSHA256() (string, error) {
+	if a.SHA256 != "" {
+		return strings.ToLower(a.SHA256), nil
+	}
+	if a.ChecksumManifest == "" && a.ReleaseAPI == "" {
+		return "", fmt.Errorf("%s: %w", a.URL, ErrUnpinned)
+	}
+	if !allowUnpinned {
+		return "", fmt.Errorf("%s: %w", a.URL, ErrUnpinnedRefused)
+	}
+	// Verified once against the manifest; reuse that offline
+	if sum, ok := cachedSHA256(a.URL); ok {
+		return sum, nil
+	}
+	if a.ReleaseAPI != "" {
+		return a.releaseDigest()
+	}
+	// From the origin, never the mirror that serves the file itself
+	manifest, err := fetchBytes(a.ChecksumManifest)
+	if err != nil {
+		return "", fmt.Errorf("fetching checksum manifest: %w", err)
+	}
+	name := path.Base(a.URL)
+	scanner := bufio.NewScanner(bytes.NewReader(manifest))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && strings.TrimPrefix(fields[len(fields)-1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s is not listed in %s: %w", name, a.ChecksumManifest, ErrUnpinned)
+}
+
+// releaseDigest reads the "sha256:<hex>" digest GitHub publishes for the asset
+func (a Artifact) releaseDigest() (string, error) {
+	release, err := FetchRelease(a.ReleaseAPI)
+	if err != nil {
+		return "", err
+	}
+	name := path.Base(a.URL)
+	sum, ok := release.Assets[name]
+	if !ok {
+		return "", fmt.Errorf("%s is not an asset of %s: %w", name, a.ReleaseAPI, ErrUnpinned)
+	}
+	if sum == "" {
+		return "", fmt.Errorf("%s has no sha256 digest in %s: %w", name, a.ReleaseAPI, ErrUnpinned)
+	}
+	return sum, nil
+}
+
+// Release is a GitHub release: its tag and the SHA256 GitHub records for
+// each asset, "" for assets published without a digest
+type Release struct {
+	Tag    string
+	Assets map[string]string
+}
+
+// FetchRelease reads a release from the GitHub API, e.g.
+// https://api.github.com/repos/<owner>/<repo>/releases/latest. The digests it
+// lists are read from GitHub itself, never through --github-mirror
+func FetchRelease(api string) (Release, error) {
+	data, err := fetchBytes(api)
+	if err != nil {
+		return Release{}, fmt.Errorf("fetching release metadata: %w", err)
+	}
+	var release struct {
+		Tag    string `json:"tag_name"`
+		Assets []struct {
+			Name   string `json:"name"`
+			Digest string `json:"digest"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(data, &release); err != nil {
+		return Release{}, fmt.Errorf("parsing release metadata: %w", err)
+	}
+	r := Release{Tag: release.Tag, Assets: map[string]string{}}
+	for _, asset := range release.Assets {
+		sum, ok := strings.CutPrefix(asset.Digest, "sha256:")
+		if !ok || len(sum) != sha256.Size*2 {
+			sum = ""
+		}
+		r.Assets[asset.Name] = strings.ToLower(sum)
+	}
+	return r, nil
+}
+
+// httpStatusError is a non-success response; only server errors are retried
+type httpStatusError struct {
+	code int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP %d %s", e.code, http.StatusText(e.code))
+}
+
+func retryable(err error) bool {
+	var status *httpStatusError
+	if errors.As(err, &status) {
+		return status.code >= 500 || status.code == http.StatusRequestTimeout || status.code == http.StatusTooManyRequests
+	}
+	return true
+}
+
+func fetchWithRetry(url, part string, progress DownloadProgress) error {
+	return retryLoop(func() error { return fetchOnce(url, part, progress) })
+}
+
+// fetchOnce downloads url into part, resuming from whatever part already holds
+func fetchOnce(url, part string, progress DownloadProgress) error {
+	var offset int64
+	if fi, err := os.Stat(part); err == nil {
+		offset = fi.Size()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := HTTPClient(30 * time.Minute).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		return nil // already complete; the checksum decides
+	case resp.StatusCode == http.StatusOK:
+		flags |= os.O_TRUNC // server ignored the range, start over
+		offset = 0
+	default:
+		return &httpStatusError{code: resp.StatusCode}
+	}
+
+	f, err := os.OpenFile(part, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	w := &progressWriter{done: offset, total: total, report: progress}
+	if _, err := io.Copy(f, io.TeeReader(resp.Body, w)); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// fetchBytes reads a small resource such as a checksum manifest or install script
+func fetchBytes(url string) ([]byte, error) {
+	var body []byte
+	err := retryLoop(func() error {
+		resp, err := HTTPClient(time.Minute).Get(url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return &httpStatusError{code: resp.StatusCode}
+		}
+		body, err = io.ReadAll(resp.Body)
+		return err
+	})
+	return body, err
+}
+
+// retryLoop runs fn until it succeeds, fails permanently, or runs out of attempts
+func retryLoop(fn func() error) error {
+	var err error
+	backoff := downloadBackoff
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		if err = fn(); err == nil || !retryable(err) {
+			return err
+		}
+		if attempt < downloadAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return err
+}
+
+type progressWriter struct {
+	done, total int64
+	report      DownloadProgress
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.done += int64(len(p))
+	if w.report != nil {
+		w.report(w.done, w.total)
+	}
+	return len(p), nil
+}
+
+func fileSHA256(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyAtomic copies src over dest without leaving a half-written file behind
+func copyAtomic(src, dest string) error {
+	if err := EnsureDir(filepath.Dir(dest)); err != nil {
+		return err
+	}
+	tmp := dest + ".download"
+	if err := CopyFile(src, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+// ReplaceBinary installs src as the executable dest. The copy is made
+// executable next to dest and renamed over it, so a failed update leaves the
+// old binary and running processes keep theirs.
+func ReplaceBinary(src, dest string) error {
+	if err := EnsureDir(filepath.Dir(dest)); err != nil {
+		return err
+	}
+	tmp := dest + ".new"
+	if err := CopyFile(src, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chmod(tmp, 0755); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+// allowInstallScripts permits vendor `curl | sh` installers, which cannot be pinned
+var allowInstallScripts bool
+
+// SetAllowInstallScripts sets the policy for unverifiable install scripts
+func SetAllowInstallScripts(allow bool) {
+	allowInstallScripts = allow
+}
+
+// InstallScriptsAllowed reports whether unverifiable install scripts may run
+func InstallScriptsAllowed() bool {
+	return allowInstallScripts
+}
+
+// ErrInstallScriptRefused is returned when the policy blocks an install script
+var ErrInstallScriptRefused = errors.New("install scripts are not allowed (use --allow-install-scripts)")
+
+// CheckInstallScript gates a vendor install script behind the policy
+func CheckInstallScript(name string) error {
+	if allowInstallScripts {
+		return nil
+	}
+	return fmt.Errorf("%s installer: %w", name, ErrInstallScriptRefused)
+}
+
+// ErrNotInArchive is returned when a zip archive lacks the requested entry
+var ErrNotInArchive = errors.New("not found in archive")
+
+// ExtractZipFile writes the archive entry whose base name is name to dest
+func ExtractZipFile(archive, name, dest string) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		if path.Base(f.Name) != name || f.FileInfo().IsDir() {
+			continue
+		}
+		src, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		if err := EnsureDir(filepath.Dir(dest)); err != nil {
+			return err
+		}
+		out, err := os.Create(dest)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, src); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}
+	return fmt.Errorf("%s in %s: %w", name, filepath.Base(archive), ErrNotInArchive)
+}
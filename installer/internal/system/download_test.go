@@ -0,0 +1,280 @@
+package system
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// artifactServer serves payload at /file.bin, honoring Range requests
+func artifactServer(t *testing.T, payload []byte, failures int) (*httptest.Server, *int32, *atomic.Value) {
+	t.Helper()
+	var hits int32
+	var lastRange atomic.Value
+	lastRange.Store("")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+		if int(n) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/file.bin":
+			lastRange.Store(r.Header.Get("Range"))
+			http.ServeContent(w, r, "file.bin", time.Time{}, bytes.NewReader(payload))
+		case "/SHA-256.txt":
+			w.Write([]byte(sha256Hex(payload) + "  file.bin\n"))
+		case "/release":
+			fmt.Fprintf(w, `{"tag_name":"v1.2.0","assets":[{"name":"file.bin","digest":"sha256:%s"},{"name":"old.bin","digest":null}]}`, sha256Hex(payload))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv("HOME", t.TempDir())
+	original := downloadBackoff
+	downloadBackoff = 0
+	t.Cleanup(func() { downloadBackoff = original })
+	return srv, &hits, &lastRange
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownload_VerifiesAndCaches(t *testing.T) {
+	payload := bytes.Repeat([]byte("gentleman"), 1000)
+	srv, hits, _ := artifactServer(t, payload, 0)
+	dest := filepath.Join(t.TempDir(), "out", "file.bin")
+
+	var lastDone, lastTotal int64
+	err := Download(Artifact{URL: srv.URL + "/file.bin", SHA256: sha256Hex(payload)}, dest, func(done, total int64) {
+		lastDone, lastTotal = done, total
+	})
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Error("downloaded content differs")
+	}
+	if lastDone != int64(len(payload)) || lastTotal != int64(len(payload)) {
+		t.Errorf("expected final progress %d/%d, got %d/%d", len(payload), len(payload), lastDone, lastTotal)
+	}
+
+	// Second download comes from the cache
+	os.Remove(dest)
+	if err := Download(Artifact{URL: srv.URL + "/file.bin", SHA256: sha256Hex(payload)}, dest, nil); err != nil {
+		t.Fatalf("cached Download: %v", err)
+	}
+	if *hits != 1 {
+		t.Errorf("expected one request, got %d", *hits)
+	}
+}
+
+func TestDownload_ChecksumMismatch(t *testing.T) {
+	srv, _, _ := artifactServer(t, []byte("tampered"), 0)
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	err := Download(Artifact{URL: srv.URL + "/file.bin", SHA256: sha256Hex([]byte("original"))}, dest, nil)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if FileExists(dest) {
+		t.Error("unverified file must not reach its destination")
+	}
+	entries, _ := os.ReadDir(DownloadCacheDir())
+	if len(entries) != 0 {
+		t.Errorf("unverified data left in cache: %v", entries)
+	}
+}
+
+func TestDownload_RequiresChecksum(t *testing.T) {
+	err := Download(Artifact{URL: "https://example.com/tool.tar.gz"}, filepath.Join(t.TempDir(), "tool"), nil)
+	if !errors.Is(err, ErrUnpinned) {
+		t.Errorf("expected ErrUnpinned, got %v", err)
+	}
+}
+
+// withUnpinnedAllowed opts into the checksums releases publish for the test
+func withUnpinnedAllowed(t *testing.T) {
+	t.Helper()
+	SetAllowUnpinned(true)
+	t.Cleanup(func() { SetAllowUnpinned(false) })
+}
+
+func TestDownload_RefusesUnpinned(t *testing.T) {
+	payload := []byte("font archive")
+	srv, hits, _ := artifactServer(t, payload, 0)
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	for _, a := range []Artifact{
+		{URL: srv.URL + "/file.bin", ChecksumManifest: srv.URL + "/SHA-256.txt"},
+		{URL: srv.URL + "/file.bin", ReleaseAPI: srv.URL + "/release"},
+	} {
+		if err := Download(a, dest, nil); !errors.Is(err, ErrUnpinnedRefused) {
+			t.Errorf("expected an unpinned artifact to be refused, got %v", err)
+		}
+	}
+	if *hits != 0 || FileExists(dest) {
+		t.Errorf("nothing should be fetched without --allow-unpinned (%d requests)", *hits)
+	}
+}
+
+func TestDownload_ChecksumManifest(t *testing.T) {
+	withUnpinnedAllowed(t)
+	payload := []byte("font archive")
+	srv, _, _ := artifactServer(t, payload, 0)
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	if err := Download(Artifact{URL: srv.URL + "/file.bin", ChecksumManifest: srv.URL + "/SHA-256.txt"}, dest, nil); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	missing := Artifact{URL: srv.URL + "/other.zip", ChecksumManifest: srv.URL + "/SHA-256.txt"}
+	if err := Download(missing, dest, nil); !errors.Is(err, ErrUnpinned) {
+		t.Errorf("expected ErrUnpinned for an unlisted file, got %v", err)
+	}
+}
+
+func TestDownload_ReleaseDigest(t *testing.T) {
+	withUnpinnedAllowed(t)
+	payload := []byte("terminal bundle")
+	srv, _, _ := artifactServer(t, payload, 0)
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	if err := Download(Artifact{URL: srv.URL + "/file.bin", ReleaseAPI: srv.URL + "/release"}, dest, nil); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	for _, name := range []string{"old.bin", "other.txz"} {
+		a := Artifact{URL: srv.URL + "/" + name, ReleaseAPI: srv.URL + "/release"}
+		if err := Download(a, dest, nil); !errors.Is(err, ErrUnpinned) {
+			t.Errorf("%s: expected ErrUnpinned, got %v", name, err)
+		}
+	}
+}
+
+func TestFetchRelease(t *testing.T) {
+	payload := []byte("herdr")
+	srv, _, _ := artifactServer(t, payload, 0)
+
+	release, err := FetchRelease(srv.URL + "/release")
+	if err != nil {
+		t.Fatalf("FetchRelease: %v", err)
+	}
+	if release.Tag != "v1.2.0" || release.Assets["file.bin"] != sha256Hex(payload) {
+		t.Errorf("unexpected release %+v", release)
+	}
+	if sum, ok := release.Assets["old.bin"]; !ok || sum != "" {
+		t.Errorf("an asset without a digest must map to \"\", got %q", sum)
+	}
+}
+
+func TestReplaceBinary(t *testing.T) {
+	dir := t.TempDir()
+	src, dest := filepath.Join(dir, "download"), filepath.Join(dir, "bin", "tool")
+	os.MkdirAll(filepath.Dir(dest), 0755)
+	os.WriteFile(dest, []byte("old"), 0755)
+	os.WriteFile(src, []byte("new"), 0644)
+
+	if err := ReplaceBinary(src, dest); err != nil {
+		t.Fatalf("ReplaceBinary: %v", err)
+	}
+	fi, err := os.Stat(dest)
+	if err != nil || fi.Mode().Perm() != 0755 {
+		t.Fatalf("expected an executable binary, got %v (%v)", fi, err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "new" {
+		t.Errorf("expected the new binary, got %q", data)
+	}
+	if FileExists(dest + ".new") {
+		t.Error("the staging copy must be renamed away")
+	}
+}
+
+func TestDownload_ResumesPartial(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789"), 500)
+	srv, _, lastRange := artifactServer(t, payload, 0)
+	want := sha256Hex(payload)
+
+	EnsureDir(DownloadCacheDir())
+	os.WriteFile(filepath.Join(DownloadCacheDir(), want+".part"), payload[:1200], 0644)
+
+	dest := filepath.Join(t.TempDir(), "file.bin")
+	if err := Download(Artifact{URL: srv.URL + "/file.bin", SHA256: want}, dest, nil); err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if got := lastRange.Load().(string); got != "bytes=1200-" {
+		t.Errorf("expected a ranged request, got %q", got)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Error("resumed content differs")
+	}
+}
+
+func TestDownload_Retries(t *testing.T) {
+	payload := []byte("flaky")
+	srv, hits, _ := artifactServer(t, payload, 2)
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	if err := Download(Artifact{URL: srv.URL + "/file.bin", SHA256: sha256Hex(payload)}, dest, nil); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if *hits != 3 {
+		t.Errorf("expected 3 attempts, got %d", *hits)
+	}
+
+	// Client errors are permanent
+	err := Download(Artifact{URL: srv.URL + "/missing.bin", SHA256: sha256Hex([]byte("missing"))}, dest, nil)
+	if err == nil || *hits != 4 {
+		t.Errorf("expected a single failed attempt for 404, got %d attempts (err=%v)", *hits-3, err)
+	}
+}
+
+func TestExtractZipFile(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "font.zip")
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range map[string]string{"README.md": "readme", "fonts/Font-Regular.ttf": "glyphs"} {
+		w, _ := zw.Create(name)
+		w.Write([]byte(body))
+	}
+	zw.Close()
+	os.WriteFile(archive, buf.Bytes(), 0644)
+
+	dest := filepath.Join(dir, "out", "font.ttf")
+	if err := ExtractZipFile(archive, "Font-Regular.ttf", dest); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(dest); string(got) != "glyphs" {
+		t.Errorf("unexpected content %q", got)
+	}
+	if err := ExtractZipFile(archive, "Missing.ttf", dest); err == nil {
+		t.Error("expected an error for a missing entry")
+	}
+}
+
+func TestCheckInstallScript(t *testing.T) {
+	t.Cleanup(func() { SetAllowInstallScripts(false) })
+
+	SetAllowInstallScripts(false)
+	if err := CheckInstallScript("Homebrew"); !errors.Is(err, ErrInstallScriptRefused) {
+		t.Errorf("expected refusal by default, got %v", err)
+	}
+	SetAllowInstallScripts(true)
+	if err := CheckInstallScript("Homebrew"); err != nil {
+		t.Errorf("expected scripts to be allowed, got %v", err)
+	}
+}
@@ -179,7 +179,7 @@ func Run(command string, opts *ExecOptions) *ExecResult {
 	}
 
 	// Set environment
-	cmd.Env = os.Environ()
+	cmd.Env = append(os.Environ(), NetworkEnv()...)
 	if len(opts.Env) > 0 {
 		cmd.Env = append(cmd.Env, opts.Env...)
 	}
@@ -258,7 +258,17 @@ func streamOutput(r io.Reader, w *strings.Builder) {
 
 // RunSudo runs a command with sudo
 func RunSudo(command string, opts *ExecOptions) *ExecResult {
-	return Run("sudo "+command, opts)
+	return Run(SudoCommand(command), opts)
+}
+
+// SudoCommand prefixes command with sudo unless we already run as root
+// (minimal Alpine and openSUSE containers ship without sudo). Network
+// settings are preserved so package managers reach the proxy.
+func SudoCommand(command string) string {
+	if os.Geteuid() == 0 {
+		return command
+	}
+	return "sudo " + sudoPreserveEnv() + command
 }
 
 // RunBrew runs a brew command
@@ -356,6 +366,12 @@ func EnsureDir(path string) error {
 	return os.MkdirAll(path, 0755)
 }
 
+// FileExists reports whether path exists (following symlinks)
+func FileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
 // BackupInfo contains information about a backup
 type BackupInfo struct {
 	Path      string
@@ -370,6 +386,7 @@ func ConfigPaths() map[string]string {
 		"nvim":      home + "/.config/nvim",
 		"fish":      home + "/.config/fish",
 		"zsh":       home + "/.zshrc",
+		"bash":      home + "/.bashrc",
 		"zsh_p10k":  home + "/.p10k.zsh",
 		"oh-my-zsh": home + "/.oh-my-zsh",
 		"nushell":   home + "/.config/nushell",
@@ -572,7 +589,7 @@ func RunWithLogs(command string, opts *ExecOptions, onLog LogCallback) *ExecResu
 		cmd.Dir = opts.WorkDir
 	}
 
-	cmd.Env = os.Environ()
+	cmd.Env = append(os.Environ(), NetworkEnv()...)
 	if len(opts.Env) > 0 {
 		cmd.Env = append(cmd.Env, opts.Env...)
 	}
@@ -665,254 +682,5 @@ func RunBrewWithLogs(args string, opts *ExecOptions, onLog LogCallback) *ExecRes
 
 // RunSudoWithLogs runs a sudo command with log streaming
 func RunSudoWithLogs(command string, opts *ExecOptions, onLog LogCallback) *ExecResult {
-	return RunWithLogs("sudo "+command, opts, onLog)
-}
-
-// PatchZshForWM modifies .zshrc based on window manager choice.
-func PatchZshForWM(zshrcPath string, wm string, installNvim bool) error {
-	content, err := os.ReadFile(zshrcPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-	inStartIfNeeded := false
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		if strings.Contains(line, `eval "$(fzf --zsh)"`) {
-			if !installNvim {
-				newLines = append(newLines, `if command -v fzf &> /dev/null; then`)
-				newLines = append(newLines, line)
-				newLines = append(newLines, `fi`)
-			} else {
-				newLines = append(newLines, line)
-			}
-			continue
-		}
-
-		if strings.HasPrefix(trimmed, "function start_if_needed") {
-			inStartIfNeeded = !strings.Contains(trimmed, "}")
-			if wm != "none" {
-				newLines = append(newLines, line)
-			}
-			continue
-		}
-		if inStartIfNeeded {
-			if wm != "none" {
-				if strings.Contains(trimmed, "[[ $- == *i* ]]") && strings.Contains(trimmed, "WM_VAR") {
-					newLines = append(newLines, `    if [[ $- == *i* ]] && command -v "$WM_CMD" >/dev/null 2>&1 && [[ -z "${WM_VAR#/}" ]] && [[ -z "$TMUX" ]] && [[ -z "$ZELLIJ" ]] && [[ -z "$HERDR_ENV" ]] && [[ -t 1 ]]; then`)
-				} else {
-					newLines = append(newLines, line)
-				}
-			}
-			if trimmed == "}" {
-				inStartIfNeeded = false
-			}
-			continue
-		}
-		if trimmed == `WM_VAR="/$TMUX"` || trimmed == `WM_VAR="$ZELLIJ"` || trimmed == `WM_VAR="$HERDR_ENV"` {
-			switch wm {
-			case "tmux":
-				newLines = append(newLines, `WM_VAR="/$TMUX"`)
-			case "zellij":
-				newLines = append(newLines, `WM_VAR="$ZELLIJ"`)
-			case "herdr":
-				newLines = append(newLines, `WM_VAR="$HERDR_ENV"`)
-			}
-			continue
-		}
-		if trimmed == `WM_CMD="tmux"` || trimmed == `WM_CMD="zellij"` || trimmed == `WM_CMD="herdr"` {
-			switch wm {
-			case "tmux":
-				newLines = append(newLines, `WM_CMD="tmux"`)
-			case "zellij":
-				newLines = append(newLines, `WM_CMD="zellij"`)
-			case "herdr":
-				newLines = append(newLines, `WM_CMD="herdr"`)
-			}
-			continue
-		}
-		if trimmed == "# change with ZELLIJ" || trimmed == "# change with zellij" || trimmed == "# change with HERDR" || trimmed == "# change with herdr" {
-			continue
-		}
-		if trimmed == "start_if_needed" && wm == "none" {
-			continue
-		}
-
-		newLines = append(newLines, line)
-	}
-
-	return os.WriteFile(zshrcPath, []byte(strings.Join(newLines, "\n")), 0644)
-}
-
-// PatchFishForWM modifies config.fish based on window manager choice.
-func PatchFishForWM(configPath string, wm string, installNvim bool) error {
-	content, err := os.ReadFile(configPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-	insertedMultiplexerBlock := false
-	inMultiplexerBlock := false
-	multiplexerBlockDepth := 0
-	inCommentedMultiplexerBlock := false
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		if strings.Contains(line, "fzf --fish | source") {
-			if !installNvim {
-				newLines = append(newLines, `if command -v fzf &> /dev/null`)
-				newLines = append(newLines, line)
-				newLines = append(newLines, `end`)
-			} else {
-				newLines = append(newLines, line)
-			}
-			continue
-		}
-
-		if inMultiplexerBlock {
-			if strings.HasPrefix(trimmed, "if ") {
-				multiplexerBlockDepth++
-			}
-			if trimmed == "end" {
-				multiplexerBlockDepth--
-				if multiplexerBlockDepth <= 0 {
-					inMultiplexerBlock = false
-				}
-			}
-			continue
-		}
-
-		if inCommentedMultiplexerBlock {
-			if trimmed == "#end" {
-				inCommentedMultiplexerBlock = false
-			}
-			continue
-		}
-
-		startsMultiplexerBlock := trimmed == "# Start tmux/zellij (tmux first, zellij as fallback)" ||
-			trimmed == "# Start selected terminal multiplexer" ||
-			trimmed == "if not set -q TMUX" ||
-			strings.HasPrefix(trimmed, "if status is-interactive; and") && (strings.Contains(trimmed, "TMUX") || strings.Contains(trimmed, "ZELLIJ") || strings.Contains(trimmed, "HERDR_ENV"))
-
-		if startsMultiplexerBlock {
-			if !insertedMultiplexerBlock && wm != "none" {
-				newLines = append(newLines, fishMultiplexerBlock(wm)...)
-				insertedMultiplexerBlock = true
-			}
-			if strings.HasPrefix(trimmed, "if ") {
-				inMultiplexerBlock = true
-				multiplexerBlockDepth = 1
-			}
-			continue
-		}
-
-		if strings.HasPrefix(trimmed, "#if not set -q ZELLIJ") || strings.HasPrefix(trimmed, "#if not set -q HERDR_ENV") {
-			inCommentedMultiplexerBlock = true
-			continue
-		}
-
-		newLines = append(newLines, line)
-	}
-
-	if !insertedMultiplexerBlock && wm != "none" {
-		newLines = append(newLines, fishMultiplexerBlock(wm)...)
-	}
-
-	return os.WriteFile(configPath, []byte(strings.Join(newLines, "\n")), 0644)
-}
-
-func fishMultiplexerBlock(wm string) []string {
-	switch wm {
-	case "zellij":
-		return []string{
-			"# Start selected terminal multiplexer",
-			"if status is-interactive; and command -q zellij; and not set -q TMUX; and not set -q ZELLIJ; and not set -q HERDR_ENV",
-			"    zellij attach -c main",
-			"end",
-		}
-	case "herdr":
-		return []string{
-			"# Start selected terminal multiplexer",
-			"if status is-interactive; and command -q herdr; and not set -q HERDR_ENV; and not set -q TMUX; and not set -q ZELLIJ",
-			"    herdr; or echo \"⚠️  Herdr failed to start; continuing in Fish.\"",
-			"end",
-		}
-	default:
-		return []string{
-			"# Start selected terminal multiplexer",
-			"if status is-interactive; and command -q tmux; and not set -q TMUX; and not set -q ZELLIJ; and not set -q HERDR_ENV",
-			"    tmux new-session -A -s main",
-			"end",
-		}
-	}
-}
-
-// PatchNushellForWM modifies config.nu based on window manager choice.
-func PatchNushellForWM(configPath string, wm string) error {
-	content, err := os.ReadFile(configPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
-	var newLines []string
-	inMultiplexerBlock := false
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		if strings.HasPrefix(trimmed, "let MULTIPLEXER =") {
-			switch wm {
-			case "tmux", "zellij", "herdr":
-				newLines = append(newLines, fmt.Sprintf(`let MULTIPLEXER = "%s"`, wm))
-			}
-			continue
-		}
-		if strings.HasPrefix(trimmed, "let MULTIPLEXER_ENV_PREFIX =") {
-			switch wm {
-			case "tmux":
-				newLines = append(newLines, `let MULTIPLEXER_ENV_PREFIX = "TMUX"`)
-			case "zellij":
-				newLines = append(newLines, `let MULTIPLEXER_ENV_PREFIX = "ZELLIJ"`)
-			case "herdr":
-				newLines = append(newLines, `let MULTIPLEXER_ENV_PREFIX = "HERDR_ENV"`)
-			}
-			continue
-		}
-		if strings.HasPrefix(trimmed, "def start_multiplexer") {
-			inMultiplexerBlock = !strings.Contains(trimmed, "}")
-			if wm != "none" {
-				newLines = append(newLines, line)
-			}
-			continue
-		}
-		if inMultiplexerBlock {
-			if wm != "none" {
-				if strings.HasPrefix(trimmed, "if $MULTIPLEXER_ENV_PREFIX not-in") {
-					newLines = append(newLines, `  let active_env = ($env | columns)`)
-					newLines = append(newLines, `  if (which $MULTIPLEXER | is-not-empty) and $MULTIPLEXER_ENV_PREFIX not-in $active_env and "TMUX" not-in $active_env and "ZELLIJ" not-in $active_env and "HERDR_ENV" not-in $active_env {`)
-				} else {
-					newLines = append(newLines, line)
-				}
-			}
-			if trimmed == "}" {
-				inMultiplexerBlock = false
-			}
-			continue
-		}
-		if trimmed == "start_multiplexer" && wm == "none" {
-			continue
-		}
-
-		newLines = append(newLines, line)
-	}
-
-	return os.WriteFile(configPath, []byte(strings.Join(newLines, "\n")), 0644)
+	return RunWithLogs(SudoCommand(command), opts, onLog)
 }
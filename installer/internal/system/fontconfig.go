@@ -0,0 +1,74 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	tomlTableRe   = regexp.MustCompile(`^\s*\[\[?([^\]]+)\]\]?\s*(#.*)?$`)
+	weztermFontRe = regexp.MustCompile(`^(\s*config\.font\s*=\s*wezterm\.font(?:_with_fallback)?\(\s*\{?\s*(?:family\s*=\s*)?)"[^"]*"(.*)$`)
+	kittyFontRe   = regexp.MustCompile(`^(\s*font_family\s+).*$`)
+	ghosttyFontRe = regexp.MustCompile(`^(\s*font-family\s*=\s*).*$`)
+)
+
+// SetTerminalFont rewrites the font family in a terminal config, leaving
+// comments and every other setting untouched. A missing setting is added.
+func SetTerminalFont(terminal, path, family string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var updated string
+	switch terminal {
+	case "alacritty":
+		updated = setAlacrittyFont(string(content), family)
+	case "wezterm":
+		updated = setWeztermFont(string(content), family)
+	case "kitty":
+		updated = replaceOrAppend(string(content), kittyFontRe, "font_family      "+family)
+	case "ghostty":
+		updated = replaceOrAppend(string(content), ghosttyFontRe, "font-family = "+family)
+	default:
+		return fmt.Errorf("unsupported terminal for font configuration: %s", terminal)
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// setAlacrittyFont sets family under [font.normal]
+func setAlacrittyFont(content, family string) string {
+	return setTOMLValue(content, "font.normal", "family", fmt.Sprintf("%q", family))
+}
+
+// setWeztermFont replaces the first family in config.font, or sets it before `return config`
+func setWeztermFont(content, family string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if m := weztermFontRe.FindStringSubmatch(line); m != nil {
+			lines[i] = fmt.Sprintf("%s%q%s", m[1], family, m[2])
+			return strings.Join(lines, "\n")
+		}
+	}
+	entry := fmt.Sprintf("config.font = wezterm.font(%q)", family)
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) == "return config" {
+			lines = append(lines[:i], append([]string{entry, ""}, lines[i:]...)...)
+			return strings.Join(lines, "\n")
+		}
+	}
+	return strings.TrimRight(content, "\n") + "\n" + entry + "\n"
+}
+
+// replaceOrAppend replaces the first line matching re with line, or appends it
+func replaceOrAppend(content string, re *regexp.Regexp, line string) string {
+	lines := strings.Split(content, "\n")
+	for i, l := range lines {
+		if re.MatchString(l) {
+			lines[i] = line
+			return strings.Join(lines, "\n")
+		}
+	}
+	return strings.TrimRight(content, "\n") + "\n" + line + "\n"
+}
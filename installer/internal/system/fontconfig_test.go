@@ -0,0 +1,57 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetTerminalFont(t *testing.T) {
+	tests := []struct {
+		terminal, content, want, keep string
+	}{
+		{"alacritty", "# FONT\n[font]\nsize = 14\n\n[font.normal]\nfamily = \"IosevkaTerm NF\" # main\n\n[window]\nfamily = \"x\"\n",
+			"family = \"Hack Nerd Font\" # main", "[window]\nfamily = \"x\""},
+		{"wezterm", "-- FONT\nconfig.font = wezterm.font(\"IosevkaTerm NF\")\nconfig.font_size = 14.0\nreturn config\n",
+			"config.font = wezterm.font(\"Hack Nerd Font\")", "config.font_size = 14.0"},
+		{"wezterm", "config.font = wezterm.font_with_fallback({ \"IosevkaTerm NF\", \"Symbols\" })\n",
+			"config.font = wezterm.font_with_fallback({ \"Hack Nerd Font\", \"Symbols\" })", ""},
+		{"kitty", "# font_family Comment\nfont_family      IosevkaTerm Nerd Font\nfont_size        14.0\n",
+			"font_family      Hack Nerd Font", "# font_family Comment"},
+		{"ghostty", "# FONT\nfont-family = IosevkaTerm NF\nfont-size = 14\n",
+			"font-family = Hack Nerd Font", "font-size = 14"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config")
+		os.WriteFile(path, []byte(tt.content), 0644)
+		if err := SetTerminalFont(tt.terminal, path, "Hack Nerd Font"); err != nil {
+			t.Fatalf("%s: %v", tt.terminal, err)
+		}
+		got, _ := os.ReadFile(path)
+		if !strings.Contains(string(got), tt.want) || !strings.Contains(string(got), tt.keep) {
+			t.Errorf("%s: expected %q and %q in:\n%s", tt.terminal, tt.want, tt.keep, got)
+		}
+		if strings.Contains(string(got), "Iosevka") && tt.terminal != "kitty" {
+			t.Errorf("%s: old family left behind:\n%s", tt.terminal, got)
+		}
+	}
+}
+
+func TestSetTerminalFont_AddsMissingSetting(t *testing.T) {
+	tests := map[string]struct{ content, want string }{
+		"alacritty": {"[font]\nsize = 14\n", "[font.normal]\nfamily = \"Hack Nerd Font\"\n"},
+		"wezterm":   {"local config = {}\nreturn config\n", "config.font = wezterm.font(\"Hack Nerd Font\")\n\nreturn config"},
+		"ghostty":   {"font-size = 14\n", "font-size = 14\nfont-family = Hack Nerd Font\n"},
+	}
+	for terminal, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config")
+		os.WriteFile(path, []byte(tt.content), 0644)
+		if err := SetTerminalFont(terminal, path, "Hack Nerd Font"); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := os.ReadFile(path); !strings.Contains(string(got), tt.want) {
+			t.Errorf("%s: expected %q in:\n%s", terminal, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// HerdrBinding is a [keys] entry of herdr/config.toml the installer edits
+type HerdrBinding struct {
+	Key     string
+	Label   string
+	Default string   // value in the shipped herdr/config.toml
+	Choices []string // presets offered by the key binding screen
+}
+
+// HerdrBindings lists what `set herdr.<key>` and the Herdr keys screen edit
+var HerdrBindings = []HerdrBinding{
+	{Key: "prefix", Label: "Prefix", Default: "ctrl+a",
+		Choices: []string{"ctrl+a", "ctrl+b", "ctrl+s", "ctrl+space"}},
+	{Key: "previous_agent", Label: "Previous agent", Default: "prefix+alt+k",
+		Choices: []string{"prefix+alt+k", "prefix+ctrl+k", "prefix+k"}},
+	{Key: "next_agent", Label: "Next agent", Default: "prefix+alt+j",
+		Choices: []string{"prefix+alt+j", "prefix+ctrl+j", "prefix+j"}},
+	{Key: "focus_agent", Label: "Jump to agent N", Default: "prefix+ctrl+1..9",
+		Choices: []string{"prefix+ctrl+1..9", "prefix+alt+1..9", "prefix+1..9"}},
+}
+
+// herdrReserved are Herdr's built-in pane-swap bindings, kept free for it
+var herdrReserved = []string{"prefix+shift+j", "prefix+shift+k"}
+
+var (
+	herdrModifiers = []string{"ctrl", "alt", "shift", "super"}
+	herdrNamedKeys = []string{"space", "enter", "tab", "esc", "backspace", "up", "down", "left", "right",
+		"home", "end", "pageup", "pagedown"}
+)
+
+// LookupHerdrBinding finds a binding by key
+func LookupHerdrBinding(key string) (HerdrBinding, bool) {
+	for _, b := range HerdrBindings {
+		if b.Key == key {
+			return b, true
+		}
+	}
+	return HerdrBinding{}, false
+}
+
+// Parse validates a key spec such as ctrl+a or prefix+alt+k for the binding
+func (b HerdrBinding) Parse(value string) (string, error) {
+	spec := strings.ToLower(strings.TrimSpace(value))
+	parts := strings.Split(spec, "+")
+	last := parts[len(parts)-1]
+	invalid := func(reason string) (string, error) {
+		return "", fmt.Errorf("invalid %s binding %q: %s", b.Key, value, reason)
+	}
+
+	if b.Key == "focus_agent" {
+		if last != "1..9" {
+			return invalid("the key must be the range 1..9, e.g. prefix+ctrl+1..9")
+		}
+	} else if !isHerdrKey(last) {
+		return invalid(fmt.Sprintf("%q is not a key", last))
+	}
+	for i, part := range parts[:len(parts)-1] {
+		switch {
+		case part == "prefix" && i == 0 && b.Key != "prefix":
+		case part == "prefix":
+			return invalid("prefix can only start a binding")
+		case !slices.Contains(herdrModifiers, part):
+			return invalid(fmt.Sprintf("unknown modifier %q (use %s)", part, strings.Join(herdrModifiers, ", ")))
+		case slices.Contains(parts[:i], part):
+			return invalid(fmt.Sprintf("%s appears twice", part))
+		}
+	}
+	if b.Key == "prefix" && len(parts) == 1 {
+		return invalid("the prefix needs a modifier, e.g. ctrl+a")
+	}
+	return spec, nil
+}
+
+// isHerdrKey reports whether key names a single key
+func isHerdrKey(key string) bool {
+	if len(key) == 1 {
+		return key[0] > ' ' && key[0] < 0x7f
+	}
+	if fn, ok := strings.CutPrefix(key, "f"); ok {
+		if n, err := strconv.Atoi(fn); err == nil && n >= 1 && n <= 12 {
+			return true
+		}
+	}
+	return slices.Contains(herdrNamedKeys, key)
+}
+
+// HerdrKeys reads the [keys] bindings of a Herdr config; unset ones get the shipped defaults
+func HerdrKeys(content string) map[string]string {
+	keys := map[string]string{}
+	for _, b := range HerdrBindings {
+		keys[b.Key] = b.Default
+		if raw := tomlValue(content, "keys", b.Key); raw != "" {
+			keys[b.Key] = strings.Trim(raw, `"'`)
+		}
+	}
+	return keys
+}
+
+// CheckHerdrKeys rejects bindings that clash with each other or with Herdr's built-ins
+func CheckHerdrKeys(keys map[string]string) error {
+	seen := map[string]string{}
+	for _, b := range HerdrBindings {
+		value := keys[b.Key]
+		if b.Key == "prefix" {
+			continue
+		}
+		if slices.Contains(herdrReserved, value) {
+			return fmt.Errorf("%s = %q is Herdr's built-in pane swap", b.Key, value)
+		}
+		if other, ok := seen[value]; ok {
+			return fmt.Errorf("%s and %s are both bound to %q", other, b.Key, value)
+		}
+		seen[value] = b.Key
+	}
+	return nil
+}
+
+// SetHerdrKeys writes bindings into the [keys] table of a Herdr config,
+// keeping comments, alignment and every other setting
+func SetHerdrKeys(path string, keys map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	merged := HerdrKeys(content)
+	for key, value := range keys {
+		b, ok := LookupHerdrBinding(key)
+		if !ok {
+			return fmt.Errorf("unknown herdr binding: %s", key)
+		}
+		if merged[key], err = b.Parse(value); err != nil {
+			return err
+		}
+	}
+	if err := CheckHerdrKeys(merged); err != nil {
+		return err
+	}
+
+	updated := content
+	for _, b := range HerdrBindings {
+		if _, ok := keys[b.Key]; ok {
+			updated = setTOMLValue(updated, "keys", b.Key, strconv.Quote(merged[b.Key]))
+		}
+	}
+	if updated == content {
+		return nil
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
@@ -0,0 +1,103 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHerdrKeys_ShippedConfig(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "herdr", "config.toml"))
+	if err != nil {
+		t.Fatalf("reading shipped config: %v", err)
+	}
+	keys := HerdrKeys(string(data))
+	for _, b := range HerdrBindings {
+		if keys[b.Key] != b.Default {
+			t.Errorf("%s: the default %q does not match the shipped %q", b.Key, b.Default, keys[b.Key])
+		}
+		for _, choice := range b.Choices {
+			if _, err := b.Parse(choice); err != nil {
+				t.Errorf("%s: preset %q is invalid: %v", b.Key, choice, err)
+			}
+		}
+	}
+	if keys := HerdrKeys("[theme]\nname = \"one-dark\"\n"); keys["prefix"] != "ctrl+a" {
+		t.Errorf("expected the default prefix without a [keys] table, got %q", keys["prefix"])
+	}
+}
+
+func TestHerdrBindingParse(t *testing.T) {
+	tests := []struct {
+		key, value, want string
+		valid            bool
+	}{
+		{"prefix", "Ctrl+B", "ctrl+b", true},
+		{"prefix", "ctrl+space", "ctrl+space", true},
+		{"prefix", "a", "", false},
+		{"prefix", "prefix+ctrl+a", "", false},
+		{"next_agent", "prefix+alt+j", "prefix+alt+j", true},
+		{"next_agent", "prefix+f2", "prefix+f2", true},
+		{"next_agent", "alt+prefix+j", "", false},
+		{"next_agent", "prefix+hyper+j", "", false},
+		{"next_agent", "prefix+alt+alt+j", "", false},
+		{"next_agent", "prefix+alt+", "", false},
+		{"focus_agent", "prefix+ctrl+1..9", "prefix+ctrl+1..9", true},
+		{"focus_agent", "prefix+ctrl+1", "", false},
+	}
+	for _, tt := range tests {
+		b, _ := LookupHerdrBinding(tt.key)
+		got, err := b.Parse(tt.value)
+		if (err == nil) != tt.valid || got != tt.want {
+			t.Errorf("%s %q: got %q (%v)", tt.key, tt.value, got, err)
+		}
+	}
+}
+
+func TestSetHerdrKeys_ShippedConfig(t *testing.T) {
+	original, err := os.ReadFile(filepath.Join("..", "..", "..", "herdr", "config.toml"))
+	if err != nil {
+		t.Fatalf("reading shipped config: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, original, 0644)
+
+	for i := 0; i < 2; i++ {
+		if err := SetHerdrKeys(path, map[string]string{"prefix": "ctrl+b", "focus_agent": "prefix+alt+1..9"}); err != nil {
+			t.Fatalf("SetHerdrKeys: %v", err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	want := strings.Replace(string(original), `prefix = "ctrl+a"`, `prefix = "ctrl+b"`, 1)
+	want = strings.Replace(want, `focus_agent = "prefix+ctrl+1..9"`, `focus_agent = "prefix+alt+1..9"`, 1)
+	if string(data) != want {
+		t.Errorf("expected only the two bindings to change, got:\n%s", data)
+	}
+
+	for _, keys := range []map[string]string{
+		{"next_agent": "prefix+alt+k"},
+		{"previous_agent": "prefix+shift+k"},
+		{"next_agent": "prefix+nope+j"},
+		{"detach": "ctrl+d"},
+	} {
+		if err := SetHerdrKeys(path, keys); err == nil {
+			t.Errorf("%v: expected an error", keys)
+		}
+	}
+	if again, _ := os.ReadFile(path); string(again) != want {
+		t.Error("a rejected binding must leave the config untouched")
+	}
+}
+
+func TestSetHerdrKeys_AddsKeysTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, []byte("[ui]\naccent = \"#6FA0AF\"\n"), 0644)
+	if err := SetHerdrKeys(path, map[string]string{"prefix": "ctrl+s"}); err != nil {
+		t.Fatalf("SetHerdrKeys: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "[ui]\naccent = \"#6FA0AF\"\n\n[keys]\nprefix = \"ctrl+s\"\n" {
+		t.Errorf("expected a [keys] table, got:\n%s", data)
+	}
+}
@@ -0,0 +1,522 @@
+package system
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KDLNode is a node of a KDL document together with the bytes it spans, so
+// edits can replace it in place and leave the rest of the file untouched
+type KDLNode struct {
+	Name     string
+	Args     []string          // argument values; strings are unquoted
+	Props    map[string]string // property values; strings are unquoted
+	Children []*KDLNode
+	Start    int // offset of the node's first byte (its type annotation or name)
+	End      int // offset just past its last value or closing brace
+	Body     int // offset of the closing brace of the children block, -1 without one
+}
+
+// ParseKDL parses the nodes of a KDL document. Comments and slashdashed
+// (`/-`) nodes, entries and blocks are skipped.
+func ParseKDL(content string) ([]*KDLNode, error) {
+	p := &kdlParser{src: content}
+	nodes, err := p.nodes(false)
+	if err != nil {
+		line := strings.Count(content[:min(p.pos, len(content))], "\n") + 1
+		return nil, fmt.Errorf("kdl line %d: %w", line, err)
+	}
+	return nodes, nil
+}
+
+type kdlParser struct {
+	src string
+	pos int
+}
+
+func (p *kdlParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *kdlParser) peek(s string) bool { return strings.HasPrefix(p.src[p.pos:], s) }
+
+// nodes parses nodes until the end of input or, inside a block, the closing brace
+func (p *kdlParser) nodes(inBlock bool) ([]*KDLNode, error) {
+	var nodes []*KDLNode
+	for {
+		if err := p.skipSpace(true); err != nil {
+			return nil, err
+		}
+		switch {
+		case p.eof():
+			if inBlock {
+				return nil, fmt.Errorf("unclosed {")
+			}
+			return nodes, nil
+		case p.src[p.pos] == ';':
+			p.pos++
+		case p.src[p.pos] == '}':
+			if !inBlock {
+				return nil, fmt.Errorf("unexpected }")
+			}
+			return nodes, nil
+		case p.peek("/-"):
+			p.pos += 2
+			if err := p.skipSpace(true); err != nil {
+				return nil, err
+			}
+			if _, err := p.node(); err != nil {
+				return nil, err
+			}
+		default:
+			node, err := p.node()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		}
+	}
+}
+
+// node parses one node: name, entries and an optional children block
+func (p *kdlParser) node() (*KDLNode, error) {
+	node := &KDLNode{Start: p.pos, Body: -1, Props: map[string]string{}}
+	p.annotation()
+	name, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	node.Name = name
+	node.End = p.pos
+	for {
+		if err := p.skipSpace(false); err != nil {
+			return nil, err
+		}
+		if p.eof() || strings.ContainsRune("\n\r;}", rune(p.src[p.pos])) || p.peek("//") {
+			return node, nil
+		}
+		skip := p.peek("/-")
+		if skip {
+			p.pos += 2
+			p.skipSpace(false)
+		}
+		if !p.eof() && p.src[p.pos] == '{' {
+			p.pos++
+			children, err := p.nodes(true)
+			if err != nil {
+				return nil, err
+			}
+			if !skip {
+				node.Children, node.Body = children, p.pos
+			}
+			p.pos++
+			if !skip {
+				node.End = p.pos
+				return node, nil
+			}
+			continue
+		}
+		p.annotation()
+		entry, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if !p.eof() && p.src[p.pos] == '=' {
+			p.pos++
+			p.annotation()
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			if !skip {
+				node.Props[entry] = value
+			}
+		} else if !skip {
+			node.Args = append(node.Args, entry)
+		}
+		if !skip {
+			node.End = p.pos
+		}
+	}
+}
+
+// skipSpace skips whitespace, comments and line continuations; newlines only
+// when newlines is set, since they terminate a node
+func (p *kdlParser) skipSpace(newlines bool) error {
+	for !p.eof() {
+		c := p.src[p.pos]
+		switch {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case (c == '\n' || c == '\r') && newlines:
+			p.pos++
+		case p.peek("//") && newlines:
+			for !p.eof() && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case p.peek("/*"):
+			depth := 0
+			for {
+				if p.eof() {
+					return fmt.Errorf("unclosed /* comment")
+				}
+				if p.peek("/*") {
+					depth++
+					p.pos += 2
+				} else if p.peek("*/") {
+					depth--
+					p.pos += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					p.pos++
+				}
+			}
+		case c == '\\':
+			// Line continuation: the node goes on past the newline
+			p.pos++
+			for !p.eof() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+				p.pos++
+			}
+			if p.peek("//") {
+				for !p.eof() && p.src[p.pos] != '\n' {
+					p.pos++
+				}
+			}
+			if !p.eof() && p.src[p.pos] == '\r' {
+				p.pos++
+			}
+			if p.eof() || p.src[p.pos] != '\n' {
+				return fmt.Errorf("expected a newline after \\")
+			}
+			p.pos++
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// annotation skips a `(type)` annotation
+func (p *kdlParser) annotation() {
+	if !p.eof() && p.src[p.pos] == '(' {
+		if end := strings.IndexByte(p.src[p.pos:], ')'); end >= 0 {
+			p.pos += end + 1
+		}
+	}
+}
+
+// value parses a quoted string, a raw string or a bare identifier/number/keyword
+func (p *kdlParser) value() (string, error) {
+	if p.eof() {
+		return "", fmt.Errorf("unexpected end of input")
+	}
+	if p.src[p.pos] == '"' {
+		return p.quoted()
+	}
+	if hashes, ok := p.rawStart(); ok {
+		closing := "\"" + strings.Repeat("#", hashes)
+		end := strings.Index(p.src[p.pos:], closing)
+		if end < 0 {
+			return "", fmt.Errorf("unclosed raw string")
+		}
+		value := p.src[p.pos : p.pos+end]
+		p.pos += end + len(closing)
+		return value, nil
+	}
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(" \t\r\n\\/(){}<>;[]=,\"", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", fmt.Errorf("unexpected %q", p.src[p.pos])
+	}
+	return p.src[start:p.pos], nil
+}
+
+// rawStart consumes the opening of r#"..."# (KDL 1) or #"..."# (KDL 2)
+func (p *kdlParser) rawStart() (int, bool) {
+	i := p.pos
+	if i < len(p.src) && p.src[i] == 'r' {
+		i++
+	}
+	hashes := 0
+	for i < len(p.src) && p.src[i] == '#' {
+		hashes++
+		i++
+	}
+	if i >= len(p.src) || p.src[i] != '"' || (i == p.pos+hashes && hashes == 0) {
+		return 0, false
+	}
+	p.pos = i + 1
+	return hashes, true
+}
+
+// quoted parses an escaped string
+func (p *kdlParser) quoted() (string, error) {
+	var b strings.Builder
+	p.pos++
+	for !p.eof() {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			p.pos++
+			if p.eof() {
+				return "", fmt.Errorf("unclosed string")
+			}
+			e := p.src[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				end := strings.IndexByte(p.src[p.pos:], '}')
+				if !p.peek("{") || end < 0 {
+					return "", fmt.Errorf("bad unicode escape")
+				}
+				r, err := strconv.ParseUint(p.src[p.pos+1:p.pos+end], 16, 32)
+				if err != nil {
+					return "", fmt.Errorf("bad unicode escape: %w", err)
+				}
+				b.WriteRune(rune(r))
+				p.pos += end + 1
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", fmt.Errorf("unclosed string")
+}
+
+// matchesKDL reports whether node has sel's name and starts with its arguments
+func matchesKDL(node, sel *KDLNode) bool {
+	if node.Name != sel.Name || len(node.Args) < len(sel.Args) {
+		return false
+	}
+	for i, arg := range sel.Args {
+		if node.Args[i] != arg {
+			return false
+		}
+	}
+	return true
+}
+
+// kdlSelector parses a path element such as `keybinds` or `bind "Ctrl a"`
+func kdlSelector(s string) (*KDLNode, error) {
+	nodes, err := ParseKDL(s)
+	if err != nil || len(nodes) != 1 {
+		return nil, fmt.Errorf("bad kdl selector %q", s)
+	}
+	return nodes[0], nil
+}
+
+// FindKDLNode follows path, one selector per level, and returns the first
+// match, or nil when a level is missing
+func FindKDLNode(nodes []*KDLNode, path ...string) *KDLNode {
+	var found *KDLNode
+	for _, s := range path {
+		sel, err := kdlSelector(s)
+		if err != nil {
+			return nil
+		}
+		found = nil
+		for _, node := range nodes {
+			if matchesKDL(node, sel) {
+				found = node
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		nodes = found.Children
+	}
+	return found
+}
+
+// SetKDLNode makes node the only match of the last selector of path, under
+// the parents the other selectors name. The first match is replaced in place
+// and the rest removed; without one, a commented-out template such as
+// `// default_shell "fish"` is taken over, else node is appended to its
+// parent. Missing parents are created.
+func SetKDLNode(content string, path []string, node string) (string, error) {
+	if len(path) == 0 {
+		return "", fmt.Errorf("empty kdl path")
+	}
+	nodes, err := ParseKDL(content)
+	if err != nil {
+		return "", err
+	}
+	sels := make([]*KDLNode, len(path))
+	for i, s := range path {
+		if sels[i], err = kdlSelector(s); err != nil {
+			return "", err
+		}
+	}
+
+	// Descend as far as the parents exist
+	depth, parent := 0, (*KDLNode)(nil)
+	for ; depth < len(path)-1; depth++ {
+		next := FindKDLNode(nodes, path[depth])
+		if next == nil {
+			break
+		}
+		parent, nodes = next, next.Children
+	}
+	start, end := 0, len(content)
+	if parent != nil {
+		start, end = parent.Start, parent.Body
+		if end < 0 {
+			// `keybinds` without a block yet: give it one
+			return content[:parent.End] + " {\n" + kdlNest(path[depth:], node, kdlIndent(content, parent.Start)+"    ") +
+				kdlIndent(content, parent.Start) + "}" + content[parent.End:], nil
+		}
+	}
+	if depth < len(path)-1 {
+		return kdlAppend(content, start, end, parent, kdlNest(path[depth:], node, kdlChildIndent(content, parent))), nil
+	}
+
+	sel := sels[len(sels)-1]
+	var matches []*KDLNode
+	for _, n := range nodes {
+		if matchesKDL(n, sel) {
+			matches = append(matches, n)
+		}
+	}
+	if len(matches) == 0 {
+		if updated, ok := kdlUncomment(content, start, end, nodes, sel, node); ok {
+			return updated, nil
+		}
+		return kdlAppend(content, start, end, parent, kdlChildIndent(content, parent)+kdlReindent(node, kdlChildIndent(content, parent))+"\n"), nil
+	}
+
+	// Edit back to front so earlier offsets stay valid
+	for i := len(matches) - 1; i > 0; i-- {
+		content = kdlRemove(content, matches[i])
+	}
+	first := matches[0]
+	return content[:first.Start] + kdlReindent(node, kdlIndent(content, first.Start)) + content[first.End:], nil
+}
+
+// SetKDLString sets a top-level `name "value"` node
+func SetKDLString(content, name, value string) (string, error) {
+	return SetKDLNode(content, []string{name}, name+" "+KDLQuote(value))
+}
+
+// KDLQuote renders s as a KDL string
+func KDLQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// kdlIndent is the whitespace before offset on its line
+func kdlIndent(content string, offset int) string {
+	lineStart := strings.LastIndexByte(content[:offset], '\n') + 1
+	indent := content[lineStart:offset]
+	if strings.TrimLeft(indent, " \t") != "" {
+		return ""
+	}
+	return indent
+}
+
+// kdlChildIndent is the indentation of parent's children: that of an existing
+// child, else one level deeper than parent
+func kdlChildIndent(content string, parent *KDLNode) string {
+	if parent == nil {
+		return ""
+	}
+	if len(parent.Children) > 0 {
+		return kdlIndent(content, parent.Children[0].Start)
+	}
+	return kdlIndent(content, parent.Start) + "    "
+}
+
+// kdlReindent indents the continuation lines of a multi-line node
+func kdlReindent(node, indent string) string {
+	return strings.ReplaceAll(strings.TrimSpace(node), "\n", "\n"+indent)
+}
+
+// kdlNest wraps node in blocks for the selectors in path but the last,
+// one line per node, each ending in a newline
+func kdlNest(path []string, node, indent string) string {
+	if len(path) == 1 {
+		return indent + kdlReindent(node, indent) + "\n"
+	}
+	return indent + path[0] + " {\n" + kdlNest(path[1:], node, indent+"    ") + indent + "}\n"
+}
+
+// kdlAppend adds lines at the end of parent's block, or of the document
+func kdlAppend(content string, start, end int, parent *KDLNode, lines string) string {
+	if parent == nil {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + lines
+	}
+	// Put the lines before the closing brace's line, or break its line
+	lineStart := strings.LastIndexByte(content[:end], '\n') + 1
+	if lineStart > start && strings.TrimSpace(content[lineStart:end]) == "" {
+		return content[:lineStart] + lines + content[lineStart:]
+	}
+	return content[:end] + "\n" + lines + kdlIndent(content, parent.Start) + content[end:]
+}
+
+// kdlRemove deletes node, with its whole line when nothing else is on it
+func kdlRemove(content string, node *KDLNode) string {
+	lineStart := strings.LastIndexByte(content[:node.Start], '\n') + 1
+	lineEnd := strings.IndexByte(content[node.End:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(content)
+	} else {
+		lineEnd += node.End + 1
+	}
+	rest := strings.TrimSpace(content[node.End:lineEnd])
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, ";"))
+	if strings.TrimSpace(content[lineStart:node.Start]) == "" && (rest == "" || strings.HasPrefix(rest, "//")) {
+		return content[:lineStart] + content[lineEnd:]
+	}
+	return content[:node.Start] + content[node.End:]
+}
+
+// kdlUncomment replaces a `// name ...` line between start and end that
+// comments out a node matching sel. Lines inside siblings are not considered.
+func kdlUncomment(content string, start, end int, siblings []*KDLNode, sel *KDLNode, node string) (string, bool) {
+	offset := start
+lines:
+	for _, line := range strings.SplitAfter(content[start:end], "\n") {
+		lineStart := offset
+		offset += len(line)
+		for _, sibling := range siblings {
+			if lineStart > sibling.Start && lineStart < sibling.End {
+				continue lines
+			}
+		}
+		trimmed := strings.TrimSpace(line)
+		commented, ok := strings.CutPrefix(trimmed, "//")
+		if !ok {
+			continue
+		}
+		nodes, err := ParseKDL(commented)
+		if err != nil || len(nodes) != 1 || !matchesKDL(nodes[0], sel) {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		lineEnd := lineStart + len(strings.TrimRight(line, "\r\n"))
+		return content[:lineStart] + indent + kdlReindent(node, indent) + content[lineEnd:], true
+	}
+	return "", false
+}
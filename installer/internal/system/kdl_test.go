@@ -0,0 +1,177 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseKDL(t *testing.T) {
+	content := `// comment
+keybinds clear-defaults=true {
+    locked {
+        bind "Ctrl a" { SwitchToMode "tmux"; }
+    }
+    /- pane {
+        bind "x" { CloseFocus; }
+    }
+}
+/* block /* nested */ comment */
+plugins {
+    tab-bar location="zellij:tab-bar"
+}
+layout_dir r#"C:\layouts"# /-"skipped" "kept"
+default_shell \
+    "fi\"sh\u{1F41F}"
+`
+	nodes, err := ParseKDL(content)
+	if err != nil {
+		t.Fatalf("ParseKDL: %v", err)
+	}
+	if len(nodes) != 4 {
+		t.Fatalf("expected 4 top-level nodes, got %d", len(nodes))
+	}
+	keybinds := nodes[0]
+	if keybinds.Props["clear-defaults"] != "true" || len(keybinds.Children) != 1 {
+		t.Errorf("unexpected keybinds %+v", keybinds)
+	}
+	bind := FindKDLNode(nodes, "keybinds", "locked", `bind "Ctrl a"`)
+	if bind == nil || content[bind.Start:bind.End] != `bind "Ctrl a" { SwitchToMode "tmux"; }` {
+		t.Errorf("expected the Ctrl a binding with its span, got %+v", bind)
+	}
+	if node := FindKDLNode(nodes, "plugins", "tab-bar"); node == nil || node.Props["location"] != "zellij:tab-bar" {
+		t.Errorf("expected the tab-bar alias, got %+v", node)
+	}
+	if args := nodes[2].Args; len(args) != 2 || args[0] != `C:\layouts` || args[1] != "kept" {
+		t.Errorf("unexpected layout_dir args %q", args)
+	}
+	if args := nodes[3].Args; len(args) != 1 || args[0] != "fi\"sh🐟" {
+		t.Errorf("unexpected default_shell args %q", args)
+	}
+
+	if _, err := ParseKDL("themes {\n    dark {\n"); err == nil {
+		t.Error("expected an error for an unclosed block")
+	}
+}
+
+func TestParseKDL_ShippedConfig(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "GentlemanZellij", "zellij", "config.kdl"))
+	if err != nil {
+		t.Fatalf("reading shipped config: %v", err)
+	}
+	nodes, err := ParseKDL(string(data))
+	if err != nil {
+		t.Fatalf("ParseKDL: %v", err)
+	}
+	if node := FindKDLNode(nodes, "default_layout"); node == nil || node.Args[0] != "work_kanagawa" {
+		t.Errorf("expected default_layout work_kanagawa, got %+v", node)
+	}
+	if FindKDLNode(nodes, "keybinds", "locked", `bind "Ctrl a"`) == nil {
+		t.Error("expected the locked mode Ctrl a binding")
+	}
+	if FindKDLNode(nodes, "default_shell") != nil {
+		t.Error("the commented default_shell must not be parsed as a node")
+	}
+}
+
+func TestSetKDLString(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"replaces in place", "theme \"a\"\ndefault_layout \"work\" // start here\npane_frames false\n",
+			"theme \"a\"\ndefault_layout \"dev\" // start here\npane_frames false\n"},
+		{"drops duplicates", "default_layout \"a\"\nmouse_mode true\ndefault_layout \"b\"\n",
+			"default_layout \"dev\"\nmouse_mode true\n"},
+		{"takes over a template", "// The default layout\n// \n// default_layout \"work\"\n\npane_frames false\n",
+			"// The default layout\n// \ndefault_layout \"dev\"\n\npane_frames false\n"},
+		{"appends", "pane_frames false", "pane_frames false\ndefault_layout \"dev\"\n"},
+		{"ignores nested names", "ui {\n    default_layout \"x\"\n}\n", "ui {\n    default_layout \"x\"\n}\ndefault_layout \"dev\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetKDLString(tt.content, "default_layout", "dev")
+			if err != nil {
+				t.Fatalf("SetKDLString: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+			again, _ := SetKDLString(got, "default_layout", "dev")
+			if again != got {
+				t.Errorf("not idempotent:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestSetKDLNode_Keybinds(t *testing.T) {
+	content := "keybinds clear-defaults=true {\n    locked {\n        bind \"Ctrl a\" { SwitchToMode \"tmux\"; }\n    }\n}\n"
+
+	got, err := SetKDLNode(content, []string{"keybinds", "locked", `bind "Ctrl a"`}, `bind "Ctrl a" { SwitchToMode "normal"; }`)
+	if err != nil {
+		t.Fatalf("SetKDLNode: %v", err)
+	}
+	if want := strings.Replace(content, `"tmux"`, `"normal"`, 1); got != want {
+		t.Errorf("expected the binding replaced in place, got:\n%s", got)
+	}
+
+	got, err = SetKDLNode(got, []string{"keybinds", "locked", `bind "Ctrl g"`}, `bind "Ctrl g" { SwitchToMode "normal"; }`)
+	if err != nil {
+		t.Fatalf("SetKDLNode: %v", err)
+	}
+	if !strings.Contains(got, "{ SwitchToMode \"normal\"; }\n        bind \"Ctrl g\" { SwitchToMode \"normal\"; }\n    }\n}\n") {
+		t.Errorf("expected the binding appended to the mode, got:\n%s", got)
+	}
+
+	got, err = SetKDLNode(got, []string{"keybinds", "tmux", `bind "x"`}, `bind "x" { CloseFocus; }`)
+	if err != nil {
+		t.Fatalf("SetKDLNode: %v", err)
+	}
+	if !strings.HasSuffix(got, "    }\n    tmux {\n        bind \"x\" { CloseFocus; }\n    }\n}\n") {
+		t.Errorf("expected the missing mode to be created, got:\n%s", got)
+	}
+	if nodes, err := ParseKDL(got); err != nil || FindKDLNode(nodes, "keybinds", "tmux", `bind "x"`) == nil {
+		t.Errorf("result must parse back: %v", err)
+	}
+}
+
+func TestSetZellijDefaultShell_ShippedConfig(t *testing.T) {
+	original, err := os.ReadFile(filepath.Join("..", "..", "..", "GentlemanZellij", "zellij", "config.kdl"))
+	if err != nil {
+		t.Fatalf("reading shipped config: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "config.kdl")
+	os.WriteFile(path, original, 0644)
+
+	for _, shell := range []string{"zsh", "fish", "fish"} {
+		if err := SetZellijDefaultShell(path, shell); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	want := strings.Replace(string(original), `// default_shell "fish"`, `default_shell "fish"`, 1)
+	if string(data) != want {
+		t.Error("expected only the commented default_shell to be enabled")
+	}
+}
+
+func TestZellijLayouts(t *testing.T) {
+	layouts, err := ZellijLayouts(filepath.Join("..", "..", "..", "GentlemanZellij", "zellij", "layouts"))
+	if err != nil {
+		t.Fatalf("ZellijLayouts: %v", err)
+	}
+	if len(layouts) == 0 || layouts[0] != "work" || !slices.Contains(layouts, "work_kanagawa") {
+		t.Errorf("unexpected layouts %v", layouts)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.kdl")
+	os.WriteFile(path, []byte("default_layout \"work\"\n"), 0644)
+	if err := SetZellijDefaultLayout(path, "work_sakura"); err != nil {
+		t.Fatal(err)
+	}
+	if layout, err := ZellijOption(path, "default_layout"); err != nil || layout != "work_sakura" {
+		t.Errorf("expected work_sakura, got %q (%v)", layout, err)
+	}
+}
@@ -0,0 +1,142 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultLinkRepoDir returns the persistent clone location used by link mode
+func DefaultLinkRepoDir() string {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "gentleman-dots")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".local", "share", "gentleman-dots")
+}
+
+// OverridesDir returns the directory holding installer-owned override files.
+// Linked configs source these files so installer patches never touch the repo.
+func OverridesDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "gentleman")
+}
+
+// IsLinkedTo reports whether dst is a symlink pointing at src
+func IsLinkedTo(src, dst string) bool {
+	target, err := os.Readlink(dst)
+	if err != nil {
+		return false
+	}
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return false
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(dst), target)
+	}
+	return filepath.Clean(target) == absSrc
+}
+
+// LinkFile replaces dst with a symlink to src.
+// Existing files are removed; callers are expected to have backed them up.
+func LinkFile(src, dst string) error {
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(absSrc); err != nil {
+		return err
+	}
+	if IsLinkedTo(absSrc, dst) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return fmt.Errorf("link %s: failed to remove existing target: %w", dst, err)
+	}
+	return os.Symlink(absSrc, dst)
+}
+
+// LinkTree links src into dst the way GNU stow does.
+// When dst does not exist (or is a foreign symlink) the whole directory is
+// linked in one piece. When dst is a real directory it is "unfolded": each
+// entry of src is linked individually so files unknown to the repo survive.
+func LinkTree(src, dst string) error {
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	srcInfo, err := os.Stat(absSrc)
+	if err != nil {
+		return err
+	}
+	if !srcInfo.IsDir() {
+		return LinkFile(absSrc, dst)
+	}
+
+	dstInfo, err := os.Lstat(dst)
+	if os.IsNotExist(err) || (err == nil && dstInfo.Mode()&os.ModeSymlink != 0) {
+		return LinkFile(absSrc, dst)
+	}
+	if err != nil {
+		return err
+	}
+	if !dstInfo.IsDir() {
+		return LinkFile(absSrc, dst)
+	}
+
+	entries, err := os.ReadDir(absSrc)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := LinkTree(filepath.Join(absSrc, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteOverride writes an installer-owned override file under OverridesDir
+func WriteOverride(name string, content string) (string, error) {
+	dir := OverridesDir()
+	if err := EnsureDir(dir); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	return path, os.WriteFile(path, []byte(content), 0644)
+}
+
+// ZshWMOverride renders the override sourced by a linked .zshrc (or .bashrc) to select
+// the multiplexer started by start_if_needed.
+func ZshWMOverride(wm string) string {
+	header := "# Written by the Gentleman.Dots installer (--link mode). Do not edit.\n"
+	switch wm {
+	case "tmux":
+		return header + "WM_VAR=\"/$TMUX\"\nWM_CMD=\"tmux\"\n"
+	case "zellij":
+		return header + "WM_VAR=\"$ZELLIJ\"\nWM_CMD=\"zellij\"\n"
+	case "herdr":
+		return header + "WM_VAR=\"$HERDR_ENV\"\nWM_CMD=\"herdr\"\n"
+	default:
+		return header + "function start_if_needed() { :; }\n"
+	}
+}
+
+// FishWMOverride renders the override sourced by a linked config.fish.
+// It starts the chosen multiplexer itself and marks the shipped block as handled.
+func FishWMOverride(wm string) string {
+	lines := []string{"# Written by the Gentleman.Dots installer (--link mode). Do not edit."}
+	if wm != "none" && wm != "" {
+		lines = append(lines, fishMultiplexerBlock(wm)...)
+	}
+	lines = append(lines, "set -g GENTLEMAN_WM_HANDLED 1")
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// TmuxShellOverride renders the override sourced by a linked tmux.conf
+func TmuxShellOverride(shellPath string) string {
+	return fmt.Sprintf("# Written by the Gentleman.Dots installer (--link mode). Do not edit.\nset -g default-command \"%s\"\nset -g default-shell \"%s\"\n", shellPath, shellPath)
+}
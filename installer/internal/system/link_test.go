@@ -0,0 +1,96 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLinkTreeLinksWholeDirectoryWhenTargetMissing(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "repo", "fish")
+	dst := filepath.Join(tmp, "home", ".config", "fish")
+	os.MkdirAll(filepath.Join(src, "functions"), 0755)
+	os.WriteFile(filepath.Join(src, "config.fish"), []byte("set -g x 1\n"), 0644)
+
+	if err := LinkTree(src, dst); err != nil {
+		t.Fatalf("LinkTree failed: %v", err)
+	}
+
+	if !IsLinkedTo(src, dst) {
+		t.Fatalf("expected %s to be a symlink to %s", dst, src)
+	}
+}
+
+func TestLinkTreeUnfoldsExistingDirectory(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "repo", "zellij")
+	dst := filepath.Join(tmp, "home", ".config", "zellij")
+	os.MkdirAll(filepath.Join(src, "layouts"), 0755)
+	os.WriteFile(filepath.Join(src, "config.kdl"), []byte("theme \"kanagawa\"\n"), 0644)
+	os.MkdirAll(dst, 0755)
+	os.WriteFile(filepath.Join(dst, "local.kdl"), []byte("// mine\n"), 0644)
+
+	if err := LinkTree(src, dst); err != nil {
+		t.Fatalf("LinkTree failed: %v", err)
+	}
+
+	if IsLinkedTo(src, dst) {
+		t.Fatal("existing directory should be unfolded, not replaced")
+	}
+	if !IsLinkedTo(filepath.Join(src, "config.kdl"), filepath.Join(dst, "config.kdl")) {
+		t.Error("config.kdl should be linked into the existing directory")
+	}
+	if !IsLinkedTo(filepath.Join(src, "layouts"), filepath.Join(dst, "layouts")) {
+		t.Error("layouts should be linked into the existing directory")
+	}
+	if _, err := os.Stat(filepath.Join(dst, "local.kdl")); err != nil {
+		t.Error("files unknown to the repo must survive unfolding")
+	}
+}
+
+func TestLinkFileReplacesExistingFileAndIsIdempotent(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "repo", ".zshrc")
+	dst := filepath.Join(tmp, "home", ".zshrc")
+	os.MkdirAll(filepath.Dir(src), 0755)
+	os.MkdirAll(filepath.Dir(dst), 0755)
+	os.WriteFile(src, []byte("repo\n"), 0644)
+	os.WriteFile(dst, []byte("old\n"), 0644)
+
+	for i := 0; i < 2; i++ {
+		if err := LinkFile(src, dst); err != nil {
+			t.Fatalf("LinkFile run %d failed: %v", i+1, err)
+		}
+	}
+
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("reading linked file: %v", err)
+	}
+	if string(data) != "repo\n" {
+		t.Errorf("expected linked content, got %q", string(data))
+	}
+}
+
+func TestWMOverrides(t *testing.T) {
+	if got := ZshWMOverride("zellij"); !strings.Contains(got, `WM_CMD="zellij"`) || !strings.Contains(got, `WM_VAR="$ZELLIJ"`) {
+		t.Errorf("zsh zellij override missing WM settings:\n%s", got)
+	}
+	if got := ZshWMOverride("none"); !strings.Contains(got, "function start_if_needed() { :; }") {
+		t.Errorf("zsh none override should disable start_if_needed:\n%s", got)
+	}
+
+	fish := FishWMOverride("herdr")
+	if !strings.Contains(fish, "command -q herdr") || !strings.Contains(fish, "set -g GENTLEMAN_WM_HANDLED 1") {
+		t.Errorf("fish herdr override incomplete:\n%s", fish)
+	}
+	if fish := FishWMOverride("none"); strings.Contains(fish, "if status is-interactive") {
+		t.Errorf("fish none override should not start a multiplexer:\n%s", fish)
+	}
+
+	if got := TmuxShellOverride("/usr/bin/fish"); !strings.Contains(got, `set -g default-shell "/usr/bin/fish"`) {
+		t.Errorf("tmux override missing default-shell:\n%s", got)
+	}
+}
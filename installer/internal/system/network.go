@@ -0,0 +1,304 @@
+package system
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// NetworkConfig holds the proxy, CA and mirror settings applied to every fetch
+type NetworkConfig struct {
+	HTTPProxy        string
+	HTTPSProxy       string
+	NoProxy          string
+	CABundle         string // extra PEM certificates, trusted on top of the system store
+	GitHubMirror     string // replaces https://github.com in clones and downloads
+	BrewBottleMirror string // HOMEBREW_BOTTLE_DOMAIN
+	PackageMirror    string // distro package server checked before installing
+}
+
+var (
+	network       NetworkConfig
+	networkCAFile string // system roots + CABundle, handed to child processes
+)
+
+// SetNetwork makes cfg apply to every command and download that follows
+func SetNetwork(cfg NetworkConfig) error {
+	cfg.GitHubMirror = strings.TrimRight(cfg.GitHubMirror, "/")
+	cfg.BrewBottleMirror = strings.TrimRight(cfg.BrewBottleMirror, "/")
+	cfg.PackageMirror = strings.TrimRight(cfg.PackageMirror, "/")
+
+	caFile := ""
+	if cfg.CABundle != "" {
+		var err error
+		if caFile, err = buildCABundle(cfg.CABundle); err != nil {
+			return err
+		}
+	}
+	network, networkCAFile = cfg, caFile
+	return nil
+}
+
+// Network returns the active network configuration
+func Network() NetworkConfig {
+	return network
+}
+
+// Merge returns c with every non-empty field of o applied on top
+func (c NetworkConfig) Merge(o NetworkConfig) NetworkConfig {
+	pick := func(base, override string) string {
+		if override != "" {
+			return override
+		}
+		return base
+	}
+	return NetworkConfig{
+		HTTPProxy:        pick(c.HTTPProxy, o.HTTPProxy),
+		HTTPSProxy:       pick(c.HTTPSProxy, o.HTTPSProxy),
+		NoProxy:          pick(c.NoProxy, o.NoProxy),
+		CABundle:         pick(c.CABundle, o.CABundle),
+		GitHubMirror:     pick(c.GitHubMirror, o.GitHubMirror),
+		BrewBottleMirror: pick(c.BrewBottleMirror, o.BrewBottleMirror),
+		PackageMirror:    pick(c.PackageMirror, o.PackageMirror),
+	}
+}
+
+// NetworkFromEnv reads the conventional proxy and Homebrew variables
+func NetworkFromEnv() NetworkConfig {
+	env := func(names ...string) string {
+		for _, name := range names {
+			if v := os.Getenv(name); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	return NetworkConfig{
+		HTTPProxy:        env("HTTP_PROXY", "http_proxy"),
+		HTTPSProxy:       env("HTTPS_PROXY", "https_proxy"),
+		NoProxy:          env("NO_PROXY", "no_proxy"),
+		BrewBottleMirror: env("HOMEBREW_BOTTLE_DOMAIN"),
+	}
+}
+
+// NetworkProfilePath is the default network profile location
+func NetworkProfilePath() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "gentleman", "network.conf")
+}
+
+// LoadNetworkProfile parses `key = value` lines; a missing file is not an error
+func LoadNetworkProfile(path string) (NetworkConfig, error) {
+	var cfg NetworkConfig
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	defer f.Close()
+
+	fields := map[string]*string{
+		"http_proxy":         &cfg.HTTPProxy,
+		"https_proxy":        &cfg.HTTPSProxy,
+		"no_proxy":           &cfg.NoProxy,
+		"ca_bundle":          &cfg.CABundle,
+		"github_mirror":      &cfg.GitHubMirror,
+		"brew_bottle_mirror": &cfg.BrewBottleMirror,
+		"package_mirror":     &cfg.PackageMirror,
+	}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "proxy" && ok {
+			// Shorthand for both schemes
+			cfg.HTTPProxy = strings.TrimSpace(value)
+			cfg.HTTPSProxy = cfg.HTTPProxy
+			continue
+		}
+		field, known := fields[key]
+		if !ok || !known {
+			return cfg, fmt.Errorf("%s:%d: unknown setting %q", path, n, line)
+		}
+		*field = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return cfg, scanner.Err()
+}
+
+// NetworkEnv returns the variables child processes need to use the network config
+func NetworkEnv() []string {
+	c := network
+	var env []string
+	if c.HTTPProxy != "" {
+		env = append(env, "HTTP_PROXY="+c.HTTPProxy, "http_proxy="+c.HTTPProxy)
+	}
+	if c.HTTPSProxy != "" {
+		env = append(env, "HTTPS_PROXY="+c.HTTPSProxy, "https_proxy="+c.HTTPSProxy)
+	}
+	if c.NoProxy != "" {
+		env = append(env, "NO_PROXY="+c.NoProxy, "no_proxy="+c.NoProxy)
+	}
+	if networkCAFile != "" {
+		// curl, git, Python, Homebrew and Go replace their roots; Node appends
+		env = append(env,
+			"SSL_CERT_FILE="+networkCAFile,
+			"CURL_CA_BUNDLE="+networkCAFile,
+			"GIT_SSL_CAINFO="+networkCAFile,
+			"REQUESTS_CA_BUNDLE="+networkCAFile,
+			"NODE_EXTRA_CA_CERTS="+c.CABundle,
+		)
+	}
+	if c.GitHubMirror != "" {
+		// Rewrites every git clone, including TPM and plugin managers
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=url."+c.GitHubMirror+"/.insteadOf",
+			"GIT_CONFIG_VALUE_0=https://github.com/",
+		)
+	}
+	if c.BrewBottleMirror != "" {
+		env = append(env, "HOMEBREW_BOTTLE_DOMAIN="+c.BrewBottleMirror)
+	}
+	return env
+}
+
+// sudoPreserveEnv keeps the network variables across sudo, which resets the environment
+func sudoPreserveEnv() string {
+	env := NetworkEnv()
+	if len(env) == 0 {
+		return ""
+	}
+	names := make([]string, len(env))
+	for i, kv := range env {
+		names[i], _, _ = strings.Cut(kv, "=")
+	}
+	return "--preserve-env=" + strings.Join(names, ",") + " "
+}
+
+// SudoShellWrapper defines a sudo function for scripts so every sudo call in
+// them keeps the network variables. Empty when nothing is configured.
+func SudoShellWrapper() string {
+	preserve := sudoPreserveEnv()
+	if preserve == "" {
+		return ""
+	}
+	return fmt.Sprintf("sudo() { command sudo %s\"$@\"; }\n", preserve)
+}
+
+// GitHubURL points a github.com or raw.githubusercontent.com URL at the mirror
+func GitHubURL(u string) string {
+	mirror := network.GitHubMirror
+	if mirror == "" {
+		return u
+	}
+	if rest, ok := strings.CutPrefix(u, "https://github.com/"); ok {
+		return mirror + "/" + rest
+	}
+	if rest, ok := strings.CutPrefix(u, "https://raw.githubusercontent.com/"); ok {
+		// owner/repo/ref/path -> owner/repo/raw/ref/path
+		parts := strings.SplitN(rest, "/", 3)
+		if len(parts) == 3 {
+			return mirror + "/" + parts[0] + "/" + parts[1] + "/raw/" + parts[2]
+		}
+	}
+	return u
+}
+
+// HTTPClient returns a client that honors the proxy and CA settings
+func HTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	cfg := network
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return cfg.proxyFor(req.URL)
+	}
+	if networkCAFile != "" {
+		if pem, err := os.ReadFile(networkCAFile); err == nil {
+			pool := x509.NewCertPool()
+			pool.AppendCertsFromPEM(pem)
+			transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		}
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// proxyFor picks the proxy for u, honoring NO_PROXY
+func (c NetworkConfig) proxyFor(u *url.URL) (*url.URL, error) {
+	proxy := c.HTTPSProxy
+	if u.Scheme == "http" {
+		proxy = c.HTTPProxy
+	}
+	if proxy == "" || noProxyMatches(c.NoProxy, u.Hostname()) {
+		return nil, nil
+	}
+	return url.Parse(proxy)
+}
+
+// noProxyMatches applies curl's NO_PROXY rules: exact hosts, domain suffixes and "*"
+func noProxyMatches(noProxy, host string) bool {
+	host = strings.ToLower(host)
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		domain := strings.TrimPrefix(entry, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// systemCABundles are the usual trust store locations across platforms
+var systemCABundles = []string{
+	"/etc/ssl/certs/ca-certificates.crt", // Debian, Arch, Alpine
+	"/etc/pki/tls/certs/ca-bundle.crt",   // Fedora/RHEL
+	"/etc/ssl/ca-bundle.pem",             // openSUSE
+	"/etc/ssl/cert.pem",                  // macOS, Alpine
+	"/data/data/com.termux/files/usr/etc/tls/cert.pem",
+}
+
+// buildCABundle writes the system roots plus extra into one PEM file, since
+// most tools replace rather than extend their trust store
+func buildCABundle(extra string) (string, error) {
+	custom, err := os.ReadFile(extra)
+	if err != nil {
+		return "", fmt.Errorf("reading CA bundle: %w", err)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(custom) {
+		return "", fmt.Errorf("CA bundle %s contains no PEM certificates", extra)
+	}
+
+	var bundle []byte
+	for _, path := range systemCABundles {
+		if data, err := os.ReadFile(path); err == nil {
+			bundle = append(data, '\n')
+			break
+		}
+	}
+	bundle = append(bundle, custom...)
+
+	dest := filepath.Join(CacheDir(), "ca-bundle.pem")
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(dest, bundle, 0644); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
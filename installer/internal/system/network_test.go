@@ -0,0 +1,167 @@
+package system
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withNetwork applies cfg for the duration of the test
+func withNetwork(t *testing.T, cfg NetworkConfig) {
+	t.Helper()
+	original, originalCA := network, networkCAFile
+	t.Cleanup(func() { network, networkCAFile = original, originalCA })
+	if err := SetNetwork(cfg); err != nil {
+		t.Fatalf("SetNetwork: %v", err)
+	}
+}
+
+func TestGitHubURL(t *testing.T) {
+	withNetwork(t, NetworkConfig{})
+	if got := GitHubURL("https://github.com/a/b"); got != "https://github.com/a/b" {
+		t.Errorf("expected URL unchanged without a mirror, got %s", got)
+	}
+
+	withNetwork(t, NetworkConfig{GitHubMirror: "https://git.corp/github/"})
+	tests := map[string]string{
+		"https://github.com/ryanoasis/nerd-fonts/releases/download/v3.3.0/IosevkaTerm.zip": "https://git.corp/github/ryanoasis/nerd-fonts/releases/download/v3.3.0/IosevkaTerm.zip",
+		"https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh":               "https://git.corp/github/Homebrew/install/raw/HEAD/install.sh",
+		"https://api.github.com/repos/kovidgoyal/kitty/releases/tags/v0.42.2":              "https://api.github.com/repos/kovidgoyal/kitty/releases/tags/v0.42.2",
+		"https://sh.rustup.rs": "https://sh.rustup.rs",
+	}
+	for in, want := range tests {
+		if got := GitHubURL(in); got != want {
+			t.Errorf("GitHubURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLoadNetworkProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "network.conf")
+	os.WriteFile(path, []byte(`# corporate network
+proxy = http://proxy.corp:3128
+no_proxy = localhost,.corp
+github_mirror = "https://git.corp/github"
+`), 0644)
+
+	cfg, err := LoadNetworkProfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.HTTPProxy != "http://proxy.corp:3128" || cfg.HTTPSProxy != cfg.HTTPProxy {
+		t.Errorf("expected proxy shorthand to set both schemes, got %+v", cfg)
+	}
+	if cfg.GitHubMirror != "https://git.corp/github" || cfg.NoProxy != "localhost,.corp" {
+		t.Errorf("unexpected profile: %+v", cfg)
+	}
+
+	if _, err := LoadNetworkProfile(filepath.Join(t.TempDir(), "missing.conf")); err != nil {
+		t.Errorf("missing profile should not be an error, got %v", err)
+	}
+	os.WriteFile(path, []byte("proxxy = typo\n"), 0644)
+	if _, err := LoadNetworkProfile(path); err == nil || !strings.Contains(err.Error(), ":1:") {
+		t.Errorf("expected an error with the line number, got %v", err)
+	}
+}
+
+func TestNetworkConfigMerge(t *testing.T) {
+	env := NetworkConfig{HTTPSProxy: "http://env:1", NoProxy: "localhost"}
+	got := env.Merge(NetworkConfig{HTTPSProxy: "http://flag:2"})
+	if got.HTTPSProxy != "http://flag:2" || got.NoProxy != "localhost" {
+		t.Errorf("unexpected merge result: %+v", got)
+	}
+}
+
+func TestNetworkEnvAndSudo(t *testing.T) {
+	withNetwork(t, NetworkConfig{})
+	if env := NetworkEnv(); len(env) != 0 || SudoShellWrapper() != "" {
+		t.Errorf("expected no env without configuration, got %v", env)
+	}
+
+	withNetwork(t, NetworkConfig{HTTPSProxy: "http://proxy:3128", GitHubMirror: "https://git.corp/github", BrewBottleMirror: "https://bottles.corp"})
+	env := strings.Join(NetworkEnv(), "\n")
+	for _, want := range []string{
+		"https_proxy=http://proxy:3128",
+		"GIT_CONFIG_KEY_0=url.https://git.corp/github/.insteadOf",
+		"GIT_CONFIG_VALUE_0=https://github.com/",
+		"HOMEBREW_BOTTLE_DOMAIN=https://bottles.corp",
+	} {
+		if !strings.Contains(env, want) {
+			t.Errorf("expected %q in:\n%s", want, env)
+		}
+	}
+	if wrapper := SudoShellWrapper(); !strings.Contains(wrapper, "--preserve-env=HTTPS_PROXY,https_proxy,") {
+		t.Errorf("expected sudo wrapper to preserve proxy variables, got %q", wrapper)
+	}
+}
+
+func TestProxyFor_NoProxy(t *testing.T) {
+	cfg := NetworkConfig{HTTPSProxy: "http://proxy:3128", NoProxy: "localhost, .corp"}
+	for host, bypass := range map[string]bool{
+		"github.com":         false,
+		"mirror.corp":        true,
+		"corp":               true,
+		"localhost":          true,
+		"notcorp.example.io": false,
+	} {
+		proxy, _ := cfg.proxyFor(&url.URL{Scheme: "https", Host: host})
+		if (proxy == nil) != bypass {
+			t.Errorf("%s: expected bypass=%v, got proxy %v", host, bypass, proxy)
+		}
+	}
+}
+
+func TestSetNetwork_CABundle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	bad := filepath.Join(dir, "bad.pem")
+	os.WriteFile(bad, []byte("not a certificate"), 0644)
+	if err := SetNetwork(NetworkConfig{CABundle: bad}); err == nil {
+		t.Fatal("expected an error for a bundle without certificates")
+	}
+
+	good := filepath.Join(dir, "corp.pem")
+	os.WriteFile(good, selfSignedPEM(t), 0644)
+	withNetwork(t, NetworkConfig{CABundle: good})
+
+	env := strings.Join(NetworkEnv(), "\n")
+	if !strings.Contains(env, "NODE_EXTRA_CA_CERTS="+good) || !strings.Contains(env, "GIT_SSL_CAINFO="+networkCAFile) {
+		t.Errorf("expected CA variables, got:\n%s", env)
+	}
+	combined, _ := os.ReadFile(networkCAFile)
+	custom, _ := os.ReadFile(good)
+	if !strings.HasSuffix(string(combined), string(custom)) {
+		t.Error("expected the custom CA appended to the combined bundle")
+	}
+}
+
+func selfSignedPEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Corp Root"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
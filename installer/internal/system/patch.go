@@ -0,0 +1,471 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Managed block names used by the shell config patchers
+const (
+	BlockMultiplexer      = "multiplexer"
+	BlockMultiplexerStart = "multiplexer-start"
+	BlockFzf              = "fzf"
+)
+
+// PatchZshForWM modifies .zshrc based on window manager choice.
+// Installer-owned sections live in managed blocks, so running it again
+// (or with a different WM) rewrites them in place instead of duplicating.
+func PatchZshForWM(zshrcPath string, wm string, installNvim bool) error {
+	content, err := os.ReadFile(zshrcPath)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	lines = adoptLegacy(lines, SyntaxSh, BlockMultiplexer, zshLegacyMultiplexer)
+	lines = adoptLegacy(lines, SyntaxSh, BlockMultiplexerStart, func(lines []string, i int) int {
+		if strings.TrimSpace(lines[i]) == "start_if_needed" {
+			return 1
+		}
+		return 0
+	})
+	lines = adoptLegacy(lines, SyntaxSh, BlockFzf, zshLegacyFzf)
+
+	startBody := ""
+	if wm != "none" {
+		startBody = "start_if_needed"
+	}
+	fzfBody := `eval "$(fzf --zsh)"`
+	if !installNvim {
+		fzfBody = "if command -v fzf &> /dev/null; then\n" + fzfBody + "\nfi"
+	}
+
+	return patchBlocks(zshrcPath, strings.Join(lines, "\n"), SyntaxSh, []managedBlock{
+		{name: BlockMultiplexer, body: shMultiplexerBody(wm)},
+		{name: BlockMultiplexerStart, body: startBody},
+		{name: BlockFzf, body: fzfBody, onlyIfPresent: true},
+	})
+}
+
+// shMultiplexerBody renders the start_if_needed setup shared by zsh and bash
+func shMultiplexerBody(wm string) string {
+	var wmVar string
+	switch wm {
+	case "tmux":
+		wmVar = `"/$TMUX"`
+	case "zellij":
+		wmVar = `"$ZELLIJ"`
+	case "herdr":
+		wmVar = `"$HERDR_ENV"`
+	default:
+		return ""
+	}
+	return strings.Join([]string{
+		"WM_VAR=" + wmVar,
+		fmt.Sprintf("WM_CMD=%q", wm),
+		"",
+		"function start_if_needed() {",
+		`    if [[ $- == *i* ]] && command -v "$WM_CMD" >/dev/null 2>&1 && [[ -z "${WM_VAR#/}" ]] && [[ -z "$TMUX" ]] && [[ -z "$ZELLIJ" ]] && [[ -z "$HERDR_ENV" ]] && [[ -t 1 ]]; then`,
+		"        exec $WM_CMD",
+		"    fi",
+		"}",
+	}, "\n")
+}
+
+// zshLegacyMultiplexer matches the unmarked WM_VAR/WM_CMD/start_if_needed
+// definitions shipped before managed blocks existed.
+func zshLegacyMultiplexer(lines []string, i int) int {
+	trimmed := strings.TrimSpace(lines[i])
+	switch {
+	case strings.HasPrefix(trimmed, "WM_VAR="), strings.HasPrefix(trimmed, "WM_CMD="):
+		return 1
+	case strings.HasPrefix(trimmed, "# change with "):
+		return 1
+	case strings.HasPrefix(trimmed, "function start_if_needed"):
+		if strings.Contains(trimmed, "}") {
+			return 1
+		}
+		return linesUntil(lines, i, "}")
+	}
+	return 0
+}
+
+func zshLegacyFzf(lines []string, i int) int {
+	if wrappedLine(lines, i, "if command -v fzf &> /dev/null; then", `eval "$(fzf --zsh)"`, "fi") {
+		return 3
+	}
+	if strings.Contains(lines[i], `eval "$(fzf --zsh)"`) {
+		return 1
+	}
+	return 0
+}
+
+// PatchBashForWM modifies .bashrc based on window manager choice.
+// The Gentleman .bashrc shipped with markers from the start, so there is
+// no legacy content to adopt.
+func PatchBashForWM(bashrcPath string, wm string, installNvim bool) error {
+	content, err := os.ReadFile(bashrcPath)
+	if err != nil {
+		return err
+	}
+
+	startBody := ""
+	if wm != "none" {
+		startBody = "start_if_needed"
+	}
+	fzfBody := `eval "$(fzf --bash)"`
+	if !installNvim {
+		fzfBody = "if command -v fzf &> /dev/null; then\n" + fzfBody + "\nfi"
+	}
+
+	return patchBlocks(bashrcPath, string(content), SyntaxSh, []managedBlock{
+		{name: BlockMultiplexer, body: shMultiplexerBody(wm)},
+		{name: BlockMultiplexerStart, body: startBody},
+		{name: BlockFzf, body: fzfBody, onlyIfPresent: true},
+	})
+}
+
+// PatchFishForWM modifies config.fish based on window manager choice.
+func PatchFishForWM(configPath string, wm string, installNvim bool) error {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	lines = adoptLegacy(lines, SyntaxFish, BlockMultiplexer, fishLegacyMultiplexer)
+	lines = adoptLegacy(lines, SyntaxFish, BlockFzf, fishLegacyFzf)
+
+	multiplexerBody := ""
+	if wm != "none" {
+		multiplexerBody = strings.Join(fishMultiplexerBlock(wm), "\n")
+	}
+	fzfBody := "fzf --fish | source"
+	if !installNvim {
+		fzfBody = "if command -v fzf &> /dev/null\n" + fzfBody + "\nend"
+	}
+
+	return patchBlocks(configPath, strings.Join(lines, "\n"), SyntaxFish, []managedBlock{
+		{name: BlockMultiplexer, body: multiplexerBody},
+		{name: BlockFzf, body: fzfBody, onlyIfPresent: true},
+	})
+}
+
+func fishMultiplexerBlock(wm string) []string {
+	switch wm {
+	case "zellij":
+		return []string{
+			"# Start selected terminal multiplexer",
+			"if status is-interactive; and command -q zellij; and not set -q TMUX; and not set -q ZELLIJ; and not set -q HERDR_ENV; and not set -q GENTLEMAN_WM_HANDLED",
+			"    zellij attach -c main",
+			"end",
+		}
+	case "herdr":
+		return []string{
+			"# Start selected terminal multiplexer",
+			"if status is-interactive; and command -q herdr; and not set -q HERDR_ENV; and not set -q TMUX; and not set -q ZELLIJ; and not set -q GENTLEMAN_WM_HANDLED",
+			"    herdr; or echo \"⚠️  Herdr failed to start; continuing in Fish.\"",
+			"end",
+		}
+	default:
+		return []string{
+			"# Start selected terminal multiplexer",
+			"if status is-interactive; and command -q tmux; and not set -q TMUX; and not set -q ZELLIJ; and not set -q HERDR_ENV; and not set -q GENTLEMAN_WM_HANDLED",
+			"    tmux new-session -A -s main",
+			"end",
+		}
+	}
+}
+
+// fishLegacyMultiplexer matches the unmarked multiplexer start blocks,
+// including the commented-out alternatives older configs shipped with.
+func fishLegacyMultiplexer(lines []string, i int) int {
+	trimmed := strings.TrimSpace(lines[i])
+	switch {
+	case trimmed == "# Start tmux/zellij (tmux first, zellij as fallback)",
+		trimmed == "# Start selected terminal multiplexer":
+		return 1
+	case trimmed == "if not set -q TMUX",
+		strings.HasPrefix(trimmed, "if status is-interactive; and") &&
+			(strings.Contains(trimmed, "TMUX") || strings.Contains(trimmed, "ZELLIJ") || strings.Contains(trimmed, "HERDR_ENV")):
+		depth := 0
+		for j := i; j < len(lines); j++ {
+			t := strings.TrimSpace(lines[j])
+			if strings.HasPrefix(t, "if ") {
+				depth++
+			}
+			if t == "end" {
+				depth--
+				if depth <= 0 {
+					return j - i + 1
+				}
+			}
+		}
+		return len(lines) - i
+	case strings.HasPrefix(trimmed, "#if not set -q ZELLIJ"), strings.HasPrefix(trimmed, "#if not set -q HERDR_ENV"):
+		return linesUntil(lines, i, "#end")
+	}
+	return 0
+}
+
+func fishLegacyFzf(lines []string, i int) int {
+	if wrappedLine(lines, i, "if command -v fzf &> /dev/null", "fzf --fish | source", "end") {
+		return 3
+	}
+	if strings.Contains(lines[i], "fzf --fish | source") {
+		return 1
+	}
+	return 0
+}
+
+// PatchNushellForWM modifies config.nu based on window manager choice.
+func PatchNushellForWM(configPath string, wm string) error {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	lines = adoptLegacy(lines, SyntaxNu, BlockMultiplexer, nuLegacyMultiplexer)
+	lines = adoptLegacy(lines, SyntaxNu, BlockMultiplexerStart, func(lines []string, i int) int {
+		if strings.TrimSpace(lines[i]) == "start_multiplexer" {
+			return 1
+		}
+		return 0
+	})
+
+	startBody := ""
+	if wm != "none" {
+		startBody = "start_multiplexer"
+	}
+
+	return patchBlocks(configPath, strings.Join(lines, "\n"), SyntaxNu, []managedBlock{
+		{name: BlockMultiplexer, body: nuMultiplexerBody(wm)},
+		{name: BlockMultiplexerStart, body: startBody},
+	})
+}
+
+func nuMultiplexerBody(wm string) string {
+	var envPrefix string
+	switch wm {
+	case "tmux":
+		envPrefix = "TMUX"
+	case "zellij":
+		envPrefix = "ZELLIJ"
+	case "herdr":
+		envPrefix = "HERDR_ENV"
+	default:
+		return ""
+	}
+	return strings.Join([]string{
+		fmt.Sprintf("let MULTIPLEXER = %q", wm),
+		fmt.Sprintf("let MULTIPLEXER_ENV_PREFIX = %q", envPrefix),
+		"",
+		"def start_multiplexer [] {",
+		"  let active_env = ($env | columns)",
+		`  if (which $MULTIPLEXER | is-not-empty) and $MULTIPLEXER_ENV_PREFIX not-in $active_env and "TMUX" not-in $active_env and "ZELLIJ" not-in $active_env and "HERDR_ENV" not-in $active_env {`,
+		"    run-external $MULTIPLEXER",
+		"  }",
+		"}",
+	}, "\n")
+}
+
+func nuLegacyMultiplexer(lines []string, i int) int {
+	trimmed := strings.TrimSpace(lines[i])
+	switch {
+	case strings.HasPrefix(trimmed, "let MULTIPLEXER ="), strings.HasPrefix(trimmed, "let MULTIPLEXER_ENV_PREFIX ="):
+		return 1
+	case strings.HasPrefix(trimmed, "def start_multiplexer"):
+		if strings.Contains(trimmed, "}") {
+			return 1
+		}
+		return linesUntil(lines, i, "}")
+	}
+	return 0
+}
+
+// wmStartPattern matches the multiplexer selection in every shell's auto-start code
+var wmStartPattern = regexp.MustCompile(`(?:WM_CMD=|let MULTIPLEXER = |command -q )"?(tmux|zellij|herdr)\b`)
+
+// ConfiguredWM reports which multiplexer a patched shell config (or its
+// link-mode override) auto-starts: tmux, zellij, herdr or none.
+// It returns "" when the content carries no installer multiplexer setup.
+func ConfiguredWM(content string, syntax Syntax) string {
+	if start, ok := BlockBody(content, syntax, BlockMultiplexerStart); ok && strings.TrimSpace(start) == "" {
+		return "none"
+	}
+	body, hasBlock := BlockBody(content, syntax, BlockMultiplexer)
+	if !hasBlock {
+		body = content
+	}
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if match := wmStartPattern.FindStringSubmatch(trimmed); match != nil {
+			return match[1]
+		}
+	}
+	if hasBlock || strings.Contains(content, "function start_if_needed() { :; }") ||
+		strings.Contains(content, "set -g GENTLEMAN_WM_HANDLED") {
+		return "none"
+	}
+	return ""
+}
+
+// managedBlock is one block written by patchBlocks
+type managedBlock struct {
+	name string
+	body string
+	// onlyIfPresent skips the block when the file never had it
+	onlyIfPresent bool
+}
+
+// patchBlocks upserts every block into content and writes the result
+func patchBlocks(path, content string, syntax Syntax, blocks []managedBlock) error {
+	var err error
+	for _, b := range blocks {
+		if b.onlyIfPresent && !HasBlock(content, syntax, b.name) {
+			continue
+		}
+		if content, err = UpsertBlock(content, syntax, b.name, b.body); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// linesUntil counts the lines from i through the first line equal to closing.
+// Indented lines never match, so nested closing braces are skipped.
+func linesUntil(lines []string, i int, closing string) int {
+	for j := i + 1; j < len(lines); j++ {
+		if strings.TrimRight(lines[j], " \t") == closing {
+			return j - i + 1
+		}
+	}
+	return len(lines) - i
+}
+
+// wrappedLine reports whether lines[i:i+3] is open, a line containing inner, close
+func wrappedLine(lines []string, i int, open, inner, close string) bool {
+	return i+2 < len(lines) &&
+		strings.TrimSpace(lines[i]) == open &&
+		strings.Contains(lines[i+1], inner) &&
+		strings.TrimSpace(lines[i+2]) == close
+}
+
+// Managed block names used for single-purpose installer settings
+const (
+	BlockHomebrew       = "homebrew"
+	BlockDefaultShell   = "default-shell"
+	BlockShellAutoStart = "shell-autostart"
+)
+
+// SetHomebrewShellenv writes the brew shellenv line into an rc file,
+// replacing copies appended by older installer runs.
+func SetHomebrewShellenv(rcPath string, shellenv string) error {
+	return patchFileBlock(rcPath, SyntaxSh, BlockHomebrew, shellenv, func(lines []string, i int) int {
+		trimmed := strings.TrimSpace(lines[i])
+		if lines[i] == trimmed && strings.HasPrefix(trimmed, `eval "$(`) && strings.HasSuffix(trimmed, `/bin/brew shellenv)"`) {
+			return 1
+		}
+		return 0
+	})
+}
+
+// SetTmuxDefaultShell points tmux at shellPath, filling the
+// GENTLEMAN_DEFAULT_SHELL placeholder on configs that still have it.
+func SetTmuxDefaultShell(tmuxConfPath string, shellPath string) error {
+	body := fmt.Sprintf("set -g default-command \"%s\"\nset -g default-shell \"%s\"", shellPath, shellPath)
+	return patchFileBlock(tmuxConfPath, SyntaxTmux, BlockDefaultShell, body, func(lines []string, i int) int {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "# GENTLEMAN_DEFAULT_SHELL" ||
+			strings.HasPrefix(trimmed, "set -g default-command ") ||
+			strings.HasPrefix(trimmed, "set -g default-shell ") {
+			return 1
+		}
+		return 0
+	})
+}
+
+// SetZellijDefaultShell sets default_shell in a zellij config.kdl. Copies
+// written by older installers, appended with a comment or inside a managed
+// block, are folded into a single node.
+func SetZellijDefaultShell(configPath string, shell string) error {
+	begin, end := BlockMarkers(SyntaxKDL, BlockDefaultShell)
+	return editKDLFile(configPath, func(content string) (string, error) {
+		var lines []string
+		for _, line := range strings.Split(content, "\n") {
+			switch strings.TrimSpace(line) {
+			case begin, end, "// Default shell (configured by Gentleman.Dots)":
+				continue
+			}
+			lines = append(lines, line)
+		}
+		return SetKDLString(strings.Join(lines, "\n"), "default_shell", shell)
+	})
+}
+
+// SetTermuxAutoStart makes Termux's bash exec shellPath on startup.
+// Termux has no chsh, so this stands in for changing the login shell.
+func SetTermuxAutoStart(bashrcPath string, shellPath string) error {
+	body := fmt.Sprintf(`if [ -x "%s" ] && [ -z "$GENTLEMANDOTS_SHELL_STARTED" ]; then
+    export GENTLEMANDOTS_SHELL_STARTED=1
+    exec %s
+fi`, shellPath, shellPath)
+	return patchFileBlock(bashrcPath, SyntaxSh, BlockShellAutoStart, body, func(lines []string, i int) int {
+		if strings.TrimSpace(lines[i]) == "# Gentleman.Dots shell auto-start" {
+			return linesUntil(lines, i, "fi")
+		}
+		return 0
+	})
+}
+
+// AppendLineOnce appends line to a file unless an identical line exists
+func AppendLineOnce(path string, line string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	content := string(data)
+	for _, existing := range strings.Split(content, "\n") {
+		if strings.TrimSpace(existing) == line {
+			return nil
+		}
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if err := EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content+line+"\n"), 0644)
+}
+
+// patchFileBlock adopts legacy lines (when isLegacy is set) into the named
+// block and upserts body, creating the file when it does not exist yet.
+func patchFileBlock(path string, syntax Syntax, name, body string, isLegacy func(lines []string, i int) int) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	content := string(data)
+	if isLegacy != nil {
+		content = strings.Join(adoptLegacy(strings.Split(content, "\n"), syntax, name, isLegacy), "\n")
+	}
+	updated, err := UpsertBlock(content, syntax, name, body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if updated == string(data) {
+		return nil
+	}
+	if err := EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
@@ -427,3 +427,218 @@ func TestPatchNushellForWM_FileNotFound(t *testing.T) {
 		t.Error("Expected error for non-existent file, got nil")
 	}
 }
+
+func TestPatchForWM_Idempotent(t *testing.T) {
+	patchers := []struct {
+		name   string
+		source string
+		patch  func(path, wm string) error
+	}{
+		{"zsh", "../../../GentlemanZsh/.zshrc", func(p, wm string) error { return PatchZshForWM(p, wm, false) }},
+		{"fish", "../../../GentlemanFish/fish/config.fish", func(p, wm string) error { return PatchFishForWM(p, wm, false) }},
+		{"nushell", "../../../GentlemanNushell/config.nu", PatchNushellForWM},
+		{"bash", "../../../GentlemanBash/.bashrc", func(p, wm string) error { return PatchBashForWM(p, wm, false) }},
+	}
+
+	for _, p := range patchers {
+		t.Run(p.name, func(t *testing.T) {
+			original, err := os.ReadFile(p.source)
+			if err != nil {
+				t.Skipf("shipped config not available: %v", err)
+			}
+			path := filepath.Join(t.TempDir(), "config")
+			if err := os.WriteFile(path, original, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			// Switching WMs back and forth must converge on the same file
+			var first string
+			for i, wm := range []string{"zellij", "none", "herdr", "zellij", "zellij"} {
+				if err := p.patch(path, wm); err != nil {
+					t.Fatalf("patch %s: %v", wm, err)
+				}
+				data, _ := os.ReadFile(path)
+				if i == 0 {
+					first = string(data)
+				}
+				if i >= 3 && string(data) != first {
+					t.Errorf("rerun with zellij produced different content:\n%s\nvs\n%s", data, first)
+				}
+			}
+			if n := strings.Count(first, ">>> gentleman.dots:"+BlockMultiplexer+" >>>"); n != 1 {
+				t.Errorf("expected exactly one multiplexer block, got %d", n)
+			}
+		})
+	}
+}
+
+// Linked configs are never patched, so the shipped ones must not need fzf
+func TestShippedConfigs_GuardFzf(t *testing.T) {
+	guards := map[string]string{
+		"../../../GentlemanZsh/.zshrc":            "if command -v fzf &> /dev/null; then\neval \"$(fzf --zsh)\"\nfi",
+		"../../../GentlemanBash/.bashrc":          "if command -v fzf &> /dev/null; then\neval \"$(fzf --bash)\"\nfi",
+		"../../../GentlemanFish/fish/config.fish": "if command -v fzf &> /dev/null\nfzf --fish | source\nend",
+	}
+	for source, guard := range guards {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			t.Skipf("shipped config not available: %v", err)
+		}
+		if !strings.Contains(string(data), guard) {
+			t.Errorf("%s: expected fzf to be guarded by command -v", source)
+		}
+	}
+}
+
+func TestPatchZshForWM_AdoptsPreviousPatch(t *testing.T) {
+	// A .zshrc patched by an older installer has no markers and a wrapped fzf line
+	legacy := "WM_VAR=\"$ZELLIJ\"\nWM_CMD=\"zellij\"\n\nfunction start_if_needed() {\n    exec $WM_CMD\n}\n\nif command -v fzf &> /dev/null; then\neval \"$(fzf --zsh)\"\nfi\nstart_if_needed\n"
+	path := filepath.Join(t.TempDir(), ".zshrc")
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := PatchZshForWM(path, "tmux", false); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, notWant := range []string{`WM_CMD="zellij"`, "if command -v fzf &> /dev/null; then\nif command -v fzf"} {
+		if strings.Contains(content, notWant) {
+			t.Errorf("expected %q to be replaced, got:\n%s", notWant, content)
+		}
+	}
+	if strings.Count(content, "function start_if_needed") != 1 || strings.Count(content, "\nstart_if_needed\n") != 1 {
+		t.Errorf("expected one definition and one call of start_if_needed, got:\n%s", content)
+	}
+}
+
+func TestSetTmuxDefaultShell(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tmux.conf")
+	os.WriteFile(path, []byte("# GENTLEMAN_DEFAULT_SHELL\nset -g mouse on\n"), 0644)
+
+	for _, shell := range []string{"/usr/bin/zsh", "/usr/bin/fish"} {
+		if err := SetTmuxDefaultShell(path, shell); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if strings.Contains(content, "GENTLEMAN_DEFAULT_SHELL") || strings.Contains(content, "zsh") {
+		t.Errorf("expected placeholder and previous shell replaced, got:\n%s", content)
+	}
+	if strings.Count(content, `set -g default-shell "/usr/bin/fish"`) != 1 {
+		t.Errorf("expected a single default-shell line, got:\n%s", content)
+	}
+}
+
+func TestSetZellijDefaultShell(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.kdl")
+	legacy := "theme \"kanagawa\"\n\n// Default shell (configured by Gentleman.Dots)\ndefault_shell \"zsh\"\n"
+	os.WriteFile(path, []byte(legacy), 0644)
+
+	for i := 0; i < 2; i++ {
+		if err := SetZellijDefaultShell(path, "nu"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if strings.Count(content, "default_shell") != 1 || !strings.Contains(content, `default_shell "nu"`) {
+		t.Errorf("expected a single default_shell \"nu\", got:\n%s", content)
+	}
+}
+
+func TestSetHomebrewShellenv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".bashrc")
+	shellenv := `eval "$(/home/linuxbrew/.linuxbrew/bin/brew shellenv)"`
+	os.WriteFile(path, []byte("alias ll='ls -l'\n\n"+shellenv+"\n\n"+shellenv+"\n"), 0644)
+
+	for i := 0; i < 3; i++ {
+		if err := SetHomebrewShellenv(path, shellenv); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	if n := strings.Count(string(data), "brew shellenv"); n != 1 {
+		t.Errorf("expected one brew shellenv line, got %d:\n%s", n, data)
+	}
+}
+
+func TestSetTermuxAutoStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".bashrc")
+	legacy := "\n# Gentleman.Dots shell auto-start\nif [ -x \"/bin/zsh\" ]; then\n    exec /bin/zsh\nfi\n"
+	os.WriteFile(path, []byte(legacy), 0644)
+
+	if err := SetTermuxAutoStart(path, "/bin/fish"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if strings.Contains(content, "exec /bin/zsh") || !strings.Contains(content, "exec /bin/fish") {
+		t.Errorf("expected auto-start to switch to fish, got:\n%s", content)
+	}
+}
+
+func TestAppendLineOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "etc", "shells")
+	for i := 0; i < 2; i++ {
+		if err := AppendLineOnce(path, "/usr/bin/fish"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "/usr/bin/fish\n" {
+		t.Errorf("got %q", data)
+	}
+}
+
+func TestConfiguredWM(t *testing.T) {
+	patchers := []struct {
+		name   string
+		source string
+		syntax Syntax
+		patch  func(path, wm string) error
+	}{
+		{"zsh", "../../../GentlemanZsh/.zshrc", SyntaxSh, func(p, wm string) error { return PatchZshForWM(p, wm, true) }},
+		{"fish", "../../../GentlemanFish/fish/config.fish", SyntaxFish, func(p, wm string) error { return PatchFishForWM(p, wm, true) }},
+		{"nushell", "../../../GentlemanNushell/config.nu", SyntaxNu, PatchNushellForWM},
+		{"bash", "../../../GentlemanBash/.bashrc", SyntaxSh, func(p, wm string) error { return PatchBashForWM(p, wm, true) }},
+	}
+
+	for _, p := range patchers {
+		t.Run(p.name, func(t *testing.T) {
+			original, err := os.ReadFile(p.source)
+			if err != nil {
+				t.Skipf("shipped config not available: %v", err)
+			}
+			for _, wm := range []string{"tmux", "zellij", "herdr", "none"} {
+				path := filepath.Join(t.TempDir(), "config")
+				os.WriteFile(path, original, 0644)
+				if err := p.patch(path, wm); err != nil {
+					t.Fatalf("patch %s: %v", wm, err)
+				}
+				data, _ := os.ReadFile(path)
+				if got := ConfiguredWM(string(data), p.syntax); got != wm {
+					t.Errorf("patched for %s, detected %q", wm, got)
+				}
+			}
+		})
+	}
+
+	t.Run("link overrides", func(t *testing.T) {
+		for _, wm := range []string{"tmux", "zellij", "herdr", "none"} {
+			if got := ConfiguredWM(ZshWMOverride(wm), SyntaxSh); got != wm {
+				t.Errorf("zsh override for %s: detected %q", wm, got)
+			}
+			if got := ConfiguredWM(FishWMOverride(wm), SyntaxFish); got != wm {
+				t.Errorf("fish override for %s: detected %q", wm, got)
+			}
+		}
+	})
+
+	t.Run("unrelated config", func(t *testing.T) {
+		if got := ConfiguredWM("alias ll='ls -l'\n", SyntaxSh); got != "" {
+			t.Errorf("expected unknown, got %q", got)
+		}
+	})
+}
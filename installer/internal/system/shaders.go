@@ -0,0 +1,188 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// BlockShaders holds the custom-shader lines of the Ghostty config
+const BlockShaders = "shaders"
+
+// ShaderKind is what a Ghostty shader draws
+type ShaderKind string
+
+const (
+	ShaderCursor     ShaderKind = "cursor"     // follows the cursor
+	ShaderBackground ShaderKind = "background" // animates behind the text
+	ShaderPost       ShaderKind = "post"       // filters the whole frame
+)
+
+// ShaderCost is a rough GPU cost, from the work done per pixel
+type ShaderCost string
+
+const (
+	CostLow    ShaderCost = "low"
+	CostMedium ShaderCost = "medium"
+	CostHigh   ShaderCost = "high"
+)
+
+// Shader describes one GLSL file shipped in GentlemanGhostty/shaders
+type Shader struct {
+	File        string // file name inside the shaders directory
+	Description string
+	Kind        ShaderKind
+	Cost        ShaderCost
+	Animated    bool // redraws every frame instead of only on changes
+}
+
+// Name is the file name without the .glsl extension
+func (s Shader) Name() string {
+	return strings.TrimSuffix(s.File, ".glsl")
+}
+
+// Tags summarizes the shader for one-line listings
+func (s Shader) Tags() string {
+	tags := string(s.Kind) + " · " + string(s.Cost) + " GPU"
+	if s.Animated {
+		tags += " · animated"
+	}
+	return tags
+}
+
+// GhosttyShaders is the catalog of shipped shaders. Work-in-progress and
+// debug files (WIP, test, debug_cursor_*) are left out.
+var GhosttyShaders = []Shader{
+	{File: "cursor_smear_gentleman.glsl", Description: "Gentleman default: the cursor smears toward its new position", Kind: ShaderCursor, Cost: CostLow},
+	{File: "cursor_smear.glsl", Description: "Cursor leaves a smear trail when it jumps", Kind: ShaderCursor, Cost: CostLow},
+	{File: "cursor_smear_fade.glsl", Description: "Smear trail that fades out as it shrinks", Kind: ShaderCursor, Cost: CostLow},
+	{File: "cursor_smear_glow.glsl", Description: "Smear trail with a soft glow around it", Kind: ShaderCursor, Cost: CostLow},
+	{File: "cursor_smear_gradient.glsl", Description: "Smear trail painted with a color gradient", Kind: ShaderCursor, Cost: CostLow},
+	{File: "cursor_smear_rainbow.glsl", Description: "Smear trail cycling through rainbow colors", Kind: ShaderCursor, Cost: CostLow},
+	{File: "cursor_blaze.glsl", Description: "Blazing trail behind the cursor", Kind: ShaderCursor, Cost: CostLow},
+	{File: "cursor_blaze_2.glsl", Description: "Alternative blaze trail with a longer tail", Kind: ShaderCursor, Cost: CostLow},
+	{File: "cursor_blaze_no_trail.glsl", Description: "Blaze flash on the cursor without a trail", Kind: ShaderCursor, Cost: CostLow},
+	{File: "cursor_blaze_tapered.glsl", Description: "Blaze trail that tapers to a point", Kind: ShaderCursor, Cost: CostLow},
+	{File: "cursor_border_1.glsl", Description: "Animated border around the cursor cell", Kind: ShaderCursor, Cost: CostLow, Animated: true},
+	{File: "cursor_frozen.glsl", Description: "Icy trail behind the cursor", Kind: ShaderCursor, Cost: CostLow},
+	{File: "blaze_sparks.glsl", Description: "Blaze trail that throws sparks", Kind: ShaderCursor, Cost: CostMedium},
+	{File: "sparks.glsl", Description: "Sparks burst from the cursor when it moves", Kind: ShaderCursor, Cost: CostMedium},
+	{File: "party_sparks.glsl", Description: "Colorful confetti sparks from the cursor", Kind: ShaderCursor, Cost: CostMedium},
+	{File: "manga_slash.glsl", Description: "Manga-style slash along the cursor jump", Kind: ShaderCursor, Cost: CostMedium},
+	{File: "shake.glsl", Description: "Screen shakes briefly when the cursor moves", Kind: ShaderCursor, Cost: CostLow},
+
+	{File: "animated-gradient-shader.glsl", Description: "Slowly shifting color gradient", Kind: ShaderBackground, Cost: CostLow, Animated: true},
+	{File: "gradient-background.glsl", Description: "Static color gradient", Kind: ShaderBackground, Cost: CostLow},
+	{File: "cineShader-Lava.glsl", Description: "Flowing lava lamp blobs", Kind: ShaderBackground, Cost: CostHigh, Animated: true},
+	{File: "cubes.glsl", Description: "Rotating 3D cubes", Kind: ShaderBackground, Cost: CostHigh, Animated: true},
+	{File: "fireworks.glsl", Description: "Fireworks exploding across the window", Kind: ShaderBackground, Cost: CostHigh, Animated: true},
+	{File: "fireworks-rockets.glsl", Description: "Fireworks with rising rockets", Kind: ShaderBackground, Cost: CostHigh, Animated: true},
+	{File: "galaxy.glsl", Description: "Drifting galaxy nebula", Kind: ShaderBackground, Cost: CostHigh, Animated: true},
+	{File: "gears-and-belts.glsl", Description: "Turning gears and belts", Kind: ShaderBackground, Cost: CostHigh, Animated: true},
+	{File: "inside-the-matrix.glsl", Description: "3D Matrix code tunnel", Kind: ShaderBackground, Cost: CostHigh, Animated: true},
+	{File: "matrix-hallway.glsl", Description: "Matrix digital rain hallway", Kind: ShaderBackground, Cost: CostMedium, Animated: true},
+	{File: "just-snow.glsl", Description: "Falling snow", Kind: ShaderBackground, Cost: CostMedium, Animated: true},
+	{File: "sin-interference.glsl", Description: "Rippling sine interference rings", Kind: ShaderBackground, Cost: CostLow, Animated: true},
+	{File: "smoke-and-ghost.glsl", Description: "Smoke rising from the text", Kind: ShaderBackground, Cost: CostHigh, Animated: true},
+	{File: "sparks-from-fire.glsl", Description: "Embers rising from a fire", Kind: ShaderBackground, Cost: CostHigh, Animated: true},
+	{File: "spotlight.glsl", Description: "Wandering spotlight over the text", Kind: ShaderBackground, Cost: CostLow, Animated: true},
+	{File: "starfield.glsl", Description: "Flying through a starfield", Kind: ShaderBackground, Cost: CostMedium, Animated: true},
+	{File: "starfield-colors.glsl", Description: "Starfield with colored stars", Kind: ShaderBackground, Cost: CostMedium, Animated: true},
+	{File: "underwater.glsl", Description: "Light rays through water", Kind: ShaderBackground, Cost: CostMedium, Animated: true},
+	{File: "water.glsl", Description: "Caustic water surface", Kind: ShaderBackground, Cost: CostMedium, Animated: true},
+	{File: "mnoise.glsl", Description: "Flowing simplex noise", Kind: ShaderBackground, Cost: CostMedium, Animated: true},
+
+	{File: "bloom.glsl", Description: "Bright text blooms into its surroundings", Kind: ShaderPost, Cost: CostMedium},
+	{File: "crt.glsl", Description: "CRT monitor with curvature and scanlines", Kind: ShaderPost, Cost: CostMedium},
+	{File: "bettercrt.glsl", Description: "Green-tinted CRT with curved screen", Kind: ShaderPost, Cost: CostLow},
+	{File: "in-game-crt.glsl", Description: "Detailed CRT with phosphor mask and noise", Kind: ShaderPost, Cost: CostHigh, Animated: true},
+	{File: "retro-terminal.glsl", Description: "Green phosphor retro terminal", Kind: ShaderPost, Cost: CostLow},
+	{File: "tft.glsl", Description: "Visible TFT pixel grid", Kind: ShaderPost, Cost: CostLow},
+	{File: "dither.glsl", Description: "Ordered dithering", Kind: ShaderPost, Cost: CostLow},
+	{File: "negative.glsl", Description: "Inverted colors", Kind: ShaderPost, Cost: CostLow},
+	{File: "drunkard.glsl", Description: "Wobbly, drunken distortion", Kind: ShaderPost, Cost: CostMedium, Animated: true},
+	{File: "glitchy.glsl", Description: "Periodic digital glitches", Kind: ShaderPost, Cost: CostLow, Animated: true},
+	{File: "glow-rgbsplit-twitchy.glsl", Description: "Glow with twitching RGB split", Kind: ShaderPost, Cost: CostMedium, Animated: true},
+}
+
+// LookupShader finds a catalog shader by name, with or without .glsl
+func LookupShader(name string) (Shader, bool) {
+	file := ShaderFile(name)
+	for _, s := range GhosttyShaders {
+		if s.File == file {
+			return s, true
+		}
+	}
+	return Shader{}, false
+}
+
+// ShaderFile normalizes a shader name to its file name
+func ShaderFile(name string) string {
+	name = filepath.Base(strings.TrimSpace(name))
+	if !strings.HasSuffix(name, ".glsl") {
+		name += ".glsl"
+	}
+	return name
+}
+
+var customShaderRe = regexp.MustCompile(`^\s*custom-shader\s*=\s*(.+?)\s*$`)
+
+// EnabledShaders returns the custom-shader files of a Ghostty config, in order
+func EnabledShaders(content string) []string {
+	var files []string
+	for _, line := range strings.Split(content, "\n") {
+		if m := customShaderRe.FindStringSubmatch(line); m != nil {
+			files = append(files, filepath.Base(strings.Trim(m[1], `"`)))
+		}
+	}
+	return files
+}
+
+// SetGhosttyShaders writes files, in order, as the custom-shader lines of a
+// Ghostty config. Hand-written custom-shader lines are adopted into the
+// managed block so the block is the only place shaders are enabled.
+func SetGhosttyShaders(path string, files []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	isShaderLine := func(lines []string, i int) int {
+		if customShaderRe.MatchString(lines[i]) {
+			return 1
+		}
+		return 0
+	}
+	lines := adoptLegacy(strings.Split(content, "\n"), SyntaxSh, BlockShaders, isShaderLine)
+
+	// Lines added by hand after the block was created
+	begin, end := BlockMarkers(SyntaxSh, BlockShaders)
+	var kept []string
+	inBlock := false
+	for i, line := range lines {
+		switch strings.TrimSpace(line) {
+		case begin:
+			inBlock = true
+		case end:
+			inBlock = false
+		}
+		if !inBlock && isShaderLine(lines, i) > 0 {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	var body []string
+	for _, file := range files {
+		body = append(body, "custom-shader = shaders/"+ShaderFile(file))
+	}
+	updated, err := UpsertBlock(strings.Join(kept, "\n"), SyntaxSh, BlockShaders, strings.Join(body, "\n"))
+	if err != nil {
+		return err
+	}
+	if updated == content {
+		return nil
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
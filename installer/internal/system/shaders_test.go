@@ -0,0 +1,71 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGhosttyShaders_CatalogMatchesShippedFiles(t *testing.T) {
+	dir := filepath.Join("..", "..", "..", "GentlemanGhostty", "shaders")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Skipf("shipped shaders not available: %v", err)
+	}
+	unlisted := map[string]bool{"WIP.glsl": true, "test.glsl": true, "debug_cursor_animated.glsl": true, "debug_cursor_static.glsl": true}
+	shipped := map[string]bool{}
+	for _, e := range entries {
+		shipped[e.Name()] = true
+		if _, ok := LookupShader(e.Name()); !ok && !unlisted[e.Name()] {
+			t.Errorf("%s is shipped but missing from the catalog", e.Name())
+		}
+	}
+	for _, s := range GhosttyShaders {
+		if !shipped[s.File] {
+			t.Errorf("%s is in the catalog but not shipped", s.File)
+		}
+	}
+}
+
+func TestSetGhosttyShaders(t *testing.T) {
+	shipped, err := os.ReadFile(filepath.Join("..", "..", "..", "GentlemanGhostty", "config"))
+	if err != nil {
+		t.Skipf("shipped ghostty config not available: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "config")
+	os.WriteFile(path, shipped, 0644)
+
+	if got := EnabledShaders(string(shipped)); len(got) != 1 || got[0] != "cursor_smear_gentleman.glsl" {
+		t.Fatalf("unexpected shipped shaders: %v", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := SetGhosttyShaders(path, []string{"starfield", "cursor_blaze.glsl"}); err != nil {
+			t.Fatalf("SetGhosttyShaders: %v", err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if got := strings.Join(EnabledShaders(content), ","); got != "starfield.glsl,cursor_blaze.glsl" {
+		t.Errorf("expected the shaders in order, got %s", got)
+	}
+	// The block takes the place of the shipped line, under the SHADER banner
+	if strings.Index(content, "SHADER") > strings.Index(content, "custom-shader") ||
+		strings.Index(content, "custom-shader") > strings.Index(content, "INPUT / KEYBINDINGS") {
+		t.Errorf("expected the block where the shipped line was:\n%s", content)
+	}
+
+	// A hand-written line next to the block is folded into it
+	os.WriteFile(path, []byte(content+"custom-shader = shaders/crt.glsl\n"), 0644)
+	if err := SetGhosttyShaders(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if got := EnabledShaders(string(data)); len(got) != 0 {
+		t.Errorf("expected every shader disabled, got %v", got)
+	}
+	if !HasBlock(string(data), SyntaxSh, BlockShaders) {
+		t.Error("expected the empty block to keep its position")
+	}
+}
@@ -0,0 +1,509 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// BlockTheme holds the tmux theme plugin and its options
+const BlockTheme = "theme"
+
+// Palette is a named color theme applied to every config
+type Palette struct {
+	ID          string
+	Name        string
+	Background  string
+	Foreground  string
+	Cursor      string
+	SelectionBg string
+	SelectionFg string
+	Normal      [8]string // black, red, green, yellow, blue, magenta, cyan, white
+	Bright      [8]string
+	Accent      string   // UI chrome: herdr accent and borders
+	Warm        string   // orange: starship peach, herdr yellow, zellij orange
+	Starship    string   // palette table in starship.toml
+	Zellij      string   // theme name in config.kdl
+	Nvim        string   // colorscheme name
+	NvimPlugin  string   // lazy.nvim spec added when the colorscheme is not shipped
+	Tmux        []string // theme plugin and its options
+}
+
+// kanagawaTmux is the tmux-kanagawa plugin setup for a variant
+func kanagawaTmux(variant string) []string {
+	return []string{
+		"set -g @plugin 'Nybkox/tmux-kanagawa'",
+		fmt.Sprintf("set -g @kanagawa-theme '%s'", variant),
+		`set -g @kanagawa-plugins "git cpu-usage ram-usage"`,
+		"set -g @kanagawa-ignore-window-colors true",
+	}
+}
+
+// catppuccinTmux is the catppuccin/tmux plugin setup for a flavor
+func catppuccinTmux(flavor string) []string {
+	return []string{
+		"set -g @plugin 'catppuccin/tmux'",
+		fmt.Sprintf("set -g @catppuccin_flavor '%s'", flavor),
+	}
+}
+
+// Palettes lists the selectable themes; the first is what the configs ship with
+var Palettes = []Palette{
+	{ID: "gentleman", Name: "Gentleman (Kanagawa Blur)",
+		Background: "#06080f", Foreground: "#f3f6f9", Cursor: "#e0c15a", SelectionBg: "#263356", SelectionFg: "#f3f6f9",
+		Normal: [8]string{"#06080f", "#cb7c94", "#b7cc85", "#ffe066", "#7fb4ca", "#ff8dd7", "#7aa89f", "#f3f6f9"},
+		Bright: [8]string{"#8a8fa3", "#de8fa8", "#d1e8a9", "#fff7b1", "#a3d4d5", "#ffaeea", "#7fb4ca", "#f3f6f9"},
+		Accent: "#6fa0af", Warm: "#deba87",
+		Starship: "gentleman", Zellij: "kanagawa_wave", Nvim: "gentleman-kanagawa-blur", Tmux: kanagawaTmux("dragon")},
+	{ID: "kanagawa-wave", Name: "Kanagawa Wave",
+		Background: "#1f1f28", Foreground: "#dcd7ba", Cursor: "#c8c093", SelectionBg: "#2d4f67", SelectionFg: "#c8c093",
+		Normal: [8]string{"#090618", "#c34043", "#76946a", "#c0a36e", "#7e9cd8", "#957fb8", "#6a9589", "#c8c093"},
+		Bright: [8]string{"#727169", "#e82424", "#98bb6c", "#e6c384", "#7fb4ca", "#938aa9", "#7aa89f", "#dcd7ba"},
+		Accent: "#7e9cd8", Warm: "#ffa066",
+		Starship: "kanagawa_wave", Zellij: "kanagawa_wave", Nvim: "kanagawa-wave", Tmux: kanagawaTmux("wave")},
+	{ID: "kanagawa-dragon", Name: "Kanagawa Dragon",
+		Background: "#181616", Foreground: "#c5c9c5", Cursor: "#c8c093", SelectionBg: "#2d4f67", SelectionFg: "#c8c093",
+		Normal: [8]string{"#0d0c0c", "#c4746e", "#8a9a7b", "#c4b28a", "#8ba4b0", "#a292a3", "#8ea4a2", "#c8c093"},
+		Bright: [8]string{"#a6a69c", "#e46876", "#87a987", "#e6c384", "#7fb4ca", "#938aa9", "#7aa89f", "#c5c9c5"},
+		Accent: "#8ba4b0", Warm: "#b6927b",
+		Starship: "kanagawa_dragon", Zellij: "kanagawa_dragon", Nvim: "kanagawa-dragon", Tmux: kanagawaTmux("dragon")},
+	{ID: "catppuccin-mocha", Name: "Catppuccin Mocha",
+		Background: "#1e1e2e", Foreground: "#cdd6f4", Cursor: "#f5e0dc", SelectionBg: "#585b70", SelectionFg: "#cdd6f4",
+		Normal: [8]string{"#45475a", "#f38ba8", "#a6e3a1", "#f9e2af", "#89b4fa", "#f5c2e7", "#94e2d5", "#bac2de"},
+		Bright: [8]string{"#585b70", "#f38ba8", "#a6e3a1", "#f9e2af", "#89b4fa", "#f5c2e7", "#94e2d5", "#a6adc8"},
+		Accent: "#89b4fa", Warm: "#fab387",
+		Starship: "catppuccin_mocha", Zellij: "catppuccin_mocha", Nvim: "catppuccin-mocha", Tmux: catppuccinTmux("mocha")},
+	{ID: "catppuccin-macchiato", Name: "Catppuccin Macchiato",
+		Background: "#24273a", Foreground: "#cad3f5", Cursor: "#f4dbd6", SelectionBg: "#5b6078", SelectionFg: "#cad3f5",
+		Normal: [8]string{"#494d64", "#ed8796", "#a6da95", "#eed49f", "#8aadf4", "#f5bde6", "#8bd5ca", "#b8c0e0"},
+		Bright: [8]string{"#5b6078", "#ed8796", "#a6da95", "#eed49f", "#8aadf4", "#f5bde6", "#8bd5ca", "#a5adcb"},
+		Accent: "#8aadf4", Warm: "#f5a97f",
+		Starship: "catppuccin_macchiato", Zellij: "catppuccin_macchiato", Nvim: "catppuccin-macchiato", Tmux: catppuccinTmux("macchiato")},
+	{ID: "catppuccin-frappe", Name: "Catppuccin Frappé",
+		Background: "#303446", Foreground: "#c6d0f5", Cursor: "#f2d5cf", SelectionBg: "#626880", SelectionFg: "#c6d0f5",
+		Normal: [8]string{"#51576d", "#e78284", "#a6d189", "#e5c890", "#8caaee", "#f4b8e4", "#81c8be", "#b5bfe2"},
+		Bright: [8]string{"#626880", "#e78284", "#a6d189", "#e5c890", "#8caaee", "#f4b8e4", "#81c8be", "#a5adce"},
+		Accent: "#8caaee", Warm: "#ef9f76",
+		Starship: "catppuccin_frappe", Zellij: "catppuccin_frappe", Nvim: "catppuccin-frappe", Tmux: catppuccinTmux("frappe")},
+	{ID: "catppuccin-latte", Name: "Catppuccin Latte",
+		Background: "#eff1f5", Foreground: "#4c4f69", Cursor: "#dc8a78", SelectionBg: "#acb0be", SelectionFg: "#4c4f69",
+		Normal: [8]string{"#5c5f77", "#d20f39", "#40a02b", "#df8e1d", "#1e66f5", "#ea76cb", "#179299", "#acb0be"},
+		Bright: [8]string{"#6c6f85", "#d20f39", "#40a02b", "#df8e1d", "#1e66f5", "#ea76cb", "#179299", "#bcc0cc"},
+		Accent: "#1e66f5", Warm: "#fe640b",
+		Starship: "catppuccin_latte", Zellij: "catppuccin_latte", Nvim: "catppuccin-latte", Tmux: catppuccinTmux("latte")},
+	{ID: "tokyonight", Name: "Tokyo Night",
+		Background: "#1a1b26", Foreground: "#c0caf5", Cursor: "#c0caf5", SelectionBg: "#283457", SelectionFg: "#c0caf5",
+		Normal: [8]string{"#15161e", "#f7768e", "#9ece6a", "#e0af68", "#7aa2f7", "#bb9af7", "#7dcfff", "#a9b1d6"},
+		Bright: [8]string{"#414868", "#f7768e", "#9ece6a", "#e0af68", "#7aa2f7", "#bb9af7", "#7dcfff", "#c0caf5"},
+		Accent: "#7aa2f7", Warm: "#ff9e64",
+		Starship: "tokyonight", Zellij: "tokyonight", Nvim: "tokyonight-night",
+		Tmux: []string{"set -g @plugin 'janoamaral/tokyo-night-tmux'", "set -g @tokyo-night-tmux_theme night"}},
+	{ID: "rose-pine", Name: "Rosé Pine",
+		Background: "#191724", Foreground: "#e0def4", Cursor: "#524f67", SelectionBg: "#403d52", SelectionFg: "#e0def4",
+		Normal: [8]string{"#26233a", "#eb6f92", "#31748f", "#f6c177", "#9ccfd8", "#c4a7e7", "#ebbcba", "#e0def4"},
+		Bright: [8]string{"#6e6a86", "#eb6f92", "#31748f", "#f6c177", "#9ccfd8", "#c4a7e7", "#ebbcba", "#e0def4"},
+		Accent: "#c4a7e7", Warm: "#ebbcba",
+		Starship: "rose_pine", Zellij: "rose_pine", Nvim: "rose-pine",
+		NvimPlugin: `{ "rose-pine/neovim", name = "rose-pine", priority = 1000 },`,
+		Tmux:       []string{"set -g @plugin 'rose-pine/tmux'", "set -g @rose_pine_variant 'main'"}},
+}
+
+// LookupPalette finds a palette by ID
+func LookupPalette(id string) (Palette, bool) {
+	for _, p := range Palettes {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Palette{}, false
+}
+
+// ThemeTargets lists the configs ApplyTheme knows how to recolor
+var ThemeTargets = []string{"alacritty", "wezterm", "kitty", "ghostty", "tmux", "zellij", "herdr", "starship", "nvim"}
+
+// ApplyTheme rewrites the color settings of one config to the palette,
+// leaving comments and every other setting untouched
+func ApplyTheme(target, path string, p Palette) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	var updated string
+	switch target {
+	case "alacritty":
+		updated = alacrittyTheme(content, p)
+	case "wezterm":
+		updated = weztermTheme(content, p)
+	case "kitty":
+		updated = kittyTheme(content, p)
+	case "ghostty":
+		updated = ghosttyTheme(content, p)
+	case "tmux":
+		updated, err = tmuxTheme(content, p)
+	case "zellij":
+		updated, err = zellijTheme(content, p)
+	case "herdr":
+		updated = herdrTheme(content, p)
+	case "starship":
+		updated = starshipTheme(content, p)
+	case "nvim":
+		updated = nvimTheme(content, p)
+	default:
+		return fmt.Errorf("unsupported theme target: %s", target)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if updated == content {
+		return nil
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// ansiNames are the color names terminals use for the 8 ANSI slots
+var ansiNames = [8]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// setTOMLValue sets key in table ("" for the top level) to a raw TOML value,
+// keeping the line's alignment and trailing comment. A missing key is added
+// right below the table header; a missing table is appended.
+func setTOMLValue(content, table, key, value string) string {
+	lines := strings.Split(content, "\n")
+	keyRe := regexp.MustCompile(`^(\s*` + regexp.QuoteMeta(key) + `\s*=\s*)("[^"]*"|'[^']*'|[^\s#]+)(.*)$`)
+	current := ""
+	headerLine := -1
+	for i, line := range lines {
+		if m := tomlTableRe.FindStringSubmatch(line); m != nil {
+			current = strings.TrimSpace(m[1])
+			if current == table {
+				headerLine = i
+			}
+			continue
+		}
+		if current != table {
+			continue
+		}
+		if m := keyRe.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1] + value + m[3]
+			return strings.Join(lines, "\n")
+		}
+	}
+	entry := key + " = " + value
+	if table == "" {
+		return entry + "\n" + content
+	}
+	if headerLine >= 0 {
+		lines = append(lines[:headerLine+1], append([]string{entry}, lines[headerLine+1:]...)...)
+		return strings.Join(lines, "\n")
+	}
+	return strings.TrimRight(content, "\n") + "\n\n[" + table + "]\n" + entry + "\n"
+}
+
+// hasTOMLTable reports whether content declares [table]
+func hasTOMLTable(content, table string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if m := tomlTableRe.FindStringSubmatch(line); m != nil && strings.TrimSpace(m[1]) == table {
+			return true
+		}
+	}
+	return false
+}
+
+func alacrittyTheme(content string, p Palette) string {
+	q := func(color string) string { return fmt.Sprintf("%q", color) }
+	content = setTOMLValue(content, "colors.primary", "background", q(p.Background))
+	content = setTOMLValue(content, "colors.primary", "foreground", q(p.Foreground))
+	content = setTOMLValue(content, "colors.cursor", "cursor", q(p.Cursor))
+	content = setTOMLValue(content, "colors.cursor", "text", q(p.Background))
+	content = setTOMLValue(content, "colors.selection", "background", q(p.SelectionBg))
+	content = setTOMLValue(content, "colors.selection", "text", q(p.SelectionFg))
+	for i, name := range ansiNames {
+		content = setTOMLValue(content, "colors.normal", name, q(p.Normal[i]))
+		content = setTOMLValue(content, "colors.bright", name, q(p.Bright[i]))
+	}
+	return content
+}
+
+var (
+	luaColorKeyRe = regexp.MustCompile(`^(\s*)([a-z_]+)(\s*=\s*)"#[0-9a-fA-F]{6}"(.*)$`)
+	luaListRe     = regexp.MustCompile(`^\s*(ansi|brights)\s*=\s*\{\s*$`)
+	luaListItemRe = regexp.MustCompile(`^(\s*)"#[0-9a-fA-F]{6}"(.*)$`)
+)
+
+// weztermTheme rewrites the colors inside `config.colors = { ... }`, or adds
+// the table before `return config`
+func weztermTheme(content string, p Palette) string {
+	base := map[string]string{
+		"foreground": p.Foreground, "background": p.Background,
+		"cursor_bg": p.Cursor, "cursor_fg": p.Background, "cursor_border": p.Cursor,
+		"selection_fg": p.SelectionFg, "selection_bg": p.SelectionBg,
+	}
+	lines := strings.Split(content, "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "config.colors = {") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return insertBeforeReturn(content, weztermColorsTable(p))
+	}
+
+	list, slot := "", 0
+	for i := start + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if list == "" && trimmed == "}" {
+			break
+		}
+		if m := luaListRe.FindStringSubmatch(lines[i]); m != nil {
+			list, slot = m[1], 0
+			continue
+		}
+		if list != "" {
+			if strings.HasPrefix(trimmed, "}") {
+				list = ""
+				continue
+			}
+			if m := luaListItemRe.FindStringSubmatch(lines[i]); m != nil && slot < 8 {
+				colors := p.Normal
+				if list == "brights" {
+					colors = p.Bright
+				}
+				lines[i] = fmt.Sprintf("%s%q%s", m[1], colors[slot], m[2])
+				slot++
+			}
+			continue
+		}
+		if m := luaColorKeyRe.FindStringSubmatch(lines[i]); m != nil {
+			if color, ok := base[m[2]]; ok {
+				lines[i] = fmt.Sprintf("%s%s%s%q%s", m[1], m[2], m[3], color, m[4])
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// weztermColorsTable renders a complete config.colors table
+func weztermColorsTable(p Palette) string {
+	list := func(colors [8]string) string {
+		var b strings.Builder
+		for i, c := range colors {
+			fmt.Fprintf(&b, "\t\t%q, -- %s\n", c, ansiNames[i])
+		}
+		return b.String()
+	}
+	return fmt.Sprintf(`config.colors = {
+	foreground = %q,
+	background = %q,
+	cursor_bg = %q,
+	cursor_fg = %q,
+	cursor_border = %q,
+	selection_fg = %q,
+	selection_bg = %q,
+	ansi = {
+%s	},
+	brights = {
+%s	},
+}`, p.Foreground, p.Background, p.Cursor, p.Background, p.Cursor, p.SelectionFg, p.SelectionBg, list(p.Normal), list(p.Bright))
+}
+
+// insertBeforeReturn places block before the final `return config`
+func insertBeforeReturn(content, block string) string {
+	lines := strings.Split(content, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) == "return config" {
+			lines = append(lines[:i], append(strings.Split(block+"\n", "\n"), lines[i:]...)...)
+			return strings.Join(lines, "\n")
+		}
+	}
+	return strings.TrimRight(content, "\n") + "\n" + block + "\n"
+}
+
+// setSpacedValue sets a `key value` line (kitty), keeping its alignment
+func setSpacedValue(content, key, value string) string {
+	re := regexp.MustCompile(`^(\s*` + regexp.QuoteMeta(key) + `\s+)\S+(.*)$`)
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if m := re.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1] + value + m[2]
+			return strings.Join(lines, "\n")
+		}
+	}
+	return strings.TrimRight(content, "\n") + "\n" + key + " " + value + "\n"
+}
+
+func kittyTheme(content string, p Palette) string {
+	values := [][2]string{
+		{"background", p.Background}, {"foreground", p.Foreground}, {"cursor", p.Cursor},
+		{"selection_background", p.SelectionBg}, {"selection_foreground", p.SelectionFg},
+		{"url_color", p.Normal[4]},
+		{"active_tab_background", p.SelectionBg}, {"active_tab_foreground", p.Foreground},
+		{"inactive_tab_background", p.Background}, {"inactive_tab_foreground", p.Bright[0]},
+	}
+	for i := 0; i < 8; i++ {
+		values = append(values, [2]string{fmt.Sprintf("color%d", i), p.Normal[i]})
+	}
+	for i := 0; i < 8; i++ {
+		values = append(values, [2]string{fmt.Sprintf("color%d", i+8), p.Bright[i]})
+	}
+	for _, kv := range values {
+		content = setSpacedValue(content, kv[0], kv[1])
+	}
+	return content
+}
+
+func ghosttyTheme(content string, p Palette) string {
+	bare := func(color string) string { return strings.TrimPrefix(color, "#") }
+	for _, kv := range [][2]string{
+		{"background", bare(p.Background)}, {"foreground", bare(p.Foreground)}, {"cursor-color", bare(p.Cursor)},
+		{"selection-background", bare(p.SelectionBg)}, {"selection-foreground", bare(p.SelectionFg)},
+	} {
+		re := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(kv[0]) + `\s*=`)
+		content = replaceOrAppend(content, re, kv[0]+" = "+kv[1])
+	}
+	for i := 0; i < 16; i++ {
+		color := p.Normal[i%8]
+		if i >= 8 {
+			color = p.Bright[i-8]
+		}
+		re := regexp.MustCompile(fmt.Sprintf(`^\s*palette\s*=\s*%d=`, i))
+		content = replaceOrAppend(content, re, fmt.Sprintf("palette = %d=%s", i, color))
+	}
+	return content
+}
+
+// tmuxThemeOptions are option prefixes of the theme plugins we manage
+var tmuxThemeOptions = []string{"@kanagawa-", "@catppuccin_", "@tokyo-night-tmux_", "@rose_pine_"}
+
+// tmuxThemePlugins are the theme plugins we manage
+var tmuxThemePlugins = []string{"Nybkox/tmux-kanagawa", "catppuccin/tmux", "janoamaral/tokyo-night-tmux", "rose-pine/tmux"}
+
+// isTmuxThemeLine reports whether a tmux.conf line configures a managed theme
+func isTmuxThemeLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "# Tema Kanagawa" {
+		return true
+	}
+	if !strings.HasPrefix(trimmed, "set -g @") {
+		return false
+	}
+	for _, plugin := range tmuxThemePlugins {
+		if strings.Contains(trimmed, "@plugin '"+plugin+"'") {
+			return true
+		}
+	}
+	for _, prefix := range tmuxThemeOptions {
+		if strings.HasPrefix(trimmed, "set -g "+prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// tmuxTheme swaps the theme plugin lines for a managed block. On a config
+// without theme lines the block goes before TPM's run line, so TPM loads it.
+func tmuxTheme(content string, p Palette) (string, error) {
+	lines := adoptLegacy(strings.Split(content, "\n"), SyntaxTmux, BlockTheme, func(lines []string, i int) int {
+		if isTmuxThemeLine(lines[i]) {
+			return 1
+		}
+		return 0
+	})
+	if !HasBlock(strings.Join(lines, "\n"), SyntaxTmux, BlockTheme) {
+		for i, line := range lines {
+			if strings.HasPrefix(strings.TrimSpace(line), "run ") && strings.Contains(line, "tpm") {
+				begin, end := BlockMarkers(SyntaxTmux, BlockTheme)
+				lines = append(lines[:i], append([]string{begin, end, ""}, lines[i:]...)...)
+				break
+			}
+		}
+	}
+	return UpsertBlock(strings.Join(lines, "\n"), SyntaxTmux, BlockTheme, strings.Join(p.Tmux, "\n"))
+}
+
+// zellijTheme selects the palette's theme, defining it under `themes` when missing
+func zellijTheme(content string, p Palette) (string, error) {
+	nodes, err := ParseKDL(content)
+	if err != nil {
+		return "", err
+	}
+	if FindKDLNode(nodes, "themes", KDLQuote(p.Zellij)) == nil {
+		content, err = SetKDLNode(content, []string{"themes", KDLQuote(p.Zellij)}, zellijThemeBlock(p))
+		if err != nil {
+			return "", err
+		}
+	}
+	return SetKDLString(content, "theme", p.Zellij)
+}
+
+// zellijThemeBlock renders a theme definition in zellij's legacy color format
+func zellijThemeBlock(p Palette) string {
+	block := []string{p.Zellij + " {"}
+	for _, kv := range [][2]string{
+		{"fg", p.Foreground}, {"bg", p.Background}, {"red", p.Normal[1]}, {"green", p.Normal[2]},
+		{"yellow", p.Normal[3]}, {"blue", p.Normal[4]}, {"magenta", p.Normal[5]}, {"cyan", p.Normal[6]},
+		{"orange", p.Warm}, {"black", p.Normal[0]}, {"white", p.Normal[7]},
+	} {
+		block = append(block, fmt.Sprintf("    %s %q", kv[0], kv[1]))
+	}
+	return strings.Join(append(block, "}"), "\n")
+}
+
+func herdrTheme(content string, p Palette) string {
+	q := func(color string) string { return fmt.Sprintf("%q", strings.ToUpper(color)) }
+	for _, kv := range [][2]string{
+		{"panel_bg", strings.ToLower(q(p.Background))}, {"accent", q(p.Accent)}, {"green", q(p.Normal[2])},
+		{"blue", q(p.Accent)}, {"red", q(p.Normal[1])}, {"yellow", q(p.Warm)},
+	} {
+		content = setTOMLValue(content, "theme.custom", kv[0], kv[1])
+	}
+	return setTOMLValue(content, "ui", "accent", q(p.Accent))
+}
+
+// starshipTheme selects the palette, adding its [palettes.*] table when missing
+func starshipTheme(content string, p Palette) string {
+	if table := "palettes." + p.Starship; !hasTOMLTable(content, table) {
+		content = strings.TrimRight(content, "\n") + "\n\n[" + table + "]\n" + starshipPalette(p) + "\n"
+	}
+	return setTOMLValue(content, "", "palette", fmt.Sprintf("%q", p.Starship))
+}
+
+// starshipPalette maps the palette onto the color names our starship.toml uses
+func starshipPalette(p Palette) string {
+	var b strings.Builder
+	for _, kv := range [][2]string{
+		{"text", p.Foreground}, {"red", p.Normal[1]}, {"green", p.Normal[2]}, {"yellow", p.Normal[3]},
+		{"blue", p.Normal[4]}, {"mauve", p.Bright[5]}, {"pink", p.Normal[5]}, {"teal", p.Normal[6]},
+		{"peach", p.Warm}, {"subtext0", p.Bright[0]}, {"subtext1", p.Normal[7]}, {"overlay0", p.SelectionBg},
+		{"overlay1", p.SelectionBg}, {"overlay2", p.SelectionBg}, {"surface0", p.Normal[0]}, {"surface1", p.SelectionBg},
+		{"surface2", p.SelectionBg}, {"rosewater", p.Cursor}, {"flamingo", p.Bright[5]}, {"maroon", p.Bright[1]},
+		{"lavender", p.Bright[4]}, {"base", "none"}, {"mantle", p.Background}, {"crust", p.Background},
+	} {
+		fmt.Fprintf(&b, "%s = %q\n", kv[0], kv[1])
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+var nvimColorschemeRe = regexp.MustCompile(`^(\s*colorscheme\s*=\s*)"[^"]*"(.*)$`)
+
+// nvimTheme sets LazyVim's colorscheme, adding the plugin spec when it is not shipped
+func nvimTheme(content string, p Palette) string {
+	lines := strings.Split(content, "\n")
+	lazyVimSpec := -1
+	for i, line := range lines {
+		if m := nvimColorschemeRe.FindStringSubmatch(line); m != nil {
+			lines[i] = fmt.Sprintf("%s%q%s", m[1], p.Nvim, m[2])
+		}
+		if lazyVimSpec < 0 && strings.Contains(line, `"LazyVim/LazyVim"`) && i > 0 {
+			lazyVimSpec = i - 1 // the `{` opening the spec
+		}
+	}
+	repo := strings.SplitN(strings.TrimPrefix(p.NvimPlugin, `{ "`), `"`, 2)[0]
+	if p.NvimPlugin != "" && !strings.Contains(content, `"`+repo+`"`) && lazyVimSpec >= 0 {
+		indent := lines[lazyVimSpec][:len(lines[lazyVimSpec])-len(strings.TrimLeft(lines[lazyVimSpec], " \t"))]
+		lines = append(lines[:lazyVimSpec], append([]string{indent + p.NvimPlugin}, lines[lazyVimSpec:]...)...)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,152 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// shippedThemeConfigs maps theme targets to the configs in the repository
+var shippedThemeConfigs = map[string]string{
+	"alacritty": "alacritty.toml",
+	"wezterm":   ".wezterm.lua",
+	"kitty":     "GentlemanKitty/kitty.conf",
+	"ghostty":   "GentlemanGhostty/config",
+	"tmux":      "GentlemanTmux/tmux.conf",
+	"zellij":    "GentlemanZellij/zellij/config.kdl",
+	"herdr":     "herdr/config.toml",
+	"starship":  "starship.toml",
+	"nvim":      "GentlemanNvim/nvim/lua/plugins/colorscheme.lua",
+}
+
+// copyShippedConfig copies a repository config into a temp dir
+func copyShippedConfig(t *testing.T, target string) (string, string) {
+	t.Helper()
+	original, err := os.ReadFile(filepath.Join("..", "..", "..", shippedThemeConfigs[target]))
+	if err != nil {
+		t.Fatalf("reading shipped %s config: %v", target, err)
+	}
+	path := filepath.Join(t.TempDir(), filepath.Base(shippedThemeConfigs[target]))
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, string(original)
+}
+
+func TestApplyTheme_ShippedConfigsUseGentleman(t *testing.T) {
+	gentleman, _ := LookupPalette("gentleman")
+	for _, target := range ThemeTargets {
+		if target == "tmux" {
+			continue // the plugin lines move into a managed block
+		}
+		path, original := copyShippedConfig(t, target)
+		if err := ApplyTheme(target, path, gentleman); err != nil {
+			t.Fatalf("%s: %v", target, err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != original {
+			t.Errorf("%s: applying the shipped theme changed the config", target)
+		}
+	}
+}
+
+func TestApplyTheme_RoundTrip(t *testing.T) {
+	gentleman, _ := LookupPalette("gentleman")
+	mocha, _ := LookupPalette("catppuccin-mocha")
+	for _, target := range []string{"alacritty", "wezterm", "kitty", "ghostty", "herdr"} {
+		path, original := copyShippedConfig(t, target)
+		if err := ApplyTheme(target, path, mocha); err != nil {
+			t.Fatalf("%s: %v", target, err)
+		}
+		themed, _ := os.ReadFile(path)
+		if strings.Contains(strings.ToLower(string(themed)), "06080f") {
+			t.Errorf("%s: Gentleman background left behind:\n%s", target, themed)
+		}
+		if !strings.Contains(strings.ToLower(string(themed)), "1e1e2e") && target != "herdr" {
+			t.Errorf("%s: Mocha background missing", target)
+		}
+		if err := ApplyTheme(target, path, gentleman); err != nil {
+			t.Fatalf("%s: %v", target, err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != original {
+			t.Errorf("%s: switching back did not restore the shipped config", target)
+		}
+	}
+}
+
+func TestApplyTheme_Tmux(t *testing.T) {
+	path, _ := copyShippedConfig(t, "tmux")
+	tokyo, _ := LookupPalette("tokyonight")
+	for i := 0; i < 2; i++ {
+		if err := ApplyTheme("tmux", path, tokyo); err != nil {
+			t.Fatalf("ApplyTheme: %v", err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if strings.Contains(content, "tmux-kanagawa") || strings.Contains(content, "@kanagawa-") {
+		t.Errorf("expected the kanagawa plugin to be replaced:\n%s", content)
+	}
+	if strings.Count(content, "janoamaral/tokyo-night-tmux") != 1 {
+		t.Errorf("expected the tokyo night plugin once:\n%s", content)
+	}
+	if strings.Index(content, "tokyo-night-tmux") > strings.Index(content, "run '~/.tmux/plugins/tpm/tpm'") {
+		t.Error("expected the theme plugin before TPM runs")
+	}
+
+	// A config without any theme gets the block before TPM
+	bare := filepath.Join(t.TempDir(), "tmux.conf")
+	os.WriteFile(bare, []byte("set -g mouse on\nrun '~/.tmux/plugins/tpm/tpm'\n"), 0644)
+	if err := ApplyTheme("tmux", bare, tokyo); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(bare)
+	if strings.Index(string(data), "tokyo-night-tmux") > strings.Index(string(data), "run '") {
+		t.Errorf("expected the theme before TPM:\n%s", data)
+	}
+}
+
+func TestApplyTheme_DefinesMissingThemes(t *testing.T) {
+	rose, _ := LookupPalette("rose-pine")
+
+	path, _ := copyShippedConfig(t, "zellij")
+	if err := ApplyTheme("zellij", path, rose); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "\ntheme \"rose_pine\"") || strings.Count(string(data), "rose_pine {") != 1 {
+		t.Errorf("expected rose_pine to be defined and selected")
+	}
+
+	path, _ = copyShippedConfig(t, "starship")
+	if err := ApplyTheme("starship", path, rose); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), `palette = "rose_pine"`) || !strings.Contains(string(data), "[palettes.rose_pine]") {
+		t.Errorf("expected the rose_pine palette to be added and selected")
+	}
+
+	path, _ = copyShippedConfig(t, "nvim")
+	for i := 0; i < 2; i++ {
+		if err := ApplyTheme("nvim", path, rose); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), `colorscheme = "rose-pine"`) || strings.Count(string(data), `"rose-pine/neovim"`) != 1 {
+		t.Errorf("expected the rose-pine spec once and the colorscheme set:\n%s", data)
+	}
+}
+
+func TestSetTOMLValue(t *testing.T) {
+	content := "palette = \"a\" # keep\n\n[ui]\naccent = \"#000000\"\n"
+	got := setTOMLValue(content, "", "palette", `"b"`)
+	got = setTOMLValue(got, "ui", "accent", `"#ffffff"`)
+	got = setTOMLValue(got, "ui", "border", `"#111111"`)
+	got = setTOMLValue(got, "theme.custom", "red", `"#222222"`)
+	want := "palette = \"b\" # keep\n\n[ui]\nborder = \"#111111\"\naccent = \"#ffffff\"\n\n[theme.custom]\nred = \"#222222\"\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,211 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TmuxPlugin is one `set -g @plugin` entry of a tmux.conf
+type TmuxPlugin struct {
+	Name   string // directory under ~/.tmux/plugins, as TPM names it
+	Repo   string // "owner/repo" on GitHub, or a full git URL
+	Branch string // from TPM's "owner/repo#branch" syntax; "" is the default branch
+}
+
+// URL is the git remote of the plugin
+func (p TmuxPlugin) URL() string {
+	if strings.Contains(p.Repo, "://") || strings.HasPrefix(p.Repo, "git@") || filepath.IsAbs(p.Repo) {
+		return p.Repo
+	}
+	return "https://github.com/" + p.Repo
+}
+
+var tmuxPluginRe = regexp.MustCompile(`^\s*set(?:-option)?\s+(?:-\w+\s+)*@plugin\s+['"]([^'"]+)['"]`)
+
+// ParseTmuxPlugins returns the plugins a tmux.conf declares, in order
+func ParseTmuxPlugins(content string) []TmuxPlugin {
+	var plugins []TmuxPlugin
+	seen := map[string]bool{}
+	for _, line := range strings.Split(content, "\n") {
+		m := tmuxPluginRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		repo, branch, _ := strings.Cut(m[1], "#")
+		name := strings.TrimSuffix(path.Base(strings.TrimSuffix(repo, "/")), ".git")
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		plugins = append(plugins, TmuxPlugin{Name: name, Repo: repo, Branch: branch})
+	}
+	return plugins
+}
+
+// TmuxLockEntry pins a plugin, like an entry of lazy.nvim's lazy-lock.json
+type TmuxLockEntry struct {
+	Branch string `json:"branch"`
+	Commit string `json:"commit"`
+}
+
+// TmuxLock maps plugin names to their pinned commits
+type TmuxLock map[string]TmuxLockEntry
+
+// ReadTmuxLock reads a lock file; a missing file is an empty lock
+func ReadTmuxLock(path string) (TmuxLock, error) {
+	lock := TmuxLock{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return lock, nil
+}
+
+// WriteTmuxLock writes the lock one plugin per line, sorted, as lazy.nvim does
+func WriteTmuxLock(path string, lock TmuxLock) error {
+	names := make([]string, 0, len(lock))
+	for name := range lock {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for i, name := range names {
+		key, _ := json.Marshal(name)
+		entry, _ := json.Marshal(lock[name])
+		entryText := strings.NewReplacer(`{"`, `{ "`, `":"`, `": "`, `","`, `", "`, `"}`, `" }`).Replace(string(entry))
+		b.WriteString("  " + string(key) + ": " + entryText)
+		if i < len(names)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	if err := EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// InstallTmuxPlugin puts p into dir at pin, or at the head of its branch when
+// pin is empty. A checkout already at pin is kept. Otherwise the plugin is
+// cloned next to dir and swapped in, so a failure leaves the old copy alone.
+// It returns the branch and commit that were checked out.
+func InstallTmuxPlugin(dir string, p TmuxPlugin, pin string, onLine func(string)) (TmuxLockEntry, error) {
+	if pin != "" && gitHead(dir) == pin {
+		if err := VerifyTmuxPlugin(dir, p); err == nil {
+			return TmuxLockEntry{Branch: gitBranch(dir, p), Commit: pin}, nil
+		}
+	}
+
+	staging := dir + ".new"
+	os.RemoveAll(staging)
+	if err := GitClone(p.URL(), staging, onLine); err != nil {
+		os.RemoveAll(staging)
+		return TmuxLockEntry{}, fmt.Errorf("cloning %s: %w", p.URL(), err)
+	}
+	entry := TmuxLockEntry{Branch: gitBranch(staging, p)}
+	ref := pin
+	if ref == "" {
+		ref = p.Branch
+	}
+	if ref != "" {
+		if result := Run(fmt.Sprintf("git -C %q -c advice.detachedHead=false checkout --quiet %q", staging, ref), nil); result.Error != nil {
+			os.RemoveAll(staging)
+			return TmuxLockEntry{}, fmt.Errorf("checking out %s: %w", ref, result.Error)
+		}
+	}
+	if err := VerifyTmuxPlugin(staging, p); err != nil {
+		os.RemoveAll(staging)
+		return TmuxLockEntry{}, err
+	}
+	entry.Commit = gitHead(staging)
+
+	if err := os.RemoveAll(dir); err != nil {
+		return TmuxLockEntry{}, err
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		return TmuxLockEntry{}, err
+	}
+	return entry, nil
+}
+
+// ResolveTmuxPlugin reads the commit at the head of p's branch from the
+// remote, without cloning it
+func ResolveTmuxPlugin(p TmuxPlugin) (TmuxLockEntry, error) {
+	ref := "HEAD"
+	if p.Branch != "" {
+		ref = "refs/heads/" + p.Branch
+	}
+	result := Run(fmt.Sprintf("git ls-remote --symref %q %q", p.URL(), ref), nil)
+	if result.Error != nil {
+		return TmuxLockEntry{}, fmt.Errorf("reading %s: %w", p.URL(), result.Error)
+	}
+	entry := TmuxLockEntry{Branch: p.Branch}
+	for _, line := range strings.Split(result.Output, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 3 && fields[0] == "ref:":
+			entry.Branch = strings.TrimPrefix(fields[1], "refs/heads/")
+		case len(fields) == 2 && len(fields[0]) == 40:
+			entry.Commit = fields[0]
+		}
+	}
+	if entry.Commit == "" || entry.Branch == "" {
+		return TmuxLockEntry{}, fmt.Errorf("%s has no %s", p.URL(), ref)
+	}
+	return entry, nil
+}
+
+// VerifyTmuxPlugin checks that dir holds something TPM can load: the tpm
+// script itself, or the *.tmux files every plugin runs from
+func VerifyTmuxPlugin(dir string, p TmuxPlugin) error {
+	if p.Name == "tpm" {
+		if !FileExists(filepath.Join(dir, "tpm")) {
+			return fmt.Errorf("%s: tpm script missing", dir)
+		}
+		return nil
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.tmux"))
+	if len(matches) == 0 {
+		return fmt.Errorf("%s: no *.tmux file to load", dir)
+	}
+	return nil
+}
+
+// gitHead is the commit checked out in dir, "" when it is not a repository
+func gitHead(dir string) string {
+	if !FileExists(filepath.Join(dir, ".git")) {
+		return ""
+	}
+	result := Run(fmt.Sprintf("git -C %q rev-parse HEAD", dir), nil)
+	if result.Error != nil {
+		return ""
+	}
+	return strings.TrimSpace(result.Output)
+}
+
+// gitBranch is the branch p follows: the declared one, else the clone's default
+func gitBranch(dir string, p TmuxPlugin) string {
+	if p.Branch != "" {
+		return p.Branch
+	}
+	result := Run(fmt.Sprintf("git -C %q symbolic-ref --short -q HEAD", dir), nil)
+	if branch := strings.TrimSpace(result.Output); result.Error == nil && branch != "" {
+		return branch
+	}
+	result = Run(fmt.Sprintf("git -C %q symbolic-ref --short -q refs/remotes/origin/HEAD", dir), nil)
+	return strings.TrimPrefix(strings.TrimSpace(result.Output), "origin/")
+}
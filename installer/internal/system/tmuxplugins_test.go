@@ -0,0 +1,180 @@
+package system
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTmuxPlugins_ShippedConfig(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "GentlemanTmux", "tmux.conf"))
+	if err != nil {
+		t.Fatalf("reading shipped tmux.conf: %v", err)
+	}
+	var names []string
+	for _, p := range ParseTmuxPlugins(string(data)) {
+		names = append(names, p.Name)
+	}
+	want := "tpm tmux-sensible tmux-yank vim-tmux-navigator tmux-resurrect tmux-which-key tmux-kanagawa"
+	if strings.Join(names, " ") != want {
+		t.Errorf("expected %q, got %q", want, strings.Join(names, " "))
+	}
+}
+
+func TestParseTmuxPlugins_Syntax(t *testing.T) {
+	plugins := ParseTmuxPlugins(`set -g @plugin "owner/one#v2"
+set-option -g @plugin 'https://gitlab.com/owner/two.git'
+# set -g @plugin 'owner/disabled'
+set -g @plugin 'owner/one'`)
+	if len(plugins) != 2 {
+		t.Fatalf("expected 2 plugins, got %+v", plugins)
+	}
+	if plugins[0] != (TmuxPlugin{Name: "one", Repo: "owner/one", Branch: "v2"}) || plugins[0].URL() != "https://github.com/owner/one" {
+		t.Errorf("unexpected first plugin %+v", plugins[0])
+	}
+	if plugins[1].Name != "two" || plugins[1].URL() != "https://gitlab.com/owner/two.git" {
+		t.Errorf("unexpected second plugin %+v", plugins[1])
+	}
+}
+
+func TestTmuxLock_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tmux-lock.json")
+	if lock, err := ReadTmuxLock(path); err != nil || len(lock) != 0 {
+		t.Fatalf("missing lock should be empty, got %v, %v", lock, err)
+	}
+	lock := TmuxLock{"tpm": {Branch: "master", Commit: "abc"}, "tmux-yank": {Branch: "master", Commit: "def"}}
+	if err := WriteTmuxLock(path, lock); err != nil {
+		t.Fatalf("WriteTmuxLock: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	want := "{\n" +
+		"  \"tmux-yank\": { \"branch\": \"master\", \"commit\": \"def\" },\n" +
+		"  \"tpm\": { \"branch\": \"master\", \"commit\": \"abc\" }\n" +
+		"}\n"
+	if string(data) != want {
+		t.Errorf("unexpected lock file:\n%s", data)
+	}
+	if got, err := ReadTmuxLock(path); err != nil || got["tpm"].Commit != "abc" {
+		t.Errorf("round trip failed: %v, %v", got, err)
+	}
+}
+
+// pluginRepo creates a git repository with two commits of a plugin
+func pluginRepo(t *testing.T) (string, string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := filepath.Join(t.TempDir(), "tmux-demo")
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	os.MkdirAll(repo, 0755)
+	git("init", "-q", "-b", "main")
+	os.WriteFile(filepath.Join(repo, "demo.tmux"), []byte("#!/bin/sh\n"), 0755)
+	git("add", "-A")
+	git("commit", "-q", "-m", "first")
+	first := git("rev-parse", "HEAD")
+	os.WriteFile(filepath.Join(repo, "README"), []byte("second\n"), 0644)
+	git("add", "-A")
+	git("commit", "-q", "-m", "second")
+	return repo, first, git("rev-parse", "HEAD")
+}
+
+func TestInstallTmuxPlugin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo, first, second := pluginRepo(t)
+	plugin := TmuxPlugin{Name: "tmux-demo", Repo: repo}
+	dir := filepath.Join(t.TempDir(), "plugins", "tmux-demo")
+
+	// An empty placeholder directory is replaced
+	os.MkdirAll(dir, 0755)
+	entry, err := InstallTmuxPlugin(dir, plugin, "", nil)
+	if err != nil {
+		t.Fatalf("InstallTmuxPlugin: %v", err)
+	}
+	if entry != (TmuxLockEntry{Branch: "main", Commit: second}) {
+		t.Errorf("expected the branch head, got %+v", entry)
+	}
+
+	entry, err = InstallTmuxPlugin(dir, plugin, first, nil)
+	if err != nil || entry.Commit != first || gitHead(dir) != first {
+		t.Fatalf("expected the pinned commit, got %+v, %v", entry, err)
+	}
+	if FileExists(filepath.Join(dir, "README")) {
+		t.Error("the pinned checkout should not have later files")
+	}
+
+	if _, err := InstallTmuxPlugin(dir, plugin, "0000000000000000000000000000000000000000", nil); err == nil {
+		t.Fatal("expected an unknown commit to fail")
+	}
+	if gitHead(dir) != first {
+		t.Error("a failed update must keep the installed copy")
+	}
+}
+
+func TestResolveTmuxPlugin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo, first, second := pluginRepo(t)
+	entry, err := ResolveTmuxPlugin(TmuxPlugin{Name: "tmux-demo", Repo: repo})
+	if err != nil || entry != (TmuxLockEntry{Branch: "main", Commit: second}) {
+		t.Fatalf("expected the head of main, got %+v, %v", entry, err)
+	}
+	exec.Command("git", "-C", repo, "branch", "-q", "stable", first).Run()
+	entry, err = ResolveTmuxPlugin(TmuxPlugin{Name: "tmux-demo", Repo: repo, Branch: "stable"})
+	if err != nil || entry != (TmuxLockEntry{Branch: "stable", Commit: first}) {
+		t.Fatalf("expected the head of stable, got %+v, %v", entry, err)
+	}
+	if _, err := ResolveTmuxPlugin(TmuxPlugin{Name: "tmux-demo", Repo: repo, Branch: "missing"}); err == nil {
+		t.Error("expected an error for a missing branch")
+	}
+}
+
+// TestShippedTmuxLock keeps GentlemanTmux/tmux-lock.json in step with tmux.conf:
+// every plugin pinned to a full commit, nothing stale
+func TestShippedTmuxLock(t *testing.T) {
+	dir := filepath.Join("..", "..", "..", "GentlemanTmux")
+	data, err := os.ReadFile(filepath.Join(dir, "tmux.conf"))
+	if err != nil {
+		t.Fatalf("reading shipped tmux.conf: %v", err)
+	}
+	if !FileExists(filepath.Join(dir, "tmux-lock.json")) {
+		t.Skip("no shipped tmux-lock.json yet; generate it with `gentleman.dots tmux lock <repo>`")
+	}
+	lock, err := ReadTmuxLock(filepath.Join(dir, "tmux-lock.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugins := ParseTmuxPlugins(string(data))
+	for _, p := range plugins {
+		entry, ok := lock[p.Name]
+		if !ok || len(entry.Commit) != 40 || entry.Branch == "" {
+			t.Errorf("%s is not pinned to a commit: %+v", p.Name, entry)
+		}
+	}
+	if len(lock) != len(plugins) {
+		t.Errorf("the lock has %d entries for %d plugins", len(lock), len(plugins))
+	}
+}
+
+func TestVerifyTmuxPlugin(t *testing.T) {
+	dir := t.TempDir()
+	if err := VerifyTmuxPlugin(dir, TmuxPlugin{Name: "tmux-yank"}); err == nil {
+		t.Error("an empty directory is not a plugin")
+	}
+	os.WriteFile(filepath.Join(dir, "yank.tmux"), nil, 0755)
+	if err := VerifyTmuxPlugin(dir, TmuxPlugin{Name: "tmux-yank"}); err != nil {
+		t.Errorf("VerifyTmuxPlugin: %v", err)
+	}
+	if err := VerifyTmuxPlugin(dir, TmuxPlugin{Name: "tpm"}); err == nil {
+		t.Error("tpm needs its tpm script")
+	}
+}
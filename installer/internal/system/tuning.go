@@ -0,0 +1,385 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TerminalSetting is a setting every supported terminal understands
+type TerminalSetting struct {
+	Key      string
+	Label    string
+	Min, Max float64
+	Default  float64 // terminal default when the config does not set it
+	Step     float64 // increment used by the tuning screen
+	Integer  bool
+}
+
+// TerminalSettings lists what `set terminal.<key>` and the tuning screen edit
+var TerminalSettings = []TerminalSetting{
+	{Key: "font-size", Label: "Font size", Min: 6, Max: 72, Default: 14, Step: 1},
+	{Key: "opacity", Label: "Background opacity", Min: 0, Max: 1, Default: 1, Step: 0.05},
+	{Key: "padding", Label: "Window padding (px)", Min: 0, Max: 100, Default: 0, Step: 2, Integer: true},
+	{Key: "blur", Label: "Background blur", Min: 0, Max: 100, Default: 0, Step: 5, Integer: true},
+}
+
+// LookupTerminalSetting finds a setting by key
+func LookupTerminalSetting(key string) (TerminalSetting, bool) {
+	for _, s := range TerminalSettings {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return TerminalSetting{}, false
+}
+
+// Parse validates a value for the setting
+func (s TerminalSetting) Parse(value string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number, got %q", s.Key, value)
+	}
+	if s.Integer && v != float64(int(v)) {
+		return 0, fmt.Errorf("%s must be a whole number, got %s", s.Key, value)
+	}
+	if v < s.Min || v > s.Max {
+		return 0, fmt.Errorf("%s must be between %s and %s", s.Key, FormatSetting(s.Min), FormatSetting(s.Max))
+	}
+	return v, nil
+}
+
+// FormatSetting prints a value without trailing zeros
+func FormatSetting(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// floatLiteral keeps a decimal point for configs that ship floats ("14.0")
+func floatLiteral(v float64) string {
+	s := FormatSetting(v)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+// TuneTerminal writes one setting into a terminal config, leaving comments
+// and every other line untouched. A missing setting is added.
+func TuneTerminal(terminal, path, key string, value float64) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	var updated string
+	switch terminal {
+	case "alacritty":
+		updated = tuneAlacritty(content, key, value)
+	case "wezterm":
+		updated = tuneWezterm(content, key, value)
+	case "kitty":
+		updated = tuneKitty(content, key, value)
+	case "ghostty":
+		updated = tuneGhostty(content, key, value)
+	default:
+		return fmt.Errorf("unsupported terminal for tuning: %s", terminal)
+	}
+	if updated == content {
+		return nil
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// TerminalSettingValues reads the current settings of a terminal config.
+// Settings the config does not set are missing from the result.
+func TerminalSettingValues(terminal, content string) map[string]float64 {
+	values := map[string]float64{}
+	set := func(key, raw string) {
+		if v, err := strconv.ParseFloat(strings.Trim(raw, `"'`), 64); err == nil {
+			values[key] = v
+		}
+	}
+	switch terminal {
+	case "alacritty":
+		set("font-size", tomlValue(content, "font", "size"))
+		set("opacity", tomlValue(content, "window", "opacity"))
+		set("padding", tomlValue(content, "window.padding", "x"))
+		switch tomlValue(content, "window", "blur") {
+		case "true":
+			values["blur"] = alacrittyBlurOn
+		case "false":
+			values["blur"] = 0
+		}
+	case "wezterm":
+		set("font-size", luaValue(content, "font_size"))
+		set("opacity", luaValue(content, "window_background_opacity"))
+		set("padding", luaTableField(content, "window_padding", "left"))
+		set("blur", luaValue(content, "macos_window_background_blur"))
+	case "kitty":
+		set("font-size", spacedValue(content, "font_size"))
+		set("opacity", spacedValue(content, "background_opacity"))
+		set("padding", spacedValue(content, "window_padding_width"))
+		set("blur", spacedValue(content, "background_blur"))
+	case "ghostty":
+		set("font-size", ghosttyValue(content, "font-size"))
+		set("opacity", ghosttyValue(content, "background-opacity"))
+		set("padding", ghosttyValue(content, "window-padding-x"))
+		set("blur", ghosttyValue(content, ghosttyBlurKey(content)))
+	}
+	return values
+}
+
+// alacrittyBlurOn is reported for `blur = true`; Alacritty has no radius
+const alacrittyBlurOn = 20
+
+func tuneAlacritty(content, key string, v float64) string {
+	switch key {
+	case "font-size":
+		return setTOMLValue(content, "font", "size", FormatSetting(v))
+	case "opacity":
+		return setTOMLValue(content, "window", "opacity", FormatSetting(v))
+	case "padding":
+		content = setTOMLValue(content, "window.padding", "y", FormatSetting(v))
+		return setTOMLValue(content, "window.padding", "x", FormatSetting(v))
+	case "blur":
+		return setTOMLValue(content, "window", "blur", strconv.FormatBool(v > 0))
+	}
+	return content
+}
+
+func tuneWezterm(content, key string, v float64) string {
+	switch key {
+	case "font-size":
+		return setLuaValue(content, "font_size", floatLiteral(v))
+	case "opacity":
+		return setLuaValue(content, "window_background_opacity", FormatSetting(v))
+	case "padding":
+		return setLuaTable(content, "window_padding", []string{"top", "right", "left", "bottom"}, FormatSetting(v))
+	case "blur":
+		return setLuaValue(content, "macos_window_background_blur", FormatSetting(v))
+	}
+	return content
+}
+
+func tuneKitty(content, key string, v float64) string {
+	switch key {
+	case "font-size":
+		return setKittyValue(content, "font_size", floatLiteral(v))
+	case "opacity":
+		return setKittyValue(content, "background_opacity", FormatSetting(v))
+	case "padding":
+		return setKittyValue(content, "window_padding_width", FormatSetting(v), "background_blur", "background_opacity")
+	case "blur":
+		return setKittyValue(content, "background_blur", FormatSetting(v), "background_opacity")
+	}
+	return content
+}
+
+func tuneGhostty(content, key string, v float64) string {
+	switch key {
+	case "font-size":
+		return setGhosttyValue(content, "font-size", FormatSetting(v))
+	case "opacity":
+		return setGhosttyValue(content, "background-opacity", FormatSetting(v))
+	case "padding":
+		content = setGhosttyValue(content, "window-padding-x", FormatSetting(v), "window-padding-balance", "window-padding-color")
+		return setGhosttyValue(content, "window-padding-y", FormatSetting(v), "window-padding-x")
+	case "blur":
+		return setGhosttyValue(content, ghosttyBlurKey(content), FormatSetting(v), "background-opacity")
+	}
+	return content
+}
+
+// ghosttyBlurKey keeps the older background-blur-radius name when the config uses it
+func ghosttyBlurKey(content string) string {
+	if ghosttyValue(content, "background-blur") == "" && ghosttyValue(content, "background-blur-radius") != "" {
+		return "background-blur-radius"
+	}
+	return "background-blur"
+}
+
+// tomlValue returns the raw value of key in table, "" when unset
+func tomlValue(content, table, key string) string {
+	keyRe := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(key) + `\s*=\s*("[^"]*"|'[^']*'|[^\s#]+)`)
+	current := ""
+	for _, line := range strings.Split(content, "\n") {
+		if m := tomlTableRe.FindStringSubmatch(line); m != nil {
+			current = strings.TrimSpace(m[1])
+			continue
+		}
+		if current != table {
+			continue
+		}
+		if m := keyRe.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func luaKeyRe(key string) *regexp.Regexp {
+	return regexp.MustCompile(`^(\s*config\.` + regexp.QuoteMeta(key) + `\s*=\s*)([^\s,{}-]+)(.*)$`)
+}
+
+// luaValue returns the value of `config.key = value`, "" when unset
+func luaValue(content, key string) string {
+	re := luaKeyRe(key)
+	for _, line := range strings.Split(content, "\n") {
+		if m := re.FindStringSubmatch(line); m != nil {
+			return m[2]
+		}
+	}
+	return ""
+}
+
+// setLuaValue sets `config.key = value`, keeping a trailing comment, or
+// adds it before `return config`
+func setLuaValue(content, key, value string) string {
+	re := luaKeyRe(key)
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if m := re.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1] + value + m[3]
+			return strings.Join(lines, "\n")
+		}
+	}
+	return insertBeforeReturn(content, "config."+key+" = "+value)
+}
+
+// luaTableBounds returns the lines of `config.name = {` and its closing brace
+func luaTableBounds(lines []string, name string) (int, int) {
+	openRe := regexp.MustCompile(`^\s*config\.` + regexp.QuoteMeta(name) + `\s*=\s*\{\s*(--.*)?$`)
+	for i, line := range lines {
+		if !openRe.MatchString(line) {
+			continue
+		}
+		for j := i + 1; j < len(lines); j++ {
+			if strings.HasPrefix(strings.TrimSpace(lines[j]), "}") {
+				return i, j
+			}
+		}
+	}
+	return -1, -1
+}
+
+func luaFieldRe(field string) *regexp.Regexp {
+	return regexp.MustCompile(`^(\s*` + regexp.QuoteMeta(field) + `\s*=\s*)([^\s,]+)(.*)$`)
+}
+
+// luaTableField returns a field of a multi-line `config.name = { ... }` table
+func luaTableField(content, name, field string) string {
+	lines := strings.Split(content, "\n")
+	start, end := luaTableBounds(lines, name)
+	re := luaFieldRe(field)
+	for i := start + 1; start >= 0 && i < end; i++ {
+		if m := re.FindStringSubmatch(lines[i]); m != nil {
+			return m[2]
+		}
+	}
+	return ""
+}
+
+// setLuaTable sets fields of a multi-line `config.name = { ... }` table to
+// value, adding missing fields, or adds the table before `return config`
+func setLuaTable(content, name string, fields []string, value string) string {
+	lines := strings.Split(content, "\n")
+	start, end := luaTableBounds(lines, name)
+	if start < 0 {
+		table := []string{"config." + name + " = {"}
+		for _, field := range fields {
+			table = append(table, "\t"+field+" = "+value+",")
+		}
+		return insertBeforeReturn(content, strings.Join(append(table, "}"), "\n"))
+	}
+	for _, field := range fields {
+		re := luaFieldRe(field)
+		found := false
+		for i := start + 1; i < end; i++ {
+			if m := re.FindStringSubmatch(lines[i]); m != nil {
+				lines[i] = m[1] + value + m[3]
+				found = true
+				break
+			}
+		}
+		if !found {
+			lines = append(lines[:end], append([]string{"\t" + field + " = " + value + ","}, lines[end:]...)...)
+			end++
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// spacedValue returns the value of an active `key value` line (kitty)
+func spacedValue(content, key string) string {
+	re := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(key) + `\s+(\S+)`)
+	for _, line := range strings.Split(content, "\n") {
+		if m := re.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// setKittyValue sets a kitty option; a commented-out default
+// (`# background_blur 20`) is enabled in place. A new option goes below
+// the first near option that is set.
+func setKittyValue(content, key, value string, near ...string) string {
+	if spacedValue(content, key) != "" {
+		return setSpacedValue(content, key, value)
+	}
+	re := regexp.MustCompile(`^(\s*)#\s*(` + regexp.QuoteMeta(key) + `\s+)\S+\s*$`)
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if m := re.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1] + m[2] + value
+			return strings.Join(lines, "\n")
+		}
+	}
+	return insertNear(content, key+" "+value, near, spacedValue)
+}
+
+func ghosttyKeyRe(key string) *regexp.Regexp {
+	return regexp.MustCompile(`^(\s*` + regexp.QuoteMeta(key) + `\s*=\s*)(.*?)\s*$`)
+}
+
+// ghosttyValue returns the value of `key = value`, "" when unset
+func ghosttyValue(content, key string) string {
+	re := ghosttyKeyRe(key)
+	for _, line := range strings.Split(content, "\n") {
+		if m := re.FindStringSubmatch(line); m != nil {
+			return m[2]
+		}
+	}
+	return ""
+}
+
+// setGhosttyValue sets `key = value`, keeping the line's alignment. A new
+// key goes below the first near key that is set.
+func setGhosttyValue(content, key, value string, near ...string) string {
+	re := ghosttyKeyRe(key)
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if m := re.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1] + value
+			return strings.Join(lines, "\n")
+		}
+	}
+	return insertNear(content, key+" = "+value, near, ghosttyValue)
+}
+
+// insertNear adds entry below the line of the first near key that valueOf
+// finds, or appends it
+func insertNear(content, entry string, near []string, valueOf func(content, key string) string) string {
+	lines := strings.Split(content, "\n")
+	for _, key := range near {
+		for i, line := range lines {
+			if valueOf(line, key) != "" {
+				lines = append(lines[:i+1], append([]string{entry}, lines[i+1:]...)...)
+				return strings.Join(lines, "\n")
+			}
+		}
+	}
+	return strings.TrimRight(content, "\n") + "\n" + entry + "\n"
+}
@@ -0,0 +1,93 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// shippedTerminalConfigs maps terminals to the configs in the repository
+var shippedTerminalConfigs = map[string]string{
+	"alacritty": "alacritty.toml",
+	"wezterm":   ".wezterm.lua",
+	"kitty":     "GentlemanKitty/kitty.conf",
+	"ghostty":   "GentlemanGhostty/config",
+}
+
+func TestTuneTerminal(t *testing.T) {
+	want := map[string]float64{"font-size": 16, "opacity": 0.85, "padding": 8, "blur": 30}
+	for terminal, rel := range shippedTerminalConfigs {
+		original, err := os.ReadFile(filepath.Join("..", "..", "..", rel))
+		if err != nil {
+			t.Skipf("shipped %s config not available: %v", terminal, err)
+		}
+		if got := TerminalSettingValues(terminal, string(original)); got["font-size"] != 14 || got["opacity"] != 0.95 {
+			t.Errorf("%s: unexpected shipped values %v", terminal, got)
+		}
+
+		path := filepath.Join(t.TempDir(), filepath.Base(rel))
+		os.WriteFile(path, original, 0644)
+		for i := 0; i < 2; i++ {
+			for _, s := range TerminalSettings {
+				if err := TuneTerminal(terminal, path, s.Key, want[s.Key]); err != nil {
+					t.Fatalf("%s: %v", terminal, err)
+				}
+			}
+		}
+		data, _ := os.ReadFile(path)
+		got := TerminalSettingValues(terminal, string(data))
+		for key, v := range want {
+			if terminal == "alacritty" && key == "blur" {
+				v = alacrittyBlurOn // on/off only
+			}
+			if got[key] != v {
+				t.Errorf("%s: %s = %v, want %v", terminal, key, got[key], v)
+			}
+		}
+
+		// Only the tuned lines change: every other line is kept, comments included
+		tuned := map[string]bool{}
+		for _, line := range strings.Split(string(data), "\n") {
+			tuned[line] = true
+		}
+		changed := 0
+		for _, line := range strings.Split(string(original), "\n") {
+			if !tuned[line] {
+				changed++
+			}
+		}
+		if changed > 7 {
+			t.Errorf("%s: %d original lines changed:\n%s", terminal, changed, data)
+		}
+	}
+}
+
+func TestTuneTerminal_KeepsCommentsAndStyle(t *testing.T) {
+	got := setLuaValue("config.font_size = 14.0 -- big\nreturn config\n", "font_size", floatLiteral(16))
+	if got != "config.font_size = 16.0 -- big\nreturn config\n" {
+		t.Errorf("lua: %q", got)
+	}
+	got = setKittyValue("font_size        14.0\n# background_blur 20\n", "background_blur", "10")
+	if got != "font_size        14.0\nbackground_blur 10\n" {
+		t.Errorf("kitty: %q", got)
+	}
+	got = setLuaTable("local config = {}\nreturn config\n", "window_padding", []string{"left", "right"}, "4")
+	if !strings.Contains(got, "config.window_padding = {\n\tleft = 4,\n\tright = 4,\n}\n") || !strings.HasSuffix(got, "return config\n") {
+		t.Errorf("lua table: %q", got)
+	}
+}
+
+func TestTerminalSetting_Parse(t *testing.T) {
+	padding, _ := LookupTerminalSetting("padding")
+	if _, err := padding.Parse("4.5"); err == nil {
+		t.Error("expected a fractional padding to be rejected")
+	}
+	opacity, _ := LookupTerminalSetting("opacity")
+	if _, err := opacity.Parse("1.5"); err == nil {
+		t.Error("expected an opacity above 1 to be rejected")
+	}
+	if v, err := opacity.Parse("0.8"); err != nil || v != 0.8 {
+		t.Errorf("Parse(0.8) = %v, %v", v, err)
+	}
+}
@@ -0,0 +1,75 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// BlockWezterm holds the settings derived from the installer choices in wezterm.lua
+const BlockWezterm = "installer"
+
+// WeztermChoices are the installer selections reflected in the WezTerm config
+type WeztermChoices struct {
+	Shell       string // absolute path of the shell WezTerm starts; "" keeps WezTerm's default
+	Multiplexer string // "tmux", "zellij", "herdr", or "none" when WezTerm multiplexes itself
+}
+
+// weztermLeaderKeys mirror the Gentleman tmux bindings on WezTerm's own panes
+// and tabs, for setups without a multiplexer
+const weztermLeaderKeys = `config.leader = { key = "a", mods = "CTRL", timeout_milliseconds = 1000 }
+config.keys = {
+	{ key = "a", mods = "LEADER|CTRL", action = wezterm.action.SendKey({ key = "a", mods = "CTRL" }) },
+	{ key = "v", mods = "LEADER", action = wezterm.action.SplitHorizontal({ domain = "CurrentPaneDomain" }) },
+	{ key = "d", mods = "LEADER", action = wezterm.action.SplitVertical({ domain = "CurrentPaneDomain" }) },
+	{ key = "h", mods = "LEADER", action = wezterm.action.ActivatePaneDirection("Left") },
+	{ key = "j", mods = "LEADER", action = wezterm.action.ActivatePaneDirection("Down") },
+	{ key = "k", mods = "LEADER", action = wezterm.action.ActivatePaneDirection("Up") },
+	{ key = "l", mods = "LEADER", action = wezterm.action.ActivatePaneDirection("Right") },
+	{ key = "z", mods = "LEADER", action = wezterm.action.TogglePaneZoomState },
+	{ key = "x", mods = "LEADER", action = wezterm.action.CloseCurrentPane({ confirm = true }) },
+	{ key = "c", mods = "LEADER", action = wezterm.action.SpawnTab("CurrentPaneDomain") },
+	{ key = "n", mods = "LEADER", action = wezterm.action.ActivateTabRelative(1) },
+	{ key = "p", mods = "LEADER", action = wezterm.action.ActivateTabRelative(-1) },
+}`
+
+// WeztermBlock renders the managed section for c
+func WeztermBlock(c WeztermChoices) string {
+	var body []string
+	if c.Shell != "" {
+		body = append(body, fmt.Sprintf("config.default_prog = { %q, \"-l\" }", c.Shell))
+	}
+	switch c.Multiplexer {
+	case "tmux", "zellij", "herdr":
+		// No leader: Ctrl+a reaches the multiplexer untouched
+		body = append(body, fmt.Sprintf("-- %s owns Ctrl+a; WezTerm keeps its default CTRL+SHIFT bindings", c.Multiplexer))
+	default:
+		body = append(body, "-- No multiplexer: WezTerm panes and tabs on the Ctrl+a leader", weztermLeaderKeys)
+	}
+	return strings.Join(body, "\n")
+}
+
+// SetWeztermChoices writes the managed section of a WezTerm config, placing
+// it before the final `return config` the first time
+func SetWeztermChoices(path string, c WeztermChoices) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	var updated string
+	if HasBlock(content, SyntaxLua, BlockWezterm) {
+		updated, err = UpsertBlock(content, SyntaxLua, BlockWezterm, WeztermBlock(c))
+	} else {
+		var block string
+		block, err = UpsertBlock("", SyntaxLua, BlockWezterm, WeztermBlock(c))
+		updated = insertBeforeReturn(content, strings.TrimRight(block, "\n"))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if updated == content {
+		return nil
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
@@ -0,0 +1,55 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWeztermBlock(t *testing.T) {
+	tmux := WeztermBlock(WeztermChoices{Shell: "/opt/homebrew/bin/fish", Multiplexer: "tmux"})
+	if !strings.Contains(tmux, `config.default_prog = { "/opt/homebrew/bin/fish", "-l" }`) {
+		t.Errorf("expected default_prog for fish:\n%s", tmux)
+	}
+	if strings.Contains(tmux, "config.leader") || strings.Contains(tmux, "config.keys") {
+		t.Errorf("Ctrl+a must be left to tmux:\n%s", tmux)
+	}
+
+	none := WeztermBlock(WeztermChoices{Multiplexer: "none"})
+	if strings.Contains(none, "default_prog") {
+		t.Errorf("no shell should keep WezTerm's default program:\n%s", none)
+	}
+	if !strings.Contains(none, `config.leader = { key = "a", mods = "CTRL"`) {
+		t.Errorf("expected the Ctrl+a leader without a multiplexer:\n%s", none)
+	}
+}
+
+func TestSetWeztermChoices_ShippedConfig(t *testing.T) {
+	original, err := os.ReadFile(filepath.Join("..", "..", "..", ".wezterm.lua"))
+	if err != nil {
+		t.Fatalf("reading shipped config: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "wezterm.lua")
+	os.WriteFile(path, original, 0644)
+
+	if err := SetWeztermChoices(path, WeztermChoices{Shell: "/usr/bin/zsh", Multiplexer: "none"}); err != nil {
+		t.Fatalf("SetWeztermChoices: %v", err)
+	}
+	if err := SetWeztermChoices(path, WeztermChoices{Shell: "/usr/bin/fish", Multiplexer: "zellij"}); err != nil {
+		t.Fatalf("SetWeztermChoices: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+
+	body, ok := BlockBody(content, SyntaxLua, BlockWezterm)
+	if !ok || body != WeztermBlock(WeztermChoices{Shell: "/usr/bin/fish", Multiplexer: "zellij"}) {
+		t.Errorf("unexpected block body:\n%s", body)
+	}
+	if !strings.HasSuffix(content, "<<< gentleman.dots:installer <<<\n\nreturn config\n") {
+		t.Errorf("block must sit before the final return:\n%s", content[len(content)-200:])
+	}
+	if !strings.HasPrefix(content, string(original[:strings.Index(string(original), "return config")])) {
+		t.Error("the rest of the config must be kept")
+	}
+}
@@ -0,0 +1,76 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SetZellijOption sets a top-level `name "value"` option of a zellij config.kdl,
+// replacing any earlier copies of it
+func SetZellijOption(configPath, name, value string) error {
+	return editKDLFile(configPath, func(content string) (string, error) {
+		return SetKDLString(content, name, value)
+	})
+}
+
+// editKDLFile rewrites a KDL file through edit, leaving it alone when nothing changes
+func editKDLFile(path string, edit func(content string) (string, error)) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	updated, err := edit(string(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if updated == string(data) {
+		return nil
+	}
+	if err := EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// ZellijOption returns the value of a top-level option of a zellij config.kdl
+func ZellijOption(configPath, name string) (string, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return "", err
+	}
+	nodes, err := ParseKDL(string(data))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", configPath, err)
+	}
+	if node := FindKDLNode(nodes, name); node != nil && len(node.Args) > 0 {
+		return node.Args[0], nil
+	}
+	return "", nil
+}
+
+// SetZellijDefaultLayout makes zellij start with the named layout
+func SetZellijDefaultLayout(configPath, layout string) error {
+	return SetZellijOption(configPath, "default_layout", layout)
+}
+
+// ZellijLayouts lists the layouts in a zellij layouts directory by the name
+// default_layout takes, sorted
+func ZellijLayouts(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var layouts []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".kdl")
+		// *.swap.kdl files add swap layouts to their base layout
+		if ok && !entry.IsDir() && !strings.HasSuffix(name, ".swap") {
+			layouts = append(layouts, name)
+		}
+	}
+	sort.Strings(layouts)
+	return layouts, nil
+}
@@ -0,0 +1,222 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// actionKind is what a step action does
+type actionKind int
+
+const (
+	actionNote     actionKind = iota // only log a message
+	actionPackages                   // install packages with a package manager
+	actionRun                        // run a shell command
+	actionFetch                      // clone a git repository
+	actionBuild                      // run a build command inside a directory
+	actionCopy                       // deploy a config file or directory from the repo
+	actionPatch                      // edit a file in-process
+)
+
+// stepAction is one unit of work of an install step
+type stepAction struct {
+	Kind     actionKind
+	Log      string       // progress line shown before the action
+	Command  string       // packages/run/build: command line; fetch: repository URL
+	Dir      string       // fetch: clone destination; build: working directory
+	Src, Dst string       // copy: repo path and target
+	Tree     bool         // copy: Src is a directory
+	Sudo     bool         // run as root
+	Prompts  bool         // may ask for a password even without sudo
+	Optional bool         // a failure is logged and the step goes on
+	Fail     string       // error description (a warning when Optional)
+	OK       string       // logged when the action succeeds
+	Apply    func() error // patch
+}
+
+// needsTerminal reports whether the action may ask the user for input
+func (a stepAction) needsTerminal() bool {
+	return a.Sudo || a.Prompts
+}
+
+// stepPlan defines a step once; it either runs in-process (runPlan) or is
+// split so the password-requiring part runs as a script (splitPlan)
+type stepPlan struct {
+	ID      string
+	Name    string // step error name, e.g. "Install Alacritty"
+	Title   string // script banner
+	Actions []stepAction
+	Done    []string // logged after the last action
+}
+
+// planStep returns the plan of a step defined with actions
+func planStep(stepID string, m *Model) (*stepPlan, error) {
+	switch stepID {
+	case "deps":
+		return depsPlan(m), nil
+	case "terminal":
+		return terminalPlan(m)
+	case "setshell":
+		return setShellPlan(m)
+	}
+	return nil, fmt.Errorf("step %s is not defined with actions", stepID)
+}
+
+// runPlan executes every action of the plan in-process
+func runPlan(p *stepPlan) error {
+	if err := runActions(p, p.Actions); err != nil {
+		return err
+	}
+	for _, line := range p.Done {
+		SendLog(p.ID, line)
+	}
+	return nil
+}
+
+// runActions executes actions in-process, stopping at the first required failure
+func runActions(p *stepPlan, actions []stepAction) error {
+	onLog := func(line string) { SendLog(p.ID, line) }
+	for _, a := range actions {
+		if a.Log != "" {
+			SendLog(p.ID, a.Log)
+		}
+		var err error
+		switch a.Kind {
+		case actionPackages, actionRun, actionBuild:
+			command := a.Command
+			if a.Sudo {
+				command = system.SudoCommand(command)
+			}
+			opts := &system.ExecOptions{}
+			if a.Kind == actionBuild {
+				opts.WorkDir = a.Dir
+			}
+			err = system.RunWithLogs(command, opts, onLog).Error
+		case actionFetch:
+			os.RemoveAll(a.Dir)
+			err = system.GitClone(a.Command, a.Dir, onLog)
+		case actionCopy:
+			err = copyAction(a)
+		case actionPatch:
+			err = a.Apply()
+		}
+		if err == nil {
+			if a.OK != "" {
+				SendLog(p.ID, a.OK)
+			}
+			continue
+		}
+		if a.Optional {
+			SendLog(p.ID, "Warning: "+a.Fail)
+			continue
+		}
+		return wrapStepError(p.ID, p.Name, a.Fail, err)
+	}
+	return nil
+}
+
+// copyAction deploys a config file or directory into place
+func copyAction(a stepAction) error {
+	if a.Tree {
+		// A missing directory is linked whole in link mode, so only its parent is created
+		dir := a.Dst
+		if linkMode.enabled {
+			dir = filepath.Dir(a.Dst)
+		}
+		if err := system.EnsureDir(dir); err != nil {
+			return err
+		}
+		return deployDir(a.Src, a.Dst)
+	}
+	if err := system.EnsureDir(filepath.Dir(a.Dst)); err != nil {
+		return err
+	}
+	return deployFile(a.Src, a.Dst)
+}
+
+// splitPlan separates the span from the first to the last action that needs
+// the terminal; the actions around it run in-process
+func splitPlan(p *stepPlan) (before, script, after []stepAction) {
+	first, last := -1, -1
+	for i, a := range p.Actions {
+		if a.needsTerminal() {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return p.Actions, nil, nil
+	}
+	return p.Actions[:first], p.Actions[first : last+1], p.Actions[last+1:]
+}
+
+// renderScript renders actions as the script handed to tea.ExecProcess
+func renderScript(p *stepPlan, actions []stepAction, done []string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#!/bin/sh\nset -e\necho \"\"\necho %s\necho \"   (You may be prompted for your password)\"\necho \"\"\n", shellQuote(p.Title))
+	for _, a := range actions {
+		if a.Log != "" {
+			fmt.Fprintf(&b, "echo %s\n", shellQuote(a.Log))
+		}
+		var command string
+		switch a.Kind {
+		case actionNote:
+			continue
+		case actionPackages, actionRun:
+			command = a.Command
+			if a.Sudo {
+				command = system.SudoCommand(command)
+			}
+		case actionBuild:
+			command = fmt.Sprintf("(cd %s && %s)", shellQuote(a.Dir), a.Command)
+		case actionFetch:
+			command = fetchCommand(a.Command, a.Dir)
+		case actionCopy:
+			if a.Tree {
+				command = deployConfigDirCommand(a.Src, a.Dst)
+			} else {
+				command = deployConfigCommand(a.Src, a.Dst)
+			}
+		case actionPatch:
+			return "", fmt.Errorf("%s: a patch cannot run inside the script (%s)", p.ID, a.Fail)
+		}
+		ok := ":"
+		if a.OK != "" {
+			ok = "echo " + shellQuote(a.OK)
+		}
+		if a.Optional {
+			command = fmt.Sprintf("if { %s\n}; then\n    %s\nelse\n    echo %s\nfi", command, ok, shellQuote("⚠️  "+a.Fail))
+		} else if a.OK != "" {
+			command += "\n" + ok
+		}
+		b.WriteString(command + "\n")
+	}
+	b.WriteString("echo \"\"\n")
+	for _, line := range done {
+		fmt.Fprintf(&b, "echo %s\n", shellQuote(line))
+	}
+	b.WriteString("echo \"\"\n" + interactiveContinuePrompt + "\n")
+	return b.String(), nil
+}
+
+// fetchCommand clones url into dir from the cached mirror, which
+// prepareInteractiveStep refreshes before the script runs; without a mirror
+// (git is not installed yet) it clones url directly
+func fetchCommand(url, dir string) string {
+	if !system.HasGitMirror(url) {
+		return fmt.Sprintf("rm -rf %s\ngit clone %s %s", shellQuote(dir), shellQuote(url), shellQuote(dir))
+	}
+	return fmt.Sprintf("rm -rf %s\ngit clone %s %s\ngit -C %s remote set-url origin %s",
+		shellQuote(dir), shellQuote(system.GitMirrorDir(url)), shellQuote(dir), shellQuote(dir), shellQuote(url))
+}
+
+// shellQuote single-quotes s for sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
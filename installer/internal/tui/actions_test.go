@@ -0,0 +1,250 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// sampleActions writes into dir through every kind the script can render
+func sampleActions(dir string) []stepAction {
+	return []stepAction{
+		{Kind: actionNote, Log: "starting"},
+		{Kind: actionRun, Log: "Writing marker...", Command: "echo ran > " + shellQuote(filepath.Join(dir, "marker")),
+			Prompts: true, Fail: "Failed to write marker"},
+		{Kind: actionBuild, Command: "touch built", Dir: dir, Fail: "Failed to build"},
+		{Kind: actionRun, Command: "false", Optional: true, Fail: "optional step failed"},
+	}
+}
+
+func TestSplitPlan(t *testing.T) {
+	p := &stepPlan{Actions: []stepAction{
+		{Kind: actionNote},
+		{Kind: actionRun},
+		{Kind: actionPackages, Sudo: true},
+		{Kind: actionBuild},
+		{Kind: actionRun, Prompts: true},
+		{Kind: actionCopy},
+	}}
+	before, script, after := splitPlan(p)
+	if len(before) != 2 || len(script) != 3 || len(after) != 1 {
+		t.Fatalf("unexpected split %d/%d/%d", len(before), len(script), len(after))
+	}
+
+	p.Actions = []stepAction{{Kind: actionRun}, {Kind: actionCopy}}
+	if before, script, _ := splitPlan(p); len(before) != 2 || script != nil {
+		t.Errorf("expected everything in-process without sudo, got %d/%d", len(before), len(script))
+	}
+}
+
+func TestActions_InProcessMatchesScript(t *testing.T) {
+	inProcess, scripted := t.TempDir(), t.TempDir()
+
+	p := &stepPlan{ID: "deps", Name: "Install Dependencies", Title: "Testing", Actions: sampleActions(inProcess)}
+	if err := runPlan(p); err != nil {
+		t.Fatalf("runPlan: %v", err)
+	}
+
+	p.Actions = sampleActions(scripted)
+	script, err := renderScript(p, p.Actions, []string{"✓ done"})
+	if err != nil {
+		t.Fatalf("renderScript: %v", err)
+	}
+	if out, err := exec.Command("sh", "-c", script).CombinedOutput(); err != nil {
+		t.Fatalf("script failed: %v\n%s", err, out)
+	} else if !strings.Contains(string(out), "optional step failed") {
+		t.Errorf("expected the optional failure to be reported, got:\n%s", out)
+	}
+
+	for _, dir := range []string{inProcess, scripted} {
+		for _, name := range []string{"marker", "built"} {
+			if !system.FileExists(filepath.Join(dir, name)) {
+				t.Errorf("%s missing in %s", name, dir)
+			}
+		}
+	}
+}
+
+func TestRunActions_RequiredFailure(t *testing.T) {
+	p := &stepPlan{ID: "terminal", Name: "Install Kitty", Actions: []stepAction{
+		{Kind: actionRun, Command: "false", Fail: "Failed to install Kitty"},
+		{Kind: actionPatch, Apply: func() error { t.Error("ran past a failed action"); return nil }},
+	}}
+	err := runPlan(p)
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Description != "Failed to install Kitty" {
+		t.Fatalf("expected a step error, got %v", err)
+	}
+
+	if _, err := renderScript(p, p.Actions, nil); err == nil {
+		t.Error("expected a patch inside the script to be rejected")
+	}
+}
+
+func TestTerminalPlan_ConfigCopiedInProcess(t *testing.T) {
+	t.Setenv("PATH", "")
+	t.Setenv("HOME", t.TempDir())
+	m := NewModel()
+	m.SystemInfo.OS = system.OSArch
+	m.Choices.Terminal = "ghostty"
+
+	p, err := terminalPlan(&m)
+	if err != nil {
+		t.Fatalf("terminalPlan: %v", err)
+	}
+	_, script, after := splitPlan(p)
+	rendered, err := renderScript(p, script, nil)
+	if err != nil {
+		t.Fatalf("renderScript: %v", err)
+	}
+	if !strings.Contains(rendered, "pacman -S --needed --noconfirm ghostty") {
+		t.Errorf("expected the pacman install in the script:\n%s", rendered)
+	}
+	if len(after) != 1 || after[0].Kind != actionCopy || !after[0].Tree {
+		t.Errorf("expected the config copy to run in-process, got %+v", after)
+	}
+}
+
+func TestTerminalPlan_AlacrittyBuildNeedsRustup(t *testing.T) {
+	t.Setenv("PATH", "")
+	t.Setenv("HOME", t.TempDir())
+	m := NewModel()
+	m.SystemInfo.OS = system.OSDebian
+	m.Choices.Terminal = "alacritty"
+
+	if _, err := terminalPlan(&m); !errors.Is(err, system.ErrInstallScriptRefused) {
+		t.Fatalf("expected rustup to be refused by default, got %v", err)
+	}
+
+	system.SetAllowInstallScripts(true)
+	t.Cleanup(func() { system.SetAllowInstallScripts(false) })
+	p, err := terminalPlan(&m)
+	if err != nil {
+		t.Fatalf("terminalPlan: %v", err)
+	}
+	_, script, _ := splitPlan(p)
+	rendered, err := renderScript(p, script, nil)
+	if err != nil {
+		t.Fatalf("renderScript: %v", err)
+	}
+	cargo := shellQuote(filepath.Join(os.Getenv("HOME"), ".cargo/bin/cargo"))
+	for _, want := range []string{"sh.rustup.rs", "git clone " + shellQuote(alacrittyRepoURL), cargo + " build --release", "/usr/local/bin/alacritty"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("expected %q in the build script:\n%s", want, rendered)
+		}
+	}
+}
+
+func TestTerminalPlan_KittyPerDistro(t *testing.T) {
+	t.Setenv("PATH", "")
+	t.Setenv("HOME", t.TempDir())
+	m := NewModel()
+	m.Choices.Terminal = "kitty"
+
+	m.SystemInfo.OS = system.OSFedora
+	p, err := terminalPlan(&m)
+	if err != nil {
+		t.Fatalf("terminalPlan: %v", err)
+	}
+	if p.Actions[0].Command != "dnf install -y kitty" || !p.Actions[0].Sudo {
+		t.Errorf("expected the Fedora package, got %+v", p.Actions[0])
+	}
+
+	// Debian gets the upstream bundle, installed without sudo
+	m.SystemInfo.OS = system.OSDebian
+	p, err = terminalPlan(&m)
+	if err != nil {
+		t.Fatalf("terminalPlan: %v", err)
+	}
+	if _, script, _ := splitPlan(p); script != nil {
+		t.Errorf("the bundle install should not need a script, got %+v", script)
+	}
+	if p.Actions[0].Kind != actionPatch || p.Actions[0].Apply == nil {
+		t.Errorf("expected the in-process bundle install, got %+v", p.Actions[0])
+	}
+
+	m.SystemInfo.Libc = "musl"
+	if _, err := terminalPlan(&m); err == nil {
+		t.Error("the glibc bundle must be refused on musl")
+	}
+}
+
+func TestTerminalPlan_GhosttyUnpackaged(t *testing.T) {
+	t.Setenv("PATH", "")
+	t.Setenv("HOME", t.TempDir())
+	m := NewModel()
+	m.SystemInfo.OS = system.OSAlpine
+	m.Choices.Terminal = "ghostty"
+
+	var stepErr *StepError
+	if _, err := terminalPlan(&m); !errors.As(err, &stepErr) || !strings.Contains(stepErr.Description, "no package") {
+		t.Fatalf("expected a clear unsupported error on Alpine, got %v", err)
+	}
+
+	m.SystemInfo.OS = system.OSSUSE
+	p, err := terminalPlan(&m)
+	if err != nil {
+		t.Fatalf("terminalPlan: %v", err)
+	}
+	if p.Actions[0].Command != "zypper --non-interactive install ghostty" {
+		t.Errorf("expected the openSUSE package, got %+v", p.Actions[0])
+	}
+}
+
+func TestSetShellPlan_Termux(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	prefix := t.TempDir()
+	t.Setenv("PREFIX", prefix)
+	os.MkdirAll(filepath.Join(prefix, "bin"), 0755)
+	os.WriteFile(filepath.Join(prefix, "bin", "zsh"), []byte("#!/bin/sh\n"), 0755)
+
+	m := NewModel()
+	m.SystemInfo.IsTermux = true
+	m.Choices.Shell = "zsh"
+	p, err := setShellPlan(&m)
+	if err != nil {
+		t.Fatalf("setShellPlan: %v", err)
+	}
+	// No password needed: the whole step runs in-process
+	if _, script, _ := splitPlan(p); script != nil {
+		t.Fatalf("expected no script on Termux, got %d actions", len(script))
+	}
+	if err := runPlan(p); err != nil {
+		t.Fatalf("runPlan: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".bashrc"))
+	if !strings.Contains(string(data), "exec "+filepath.Join(prefix, "bin", "zsh")) {
+		t.Errorf("expected the auto-start block, got:\n%s", data)
+	}
+}
+
+func TestRenderScript_FetchUsesMirror(t *testing.T) {
+	system.SetCacheDir(t.TempDir())
+	t.Cleanup(func() { system.SetCacheDir("") })
+	dir := filepath.Join(t.TempDir(), "alacritty-build")
+	p := &stepPlan{ID: "terminal", Title: "Build", Actions: []stepAction{
+		{Kind: actionFetch, Command: alacrittyRepoURL, Dir: dir, Fail: "Failed to clone"},
+	}}
+
+	rendered, err := renderScript(p, p.Actions, nil)
+	if err != nil {
+		t.Fatalf("renderScript: %v", err)
+	}
+	if !strings.Contains(rendered, "git clone "+shellQuote(alacrittyRepoURL)) {
+		t.Errorf("expected a direct clone without a mirror:\n%s", rendered)
+	}
+
+	mirror := system.GitMirrorDir(alacrittyRepoURL)
+	os.MkdirAll(mirror, 0755)
+	os.WriteFile(filepath.Join(mirror, "HEAD"), []byte("ref: refs/heads/master\n"), 0644)
+	if rendered, _ = renderScript(p, p.Actions, nil); !strings.Contains(rendered, "git clone "+shellQuote(mirror)+" "+shellQuote(dir)) ||
+		!strings.Contains(rendered, "remote set-url origin "+shellQuote(alacrittyRepoURL)) {
+		t.Errorf("expected the clone to come from the cached mirror:\n%s", rendered)
+	}
+}
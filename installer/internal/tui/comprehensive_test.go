@@ -127,7 +127,7 @@ func TestTerminalOptionsPerOS(t *testing.T) {
 		}
 	})
 
-	t.Run("linux should not have Kitty option", func(t *testing.T) {
+	t.Run("linux should have Kitty option", func(t *testing.T) {
 		m := NewModel()
 		m.Screen = ScreenTerminalSelect
 		m.Choices.OS = "linux"
@@ -140,8 +140,21 @@ func TestTerminalOptionsPerOS(t *testing.T) {
 				break
 			}
 		}
-		if hasKitty {
-			t.Error("Linux should not have Kitty option")
+		if !hasKitty {
+			t.Error("Linux should have Kitty option")
+		}
+	})
+
+	t.Run("alpine should not have Ghostty option", func(t *testing.T) {
+		m := NewModel()
+		m.SystemInfo = &system.SystemInfo{OS: system.OSAlpine}
+		m.Screen = ScreenTerminalSelect
+		m.Choices.OS = "linux"
+
+		for _, opt := range m.GetCurrentOptions() {
+			if strings.Contains(opt, "Ghostty") {
+				t.Error("Alpine should not have Ghostty option")
+			}
 		}
 	})
 }
@@ -877,7 +890,7 @@ func TestFontSelect(t *testing.T) {
 }
 
 func TestShellSelect(t *testing.T) {
-	shells := []string{"fish", "zsh", "nushell"}
+	shells := []string{"fish", "zsh", "nushell", "bash"}
 
 	for i, shell := range shells {
 		t.Run(shell, func(t *testing.T) {
@@ -954,11 +967,11 @@ func TestBackupConfirmWithBackup(t *testing.T) {
 	if !newModel.Choices.CreateBackup {
 		t.Error("Should set CreateBackup true")
 	}
-	if newModel.Screen != ScreenInstalling {
-		t.Errorf("Expected ScreenInstalling, got %v", newModel.Screen)
+	if newModel.Screen != ScreenPreflight {
+		t.Errorf("Expected ScreenPreflight, got %v", newModel.Screen)
 	}
 	if cmd == nil {
-		t.Error("Should return installStartMsg command")
+		t.Error("Should return the pre-flight command")
 	}
 }
 
@@ -974,11 +987,11 @@ func TestBackupConfirmWithoutBackup(t *testing.T) {
 	if newModel.Choices.CreateBackup {
 		t.Error("Should set CreateBackup false")
 	}
-	if newModel.Screen != ScreenInstalling {
-		t.Errorf("Expected ScreenInstalling, got %v", newModel.Screen)
+	if newModel.Screen != ScreenPreflight {
+		t.Errorf("Expected ScreenPreflight, got %v", newModel.Screen)
 	}
 	if cmd == nil {
-		t.Error("Should return installStartMsg command")
+		t.Error("Should return the pre-flight command")
 	}
 }
 
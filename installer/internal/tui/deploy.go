@@ -0,0 +1,188 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// tempRepoDir is the throwaway clone used by the default copy mode
+const tempRepoDir = "Gentleman.Dots"
+
+// linkMode holds the stow-style deployment settings.
+// When enabled the repo is kept at repoDir and configs are symlinked into place.
+var linkMode struct {
+	enabled bool
+	repoDir string
+}
+
+// SetLinkMode enables or disables symlink deployment from a persistent clone
+func SetLinkMode(enabled bool, repoDir string) {
+	if repoDir == "" {
+		repoDir = system.DefaultLinkRepoDir()
+	}
+	if abs, err := filepath.Abs(repoDir); err == nil {
+		repoDir = abs
+	}
+	linkMode.enabled = enabled
+	linkMode.repoDir = repoDir
+}
+
+// LinkModeEnabled reports whether configs are deployed as symlinks
+func LinkModeEnabled() bool {
+	return linkMode.enabled
+}
+
+// LinkRepoDir returns the persistent clone location used in link mode
+func LinkRepoDir() string {
+	if linkMode.repoDir == "" {
+		return system.DefaultLinkRepoDir()
+	}
+	return linkMode.repoDir
+}
+
+// repoPath returns the directory holding the Gentleman.Dots sources
+func repoPath() string {
+	if linkMode.enabled {
+		return linkMode.repoDir
+	}
+	return tempRepoDir
+}
+
+// deployFile copies (or links, in link mode) a single config file into place
+func deployFile(src, dst string) error {
+	if linkMode.enabled {
+		return system.LinkFile(src, dst)
+	}
+	return system.CopyFile(src, dst)
+}
+
+// deployDir copies (or links, in link mode) a config directory into place
+func deployDir(src, dst string) error {
+	if linkMode.enabled {
+		return system.LinkTree(src, dst)
+	}
+	return system.CopyDir(src, dst)
+}
+
+// deployDirRendering deploys a config directory but keeps the named entry
+// files as local copies. It is used for formats that cannot include a
+// missing override file (KDL, Nushell), so the installer patches the local
+// copy while everything else stays linked to the repo.
+func deployDirRendering(src, dst string, rendered ...string) error {
+	if !linkMode.enabled {
+		return system.CopyDir(src, dst)
+	}
+
+	// A previous run may have linked the whole directory; unfold it.
+	if info, err := os.Lstat(dst); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(dst); err != nil {
+			return err
+		}
+	}
+	if err := system.EnsureDir(dst); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		from := filepath.Join(src, entry.Name())
+		to := filepath.Join(dst, entry.Name())
+		if isRenderedEntry(entry.Name(), rendered) {
+			// Replace a stale symlink so the copy never writes through to the repo
+			if info, err := os.Lstat(to); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(to); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+			if err := system.CopyFile(from, to); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := system.LinkTree(from, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localizeConfig turns a linked config file into a local copy before the
+// installer edits it, unfolding a linked parent directory if needed, so
+// the edit never writes through to the repository.
+func localizeConfig(path string) error {
+	dir := filepath.Dir(path)
+	if info, err := os.Lstat(dir); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		target, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(dir); err != nil {
+			return err
+		}
+		if err := system.EnsureDir(dir); err != nil {
+			return err
+		}
+		if err := system.LinkTree(target, dir); err != nil {
+			return err
+		}
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+func isRenderedEntry(name string, rendered []string) bool {
+	for _, r := range rendered {
+		if name == r {
+			return true
+		}
+	}
+	return false
+}
+
+// linkedEntryFiles maps deployed config paths (relative to HOME) to their
+// location inside the repository, for recognising a linked installation.
+var linkedEntryFiles = map[string]string{
+	".zshrc":                   "GentlemanZsh/.zshrc",
+	".bashrc":                  "GentlemanBash/.bashrc",
+	".config/fish/config.fish": "GentlemanFish/fish/config.fish",
+	".tmux.conf":               "GentlemanTmux/tmux.conf",
+}
+
+// detectLinkMode enables link mode when the installed configs are symlinks
+// into a Gentleman.Dots clone, so later patches go to override files
+// instead of writing through the links into the repository.
+func detectLinkMode() {
+	if linkMode.enabled {
+		return
+	}
+	homeDir := os.Getenv("HOME")
+	for rel, repoRel := range linkedEntryFiles {
+		path := filepath.Join(homeDir, rel)
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil || resolved == path {
+			continue
+		}
+		suffix := string(filepath.Separator) + filepath.FromSlash(repoRel)
+		if strings.HasSuffix(resolved, suffix) {
+			SetLinkMode(true, strings.TrimSuffix(resolved, suffix))
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+func withLinkMode(t *testing.T, repoDir string) {
+	t.Helper()
+	original := linkMode
+	SetLinkMode(true, repoDir)
+	t.Cleanup(func() { linkMode = original })
+}
+
+func TestRepoPathDefaultsToTemporaryClone(t *testing.T) {
+	if got := repoPath(); got != tempRepoDir {
+		t.Errorf("expected %q in copy mode, got %q", tempRepoDir, got)
+	}
+}
+
+func TestDeployDirRenderingKeepsEntryFileLocal(t *testing.T) {
+	tmp := t.TempDir()
+	repo := filepath.Join(tmp, "repo")
+	withLinkMode(t, repo)
+
+	src := filepath.Join(repo, "GentlemanZellij", "zellij")
+	dst := filepath.Join(tmp, "home", ".config", "zellij")
+	os.MkdirAll(filepath.Join(src, "layouts"), 0755)
+	os.WriteFile(filepath.Join(src, "config.kdl"), []byte("theme \"kanagawa\"\n"), 0644)
+
+	if err := deployDirRendering(src, dst, "config.kdl"); err != nil {
+		t.Fatalf("deployDirRendering failed: %v", err)
+	}
+
+	info, err := os.Lstat(filepath.Join(dst, "config.kdl"))
+	if err != nil {
+		t.Fatalf("config.kdl missing: %v", err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		t.Error("config.kdl must be a local copy so patches never touch the repo")
+	}
+	if !system.IsLinkedTo(filepath.Join(src, "layouts"), filepath.Join(dst, "layouts")) {
+		t.Error("layouts should stay linked to the repo")
+	}
+
+	// Patching the local copy leaves the repo original untouched
+	os.WriteFile(filepath.Join(dst, "config.kdl"), []byte("default_shell \"fish\"\n"), 0644)
+	data, _ := os.ReadFile(filepath.Join(src, "config.kdl"))
+	if string(data) != "theme \"kanagawa\"\n" {
+		t.Errorf("repo config.kdl was modified: %q", string(data))
+	}
+}
+
+func TestDeployConfigCommandLinksInLinkMode(t *testing.T) {
+	withLinkMode(t, "/opt/dots")
+	cmd := deployConfigCommand("/opt/dots/alacritty.toml", "/home/u/.config/alacritty/alacritty.toml")
+	if want := `ln -sfn '/opt/dots/alacritty.toml' '/home/u/.config/alacritty/alacritty.toml'`; !strings.Contains(cmd, want) {
+		t.Errorf("expected link command %q in:\n%s", want, cmd)
+	}
+}
+
+func TestDeployConfigCommands_QuoteForShell(t *testing.T) {
+	// Go's %q would leave $ and backticks to the shell
+	root := filepath.Join(t.TempDir(), "it's $HOME `x`")
+	src, dst := filepath.Join(root, "src"), filepath.Join(root, "dst")
+	os.MkdirAll(filepath.Join(src, "themes"), 0755)
+	os.WriteFile(filepath.Join(src, "config.toml"), []byte("ok\n"), 0644)
+	os.WriteFile(filepath.Join(src, "themes", "a.toml"), []byte("ok\n"), 0644)
+
+	script := deployConfigCommand(filepath.Join(src, "config.toml"), filepath.Join(dst, "file", "config.toml")) + "\n" +
+		deployConfigDirCommand(filepath.Join(src, "themes"), filepath.Join(dst, "dir"))
+	if out, err := exec.Command("sh", "-ec", script).CombinedOutput(); err != nil {
+		t.Fatalf("script failed: %v\n%s\n%s", err, out, script)
+	}
+	for _, path := range []string{filepath.Join(dst, "file", "config.toml"), filepath.Join(dst, "dir", "a.toml")} {
+		if !system.FileExists(path) {
+			t.Errorf("expected %s to be deployed", path)
+		}
+	}
+}
+
+func TestDeployConfigDirCommand_UnfoldsLikeLinkTree(t *testing.T) {
+	withLinkMode(t, "/opt/dots")
+	src, dst := filepath.Join(t.TempDir(), "nvim"), filepath.Join(t.TempDir(), "nvim")
+	os.MkdirAll(filepath.Join(src, "lua"), 0755)
+	os.WriteFile(filepath.Join(src, "lua", "init.lua"), []byte("ok\n"), 0644)
+	os.WriteFile(filepath.Join(src, ".neoconf.json"), []byte("{}\n"), 0644)
+	// A real directory where the repo has one, plus a file the repo does not know
+	os.MkdirAll(filepath.Join(dst, "lua"), 0755)
+	os.WriteFile(filepath.Join(dst, "lua", "local.lua"), []byte("mine\n"), 0644)
+
+	if out, err := exec.Command("sh", "-ec", deployConfigDirCommand(src, dst)).CombinedOutput(); err != nil {
+		t.Fatalf("script failed: %v\n%s", err, out)
+	}
+	for _, name := range []string{filepath.Join("lua", "init.lua"), ".neoconf.json"} {
+		if !system.IsLinkedTo(filepath.Join(src, name), filepath.Join(dst, name)) {
+			t.Errorf("expected %s to be linked into the existing directory", name)
+		}
+	}
+	if system.FileExists(filepath.Join(dst, "lua", "lua")) {
+		t.Error("the link must not be created inside the existing directory")
+	}
+	if !system.FileExists(filepath.Join(dst, "lua", "local.lua")) {
+		t.Error("files unknown to the repo must survive")
+	}
+}
+
+func TestCopyAction_LinksMissingDirectoryWhole(t *testing.T) {
+	withLinkMode(t, "/opt/dots")
+	src, dst := t.TempDir(), filepath.Join(t.TempDir(), ".config", "zellij")
+	os.WriteFile(filepath.Join(src, "config.kdl"), []byte("ok\n"), 0644)
+
+	if err := copyAction(stepAction{Src: src, Dst: dst, Tree: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !system.IsLinkedTo(src, dst) {
+		t.Errorf("expected %s to be a link to the repo directory", dst)
+	}
+}
@@ -0,0 +1,255 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// Estimate is the expected cost of installing one component
+type Estimate struct {
+	DownloadMB int
+	DiskMB     int
+	Duration   time.Duration
+}
+
+// Add returns the sum of two estimates
+func (e Estimate) Add(o Estimate) Estimate {
+	return Estimate{
+		DownloadMB: e.DownloadMB + o.DownloadMB,
+		DiskMB:     e.DiskMB + o.DiskMB,
+		Duration:   e.Duration + o.Duration,
+	}
+}
+
+// IsZero reports whether nothing is installed
+func (e Estimate) IsZero() bool {
+	return e == Estimate{}
+}
+
+// String formats the estimate for a single status line
+func (e Estimate) String() string {
+	return fmt.Sprintf("📦 ~%s download • 💾 ~%s on disk • ⏱️  ~%s",
+		formatMB(e.DownloadMB), formatMB(e.DiskMB), formatDuration(e.Duration))
+}
+
+func formatMB(mb int) string {
+	if mb >= 1024 {
+		return fmt.Sprintf("%.1f GB", float64(mb)/1024)
+	}
+	return fmt.Sprintf("%d MB", mb)
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%d s", int(d.Round(5*time.Second).Seconds()))
+	}
+	return fmt.Sprintf("%d min", int(d.Round(time.Minute).Minutes()))
+}
+
+// componentEstimates are typical costs on a broadband connection.
+// Keys are step IDs, with the chosen tool appended for terminal/shell/wm.
+var componentEstimates = map[string]Estimate{
+	"clone":              {DownloadMB: 40, DiskMB: 150, Duration: 20 * time.Second},
+	"xcode":              {DownloadMB: 700, DiskMB: 2500, Duration: 10 * time.Minute},
+	"homebrew":           {DownloadMB: 300, DiskMB: 600, Duration: 4 * time.Minute},
+	"deps":               {DownloadMB: 80, DiskMB: 250, Duration: 90 * time.Second},
+	"terminal:alacritty": {DownloadMB: 10, DiskMB: 30, Duration: 45 * time.Second},
+	"terminal:wezterm":   {DownloadMB: 60, DiskMB: 200, Duration: time.Minute},
+	"terminal:kitty":     {DownloadMB: 30, DiskMB: 80, Duration: 45 * time.Second},
+	"terminal:ghostty":   {DownloadMB: 40, DiskMB: 150, Duration: time.Minute},
+	"font":               {DownloadMB: 120, DiskMB: 250, Duration: 45 * time.Second},
+	"shell:fish":         {DownloadMB: 15, DiskMB: 60, Duration: 45 * time.Second},
+	"shell:zsh":          {DownloadMB: 30, DiskMB: 120, Duration: time.Minute},
+	"shell:nushell":      {DownloadMB: 30, DiskMB: 100, Duration: 45 * time.Second},
+	"shell:bash":         {DownloadMB: 5, DiskMB: 20, Duration: 20 * time.Second},
+	"wm:tmux":            {DownloadMB: 5, DiskMB: 20, Duration: 30 * time.Second},
+	"wm:zellij":          {DownloadMB: 15, DiskMB: 40, Duration: 30 * time.Second},
+	"wm:herdr":           {DownloadMB: 10, DiskMB: 20, Duration: 20 * time.Second},
+	"nvim":               {DownloadMB: 15, DiskMB: 60, Duration: 30 * time.Second},
+
+	// Extras pulled in by a choice on some platforms
+	"alacritty-source": {DownloadMB: 350, DiskMB: 1500, Duration: 8 * time.Minute}, // Rust toolchain + cargo build
+	"nvim-toolchain":   {DownloadMB: 200, DiskMB: 600, Duration: 3 * time.Minute},  // LSPs, treesitter parsers, plugins
+	"node":             {DownloadMB: 40, DiskMB: 200, Duration: 45 * time.Second},
+}
+
+// estimateKey maps an install step to its componentEstimates key
+func estimateKey(stepID string, choices UserChoices) string {
+	switch stepID {
+	case "terminal":
+		return "terminal:" + choices.Terminal
+	case "shell":
+		return "shell:" + choices.Shell
+	case "wm":
+		return "wm:" + choices.WindowMgr
+	}
+	return stepID
+}
+
+// estimateComponent returns the cost of one component on the detected platform,
+// preferring the duration recorded on previous runs
+func estimateComponent(key string, m *Model) Estimate {
+	if m.StepTimings == nil {
+		m.loadEstimateInputs()
+	}
+	est := componentEstimates[key]
+	info := m.SystemInfo
+	if info == nil {
+		info = &system.SystemInfo{}
+	}
+
+	switch key {
+	case "terminal:alacritty":
+		// Debian/Ubuntu builds Alacritty from source (see stepInstallTerminal)
+		if m.Choices.OS == "linux" && (info.OS == system.OSDebian || info.OS == system.OSLinux) {
+			est = est.Add(componentEstimates["alacritty-source"])
+		}
+	case "nvim":
+		est = est.Add(componentEstimates["nvim-toolchain"])
+		if !m.HasNode {
+			est = est.Add(componentEstimates["node"])
+		}
+	}
+
+	if d, ok := m.StepTimings[timingKey(info, key)]; ok {
+		est.Duration = time.Duration(d * float64(time.Second))
+	}
+	return est
+}
+
+// loadEstimateInputs reads what estimates need besides the choices: the
+// recorded timings and whether node is installed
+func (m *Model) loadEstimateInputs() {
+	m.StepTimings = loadStepTimings()
+	m.HasNode = system.CommandExists("node")
+}
+
+// estimateInstall totals every step the current choices would run
+func estimateInstall(m *Model) Estimate {
+	if m.SystemInfo == nil {
+		return Estimate{}
+	}
+	plan := *m
+	plan.SetupInstallSteps()
+	var total Estimate
+	for _, step := range plan.Steps {
+		total = total.Add(estimateComponent(estimateKey(step.ID, m.Choices), m))
+	}
+	return total
+}
+
+// selectionEstimate returns the cost of the option under the cursor and the
+// install total if it were chosen
+func (m Model) selectionEstimate() (option, total Estimate, ok bool) {
+	options := m.GetCurrentOptions()
+	if m.Cursor >= len(options) || m.SystemInfo == nil {
+		return Estimate{}, Estimate{}, false
+	}
+	label := strings.ToLower(strings.Split(options[m.Cursor], " ")[0])
+	plan := m
+
+	var key string
+	switch m.Screen {
+	case ScreenTerminalSelect:
+		if _, known := componentEstimates["terminal:"+label]; known {
+			key = "terminal:" + label
+			plan.Choices.Terminal = label
+		}
+	case ScreenFontSelect:
+		plan.Choices.InstallFont = m.Cursor != 1
+		if plan.Choices.InstallFont {
+			key = "font"
+		}
+	case ScreenShellSelect:
+		if _, known := componentEstimates["shell:"+label]; known {
+			key = "shell:" + label
+			plan.Choices.Shell = label
+		}
+	case ScreenWMSelect:
+		if _, known := componentEstimates["wm:"+label]; known {
+			key = "wm:" + label
+			plan.Choices.WindowMgr = label
+		}
+	case ScreenNvimSelect:
+		plan.Choices.InstallNvim = m.Cursor == 0
+		if plan.Choices.InstallNvim {
+			key = "nvim"
+		}
+	}
+	if key == "" {
+		return Estimate{}, Estimate{}, false
+	}
+	return estimateComponent(key, &plan), estimateInstall(&plan), true
+}
+
+// timingKey scopes a recorded duration to the platform it was measured on
+func timingKey(info *system.SystemInfo, key string) string {
+	platform := "linux"
+	switch {
+	case info.IsTermux:
+		platform = "termux"
+	case info.OS == system.OSMac:
+		platform = "mac"
+	case info.OS == system.OSArch:
+		platform = "arch"
+	case info.OS == system.OSDebian:
+		platform = "debian"
+	case info.OS == system.OSFedora:
+		platform = "fedora"
+	case info.OS == system.OSSUSE:
+		platform = "suse"
+	case info.OS == system.OSAlpine:
+		platform = "alpine"
+	case info.OS == system.OSVoid:
+		platform = "void"
+	}
+	return platform + "/" + key
+}
+
+// stepTimingsPath is where durations from previous runs are kept
+func stepTimingsPath() string {
+	return filepath.Join(system.CacheDir(), "timings.json")
+}
+
+// loadStepTimings reads recorded step durations in seconds
+func loadStepTimings() map[string]float64 {
+	timings := map[string]float64{}
+	data, err := os.ReadFile(stepTimingsPath())
+	if err != nil {
+		return timings
+	}
+	json.Unmarshal(data, &timings)
+	return timings
+}
+
+// recordStepTiming folds a finished step's duration into a decaying average:
+// each run counts half and older runs fade, so estimates follow this machine
+// and its current connection. Callers skip interactive steps: time spent at a
+// password prompt is not install time.
+func recordStepTiming(stepID string, m *Model, d time.Duration) {
+	key := estimateKey(stepID, m.Choices)
+	if _, known := componentEstimates[key]; !known || m.SystemInfo == nil {
+		return
+	}
+	timings := loadStepTimings()
+	tk := timingKey(m.SystemInfo, key)
+	secs := d.Seconds()
+	if prev, ok := timings[tk]; ok {
+		secs = (prev + secs) / 2 // halve the weight of every earlier run
+	}
+	timings[tk] = secs
+
+	path := stepTimingsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	if data, err := json.MarshalIndent(timings, "", "  "); err == nil {
+		os.WriteFile(path, data, 0644)
+	}
+}
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEstimateComponent_AlacrittySourceBuild(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSArch}, Choices: UserChoices{OS: "linux"}}
+	native := estimateComponent("terminal:alacritty", m)
+
+	m.SystemInfo.OS = system.OSDebian
+	source := estimateComponent("terminal:alacritty", m)
+	if source.DiskMB <= native.DiskMB || source.Duration <= native.Duration {
+		t.Errorf("expected the Debian source build to cost more: native %+v, source %+v", native, source)
+	}
+}
+
+func TestEstimateInstall_Total(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := &Model{
+		SystemInfo: &system.SystemInfo{OS: system.OSMac, HasBrew: true, HasXcode: true},
+		Choices:    UserChoices{OS: "mac", Terminal: "kitty", Shell: "fish", WindowMgr: "tmux"},
+	}
+	want := componentEstimates["clone"].
+		Add(componentEstimates["terminal:kitty"]).
+		Add(componentEstimates["shell:fish"]).
+		Add(componentEstimates["wm:tmux"])
+	if got := estimateInstall(m); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	// Without Homebrew the bootstrap is part of the total
+	m.SystemInfo.HasBrew = false
+	if got := estimateInstall(m); got.DownloadMB != want.DownloadMB+componentEstimates["homebrew"].DownloadMB {
+		t.Errorf("expected Homebrew bootstrap in total, got %+v", got)
+	}
+}
+
+func TestRecordStepTiming(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSFedora}, Choices: UserChoices{Shell: "zsh"}}
+
+	recordStepTiming("shell", m, 100*time.Second)
+	recordStepTiming("shell", m, 200*time.Second)
+	recordStepTiming("shell", m, 300*time.Second)
+	recordStepTiming("cleanup", m, time.Hour) // not estimated, ignored
+
+	// Decaying: (100+200)/2 = 150, then (150+300)/2 = 225
+	timings := loadStepTimings()
+	if got := timings["fedora/shell:zsh"]; got != 225 {
+		t.Errorf("expected the decaying average 225s, got %v", got)
+	}
+	if len(timings) != 1 {
+		t.Errorf("expected only the shell timing, got %v", timings)
+	}
+	if got := estimateComponent("shell:zsh", m).Duration; got != 225*time.Second {
+		t.Errorf("expected recorded duration to win, got %v", got)
+	}
+	// Timings don't leak across platforms
+	m.SystemInfo.OS = system.OSArch
+	if got := estimateComponent("shell:zsh", m).Duration; got != componentEstimates["shell:zsh"].Duration {
+		t.Errorf("expected table duration on another platform, got %v", got)
+	}
+}
+
+func TestSelectionEstimate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := NewModel()
+	m.SystemInfo = &system.SystemInfo{OS: system.OSMac, HasBrew: true, HasXcode: true}
+	m.Choices = UserChoices{OS: "mac"}
+	m.Screen = ScreenTerminalSelect
+	m.Cursor = 2 // Kitty
+
+	option, total, ok := m.selectionEstimate()
+	if !ok || option != componentEstimates["terminal:kitty"] {
+		t.Fatalf("expected kitty estimate, got %+v (ok=%v)", option, ok)
+	}
+	if total.DiskMB <= option.DiskMB {
+		t.Errorf("expected running total to include the clone, got %+v", total)
+	}
+	if view := m.renderSelection(); !strings.Contains(view, "Total so far") {
+		t.Errorf("expected estimate lines in view:\n%s", view)
+	}
+
+	m.Cursor = 4 // None
+	if _, _, ok := m.selectionEstimate(); ok {
+		t.Error("expected no estimate for None")
+	}
+}
+
+func TestEstimates_LoadedOnceAndSkipInteractiveSteps(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := NewModel()
+	m.SystemInfo = &system.SystemInfo{OS: system.OSFedora}
+	m.Choices = UserChoices{OS: "linux", Shell: "zsh"}
+	m.Screen = ScreenMainMenu
+	m.Cursor = 0 // Start Installation
+	result, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.StepTimings == nil {
+		t.Fatal("expected the timings to be loaded when the wizard starts")
+	}
+
+	// Rendering uses the loaded timings, not the file
+	recordStepTiming("shell", &m, 100*time.Second)
+	if got := estimateComponent("shell:zsh", &m).Duration; got != componentEstimates["shell:zsh"].Duration {
+		t.Errorf("expected the timings loaded on entry, got %v", got)
+	}
+
+	// An interactive step's duration includes the password prompt
+	m.Screen = ScreenInstalling
+	m.Steps = []InstallStep{{ID: "terminal", Interactive: true}, {ID: "cleanup"}}
+	m.Choices.Terminal = "kitty"
+	m.StepStarted = time.Now().Add(-time.Hour)
+	m.Update(execFinishedMsg{stepID: "terminal"})
+	if _, ok := loadStepTimings()["fedora/terminal:kitty"]; ok {
+		t.Error("an interactive step must not be recorded")
+	}
+}
+
+func TestEstimateString(t *testing.T) {
+	e := Estimate{DownloadMB: 350, DiskMB: 2048, Duration: 8*time.Minute + 20*time.Second}
+	if got := e.String(); !strings.Contains(got, "~350 MB download") || !strings.Contains(got, "~2.0 GB on disk") || !strings.Contains(got, "~8 min") {
+		t.Errorf("unexpected format: %s", got)
+	}
+}
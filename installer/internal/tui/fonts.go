@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// NerdFont describes a font offered by the installer
+type NerdFont struct {
+	ID      string // value of --nerd-font
+	Label   string // shown in the picker
+	Archive string // release asset name without .zip
+	Family  string // family name written into terminal configs
+	Cask    string // Homebrew cask on macOS
+	Nix     string // nixpkgs attribute
+	TTF     string // regular face used on Termux
+}
+
+// nerdFonts lists the fonts in the picker; the first is what the configs ship with
+var nerdFonts = []NerdFont{
+	{ID: "iosevka-term", Label: "Iosevka Term Nerd Font", Archive: "IosevkaTerm", Family: "IosevkaTerm NF",
+		Cask: "font-iosevka-term-nerd-font", Nix: "nerd-fonts.iosevka-term", TTF: "IosevkaTermNerdFont-Regular.ttf"},
+	{ID: "jetbrains-mono", Label: "JetBrainsMono Nerd Font", Archive: "JetBrainsMono", Family: "JetBrainsMono Nerd Font",
+		Cask: "font-jetbrains-mono-nerd-font", Nix: "nerd-fonts.jetbrains-mono", TTF: "JetBrainsMonoNerdFont-Regular.ttf"},
+	{ID: "fira-code", Label: "FiraCode Nerd Font", Archive: "FiraCode", Family: "FiraCode Nerd Font",
+		Cask: "font-fira-code-nerd-font", Nix: "nerd-fonts.fira-code", TTF: "FiraCodeNerdFont-Regular.ttf"},
+	{ID: "caskaydia-cove", Label: "CaskaydiaCove Nerd Font", Archive: "CascadiaCode", Family: "CaskaydiaCove Nerd Font",
+		Cask: "font-caskaydia-cove-nerd-font", Nix: "nerd-fonts.caskaydia-cove", TTF: "CaskaydiaCoveNerdFont-Regular.ttf"},
+	{ID: "hack", Label: "Hack Nerd Font", Archive: "Hack", Family: "Hack Nerd Font",
+		Cask: "font-hack-nerd-font", Nix: "nerd-fonts.hack", TTF: "HackNerdFont-Regular.ttf"},
+}
+
+// nerdFontVersions are the nerd-fonts releases offered; the first is the default.
+var nerdFontVersions = []string{"v3.3.0", "v3.4.0", "v3.2.1"}
+
+// nerdFontPinned are the archive digests shipped with the installer, keyed by
+// release and asset. Add the SHA-256.txt entries of every nerdFonts archive
+// when offering a release; unpinned archives need --allow-unpinned.
+var nerdFontPinned = map[string]system.Release{}
+
+// ValidNerdFonts lists the accepted --nerd-font values
+var ValidNerdFonts = nerdFontIDs()
+
+func nerdFontIDs() []string {
+	ids := make([]string, len(nerdFonts))
+	for i, f := range nerdFonts {
+		ids[i] = f.ID
+	}
+	return ids
+}
+
+// lookupNerdFont finds a font by ID or picker label
+func lookupNerdFont(key string) (NerdFont, bool) {
+	for _, f := range nerdFonts {
+		if f.ID == key || strings.EqualFold(f.Label, key) {
+			return f, true
+		}
+	}
+	return NerdFont{}, false
+}
+
+// selectedFont is the font to install; Termux defaults to JetBrainsMono,
+// which renders better as the single font Termux supports
+func selectedFont(choices UserChoices) NerdFont {
+	if f, ok := lookupNerdFont(choices.Font); ok {
+		return f
+	}
+	if choices.OS == "termux" {
+		f, _ := lookupNerdFont("jetbrains-mono")
+		return f
+	}
+	return nerdFonts[0]
+}
+
+// FontDescription names the font and release the choices will install
+func FontDescription(choices UserChoices) string {
+	return fmt.Sprintf("%s (%s)", selectedFont(choices).Label, selectedFontVersion(choices))
+}
+
+// selectedFontVersion is the nerd-fonts release to download
+func selectedFontVersion(choices UserChoices) string {
+	if choices.FontVersion != "" {
+		return choices.FontVersion
+	}
+	return nerdFontVersions[0]
+}
+
+// ValidFontVersion reports whether v is an offered nerd-fonts release
+func ValidFontVersion(v string) bool {
+	for _, known := range nerdFontVersions {
+		if v == known {
+			return true
+		}
+	}
+	return false
+}
+
+// cycleFontVersion moves the version selector by delta, wrapping around
+func cycleFontVersion(current string, delta int) string {
+	idx := 0
+	for i, v := range nerdFontVersions {
+		if v == current {
+			idx = i
+		}
+	}
+	n := len(nerdFontVersions)
+	return nerdFontVersions[((idx+delta)%n+n)%n]
+}
+
+// fontPickerOptions builds the font screen: the default keeps its yes/no
+// positions, alternatives follow a separator
+func fontPickerOptions() []string {
+	options := []string{"Yes, install " + nerdFonts[0].Label, "No, I already have it", "─────────────"}
+	for _, f := range nerdFonts[1:] {
+		options = append(options, f.Label)
+	}
+	return options
+}
+
+// terminalConfigPath returns the deployed config file holding the font setting
+func terminalConfigPath(terminal string) string {
+	home := os.Getenv("HOME")
+	switch terminal {
+	case "alacritty":
+		return filepath.Join(home, ".config", "alacritty", "alacritty.toml")
+	case "wezterm":
+		return filepath.Join(home, ".config", "wezterm", "wezterm.lua")
+	case "kitty":
+		return filepath.Join(home, ".config", "kitty", "kitty.conf")
+	case "ghostty":
+		return filepath.Join(home, ".config", "ghostty", "config")
+	}
+	return ""
+}
+
+// applyTerminalFont points the chosen terminal's config at the installed font
+func applyTerminalFont(stepID string, choices UserChoices) error {
+	font := selectedFont(choices)
+	path := terminalConfigPath(choices.Terminal)
+	if path == "" || font.Family == nerdFonts[0].Family || !system.FileExists(path) {
+		return nil // the shipped configs already use the default font
+	}
+	SendLog(stepID, fmt.Sprintf("Setting %s font to %s...", choices.Terminal, font.Family))
+	if err := localizeConfig(path); err != nil {
+		return err
+	}
+	return system.SetTerminalFont(choices.Terminal, path, font.Family)
+}
+
+// fontFaces are the styles terminals use; other weights in the archive are skipped
+var fontFaces = []string{"Regular", "Bold", "Italic", "BoldItalic"}
+
+// fontVersionFile records the nerd-fonts release installed in a font directory
+const fontVersionFile = ".nerd-fonts-version"
+
+// fontStem is the file name prefix of a font's faces, e.g. "HackNerdFont"
+func fontStem(font NerdFont) string {
+	return strings.TrimSuffix(font.TTF, "-Regular.ttf")
+}
+
+// fontInstallDir is the per-font directory under ~/.local/share/fonts, so a
+// font can be upgraded or removed without touching anything else
+func fontInstallDir(font NerdFont) string {
+	return filepath.Join(os.Getenv("HOME"), ".local", "share", "fonts", "nerd-fonts", font.ID)
+}
+
+// extractFontFaces replaces dir with the font's faces from archive.
+// Faces the family does not ship are skipped; Regular is required.
+func extractFontFaces(archive string, font NerdFont, dir string) ([]string, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	var installed []string
+	for _, face := range fontFaces {
+		name := fontStem(font) + "-" + face + ".ttf"
+		err := system.ExtractZipFile(archive, name, filepath.Join(dir, name))
+		if errors.Is(err, system.ErrNotInArchive) && face != "Regular" {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		installed = append(installed, name)
+	}
+	return installed, nil
+}
+
+// removeLegacyFontFiles deletes what older installers unzipped straight into
+// the fonts directory, so the family is not registered twice
+func removeLegacyFontFiles(stepID, fontDir string, font NerdFont) {
+	legacy, _ := filepath.Glob(filepath.Join(fontDir, fontStem(font)+"*.ttf"))
+	legacy = append(legacy, filepath.Join(fontDir, font.Archive+".zip"))
+	removed := 0
+	for _, path := range legacy {
+		if os.Remove(path) == nil {
+			removed++
+		}
+	}
+	if removed > 0 {
+		SendLog(stepID, fmt.Sprintf("Removed %d files left by a previous font install", removed))
+	}
+}
+
+// fontRegistered reports whether `fc-list : family file` output lists a
+// face from dir under the font's family
+func fontRegistered(fcList, dir string, font NerdFont) bool {
+	longName := strings.TrimSuffix(fontStem(font), "NerdFont") + " Nerd Font"
+	for _, line := range strings.Split(fcList, "\n") {
+		file, families, ok := strings.Cut(line, ": ")
+		if !ok || !strings.HasPrefix(file, dir+string(filepath.Separator)) {
+			continue
+		}
+		for _, family := range strings.Split(families, ",") {
+			family = strings.TrimSpace(family)
+			if strings.EqualFold(family, font.Family) || strings.EqualFold(family, longName) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFontPicker(t *testing.T) {
+	m := NewModel()
+	m.Screen = ScreenFontSelect
+	m.Choices.OS = "linux"
+
+	// ←/→ cycles the nerd-fonts release
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m = result.(Model)
+	if m.Choices.FontVersion != nerdFontVersions[1] {
+		t.Errorf("expected %s after →, got %q", nerdFontVersions[1], m.Choices.FontVersion)
+	}
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = result.(Model)
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = result.(Model)
+	if m.Choices.FontVersion != nerdFontVersions[len(nerdFontVersions)-1] {
+		t.Errorf("expected the selector to wrap, got %q", m.Choices.FontVersion)
+	}
+
+	options := m.GetCurrentOptions()
+	for i, opt := range options {
+		if opt == "FiraCode Nerd Font" {
+			m.Cursor = i
+		}
+	}
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if !m.Choices.InstallFont || m.Choices.Font != "fira-code" || m.Screen != ScreenShellSelect {
+		t.Errorf("expected FiraCode to be chosen, got %+v on screen %v", m.Choices, m.Screen)
+	}
+
+	m.SystemInfo = &system.SystemInfo{OS: system.OSArch}
+	m.Choices.Shell = "fish"
+	m.SetupInstallSteps()
+	for _, s := range m.Steps {
+		if s.ID == "font" && s.Name != "Install FiraCode Nerd Font" {
+			t.Errorf("unexpected font step name %q", s.Name)
+		}
+	}
+}
+
+func TestSelectedFont_Defaults(t *testing.T) {
+	if f := selectedFont(UserChoices{OS: "linux"}); f.ID != "iosevka-term" {
+		t.Errorf("expected Iosevka Term by default, got %s", f.ID)
+	}
+	if f := selectedFont(UserChoices{OS: "termux"}); f.TTF != "JetBrainsMonoNerdFont-Regular.ttf" {
+		t.Errorf("expected JetBrainsMono on Termux, got %s", f.ID)
+	}
+	// Choosing the Iosevka Term option installs it, on Termux too
+	m := NewModel()
+	m.Screen = ScreenFontSelect
+	m.Choices.OS = "termux"
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if f := selectedFont(result.(Model).Choices); f.ID != "iosevka-term" {
+		t.Errorf("expected the chosen Iosevka Term on Termux, got %s", f.ID)
+	}
+	a := nerdFontArtifact("v3.4.0", selectedFont(UserChoices{Font: "caskaydia-cove"}).Archive)
+	if !strings.HasSuffix(a.URL, "/v3.4.0/CascadiaCode.zip") || !strings.HasSuffix(a.ChecksumManifest, "/v3.4.0/SHA-256.txt") {
+		t.Errorf("unexpected artifact %+v", a)
+	}
+
+	sum := strings.Repeat("ab", 32)
+	t.Cleanup(func() { delete(nerdFontPinned, "v9.9.9") })
+	nerdFontPinned["v9.9.9"] = system.Release{Tag: "v9.9.9", Assets: map[string]string{"Hack.zip": sum}}
+	if a := nerdFontArtifact("v9.9.9", "Hack"); a.SHA256 != sum || a.ChecksumManifest != "" {
+		t.Errorf("expected the pinned digest for Hack, got %+v", a)
+	}
+	if a := nerdFontArtifact("v9.9.9", "FiraCode"); a.SHA256 != "" {
+		t.Errorf("expected no pin for FiraCode, got %+v", a)
+	}
+}
+
+func TestApplyTerminalFont_LinkedConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := filepath.Join(t.TempDir(), "GentlemanGhostty")
+	os.MkdirAll(repo, 0755)
+	shipped := "font-family = IosevkaTerm NF\nfont-size = 14\n"
+	os.WriteFile(filepath.Join(repo, "config"), []byte(shipped), 0644)
+	os.MkdirAll(filepath.Join(home, ".config"), 0755)
+	os.Symlink(repo, filepath.Join(home, ".config", "ghostty"))
+
+	if err := applyTerminalFont("font", UserChoices{Terminal: "ghostty", Font: "hack"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(repo, "config")); string(got) != shipped {
+		t.Errorf("repository config was modified:\n%s", got)
+	}
+	got, _ := os.ReadFile(filepath.Join(home, ".config", "ghostty", "config"))
+	if !strings.Contains(string(got), "font-family = Hack Nerd Font") {
+		t.Errorf("expected the local config to use Hack, got:\n%s", got)
+	}
+}
+
+func TestExtractFontFaces(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "FiraCode.zip")
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"FiraCodeNerdFont-Regular.ttf", "FiraCodeNerdFont-Bold.ttf", "FiraCodeNerdFont-Light.ttf", "FiraCodeNerdFontMono-Regular.ttf", "LICENSE", "README.md"} {
+		w, _ := zw.Create(name)
+		w.Write([]byte(name))
+	}
+	zw.Close()
+	os.WriteFile(archive, buf.Bytes(), 0644)
+
+	font, _ := lookupNerdFont("fira-code")
+	target := filepath.Join(dir, "fonts", "fira-code")
+	os.MkdirAll(target, 0755)
+	os.WriteFile(filepath.Join(target, "stale.ttf"), nil, 0644)
+
+	installed, err := extractFontFaces(archive, font, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(installed, ",") != "FiraCodeNerdFont-Regular.ttf,FiraCodeNerdFont-Bold.ttf" {
+		t.Errorf("expected only Regular and Bold, got %v", installed)
+	}
+	entries, _ := os.ReadDir(target)
+	if len(entries) != 2 {
+		t.Errorf("expected the directory to hold just the extracted faces, got %v", entries)
+	}
+
+	hack, _ := lookupNerdFont("hack")
+	if _, err := extractFontFaces(archive, hack, filepath.Join(dir, "hack")); err == nil {
+		t.Error("expected an error when the Regular face is missing")
+	}
+}
+
+func TestRemoveLegacyFontFiles(t *testing.T) {
+	fontDir := t.TempDir()
+	font := nerdFonts[0]
+	for _, name := range []string{"IosevkaTerm.zip", "IosevkaTermNerdFont-Regular.ttf", "IosevkaTermNerdFontMono-Bold.ttf", "OtherFont-Regular.ttf"} {
+		os.WriteFile(filepath.Join(fontDir, name), nil, 0644)
+	}
+	removeLegacyFontFiles("font", fontDir, font)
+
+	entries, _ := os.ReadDir(fontDir)
+	if len(entries) != 1 || entries[0].Name() != "OtherFont-Regular.ttf" {
+		t.Errorf("expected only unrelated fonts to remain, got %v", entries)
+	}
+}
+
+func TestFontRegistered(t *testing.T) {
+	font := nerdFonts[0]
+	dir := "/home/u/.local/share/fonts/nerd-fonts/iosevka-term"
+	out := "/usr/share/fonts/DejaVuSans.ttf: DejaVu Sans\n" +
+		dir + "/IosevkaTermNerdFont-Regular.ttf: IosevkaTerm Nerd Font,IosevkaTerm NF\n"
+	if !fontRegistered(out, dir, font) {
+		t.Error("expected the installed face to be found")
+	}
+	if fontRegistered("/usr/share/fonts/IosevkaTermNerdFont-Regular.ttf: IosevkaTerm NF\n", dir, font) {
+		t.Error("a copy outside the install directory should not count")
+	}
+	hack, _ := lookupNerdFont("hack")
+	if fontRegistered(out, dir, hack) {
+		t.Error("expected a family mismatch to fail")
+	}
+}
@@ -0,0 +1,393 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// herdrVersion is the release installed unless --herdr-version asks for another
+const herdrVersion = "0.7.1"
+
+// Herdr publishes its binaries as GitHub release assets; the release API is
+// the manifest listing every asset with its SHA256 digest
+const herdrReleases = "https://github.com/ogulcancelik/herdr/releases/download/"
+
+var herdrReleaseAPI = "https://api.github.com/repos/ogulcancelik/herdr/releases/"
+
+// herdrPinned are manifests shipped with the installer, so the default
+// release installs without asking the GitHub API
+var herdrPinned = map[string]system.Release{
+	"v0.7.1": {Tag: "v0.7.1", Assets: map[string]string{
+		"herdr-linux-x86_64":  "b965acaffc2c22f54b6e6c64af7cf8e98a3f4ac2622630a0599c67a4b9d8a654",
+		"herdr-linux-aarch64": "3d757ac30c631e79dc45038c3ecc6423fe13a89f9cffa0f415aedd2c27f1576c",
+	}},
+}
+
+var herdrVersionRe = regexp.MustCompile(`^v?\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?$`)
+
+var herdrRequest struct {
+	version string // "latest", a version such as 0.7.1, or "" for herdrVersion
+}
+
+// SetHerdrVersion selects the Herdr release to install: "latest" or a version such as 0.7.1
+func SetHerdrVersion(v string) error {
+	if v != "" && v != "latest" && !herdrVersionRe.MatchString(v) {
+		return fmt.Errorf("invalid herdr version: %s (use latest or a release such as %s)", v, herdrVersion)
+	}
+	herdrRequest.version = strings.TrimPrefix(v, "v")
+	return nil
+}
+
+// herdrTarget is the requested release, "latest" or a version without the v
+func herdrTarget() string {
+	if herdrRequest.version == "" {
+		return herdrVersion
+	}
+	return herdrRequest.version
+}
+
+// herdrBinPath is where release binaries and source builds are installed
+func herdrBinPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".local", "bin", "herdr")
+}
+
+// herdrAsset is the release binary for a Go architecture, "" when none is published
+func herdrAsset(goarch string) string {
+	switch goarch {
+	case "amd64":
+		return "herdr-linux-x86_64"
+	case "arm64":
+		return "herdr-linux-aarch64"
+	}
+	return ""
+}
+
+// resolveHerdrRelease reads the manifest of a release ("latest" or a version)
+func resolveHerdrRelease(version string) (system.Release, error) {
+	if release, ok := herdrPinned["v"+version]; ok {
+		return release, nil
+	}
+	api := herdrReleaseAPI + "tags/v" + version
+	if version == "latest" {
+		api = herdrReleaseAPI + "latest"
+	}
+	return system.FetchRelease(api)
+}
+
+// herdrReleaseUnpinned reports whether the plan downloads a Herdr release
+// binary with no digest in herdrPinned; "latest" can move, so it never counts as pinned
+func herdrReleaseUnpinned(info *system.SystemInfo) bool {
+	version := herdrRequest.version
+	if version == "" {
+		return false
+	}
+	useBrew := !info.IsTermux && (info.OS == system.OSMac || info.HasBrew)
+	if useBrew && version == "latest" || herdrSourceReason(info, runtime.GOARCH) != "" {
+		return false
+	}
+	_, ok := herdrPinned["v"+version]
+	return !ok
+}
+
+// herdrArtifact is the verified download of asset from release
+func herdrArtifact(release system.Release, asset string) (system.Artifact, error) {
+	sum, ok := release.Assets[asset]
+	if !ok {
+		return system.Artifact{}, fmt.Errorf("herdr %s publishes no %s binary", release.Tag, asset)
+	}
+	if sum == "" {
+		return system.Artifact{}, fmt.Errorf("herdr %s lists no checksum for %s: %w", release.Tag, asset, system.ErrUnpinned)
+	}
+	return system.Artifact{URL: herdrReleases + release.Tag + "/" + asset, SHA256: sum}, nil
+}
+
+// herdrSourceReason says why Herdr has to be built with cargo, "" when a
+// release binary runs here
+func herdrSourceReason(info *system.SystemInfo, goarch string) string {
+	switch {
+	case info.IsTermux:
+		return "no release binary for Termux"
+	case info.OS == system.OSMac:
+		return "Homebrew only installs the latest release"
+	case info.Libc == "musl":
+		return "release binaries need glibc"
+	case herdrAsset(goarch) == "":
+		return "no release binary for " + goarch
+	}
+	return ""
+}
+
+var herdrVersionOutputRe = regexp.MustCompile(`\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?`)
+
+// installedHerdrVersion is the version the binary at path reports, "" when unknown
+func installedHerdrVersion(path string) string {
+	if !system.FileExists(path) {
+		return ""
+	}
+	result := system.Run(shellQuote(path)+" --version", nil)
+	if result.Error != nil {
+		return ""
+	}
+	return herdrVersionOutputRe.FindString(result.Output)
+}
+
+func installHerdrBinary(m *Model, stepID string) error {
+	version := herdrRequest.version
+	if system.CommandExists("herdr") && version == "" {
+		SendLog(stepID, "Herdr already installed")
+		return nil
+	}
+
+	// Homebrew always has the latest release
+	useBrew := !m.SystemInfo.IsTermux && (m.SystemInfo.OS == system.OSMac || m.SystemInfo.HasBrew)
+	if useBrew && (version == "" || version == "latest") && (!system.CommandExists("herdr") || herdrFromBrew()) {
+		verb := "install"
+		if system.CommandExists("herdr") {
+			verb = "upgrade"
+		}
+		result := runBrewWithLogs(verb+" herdr", nil, func(line string) {
+			SendLog(stepID, line)
+		})
+		return result.Error
+	}
+
+	if reason := herdrSourceReason(m.SystemInfo, runtime.GOARCH); reason != "" {
+		SendLog(stepID, fmt.Sprintf("Building Herdr from source (%s)...", reason))
+		return installHerdrFromSource(m, stepID, herdrTarget())
+	}
+	return installHerdrRelease(stepID, herdrTarget())
+}
+
+// herdrFromBrew reports whether Homebrew owns the installed herdr
+func herdrFromBrew() bool {
+	result := system.Run("brew list --versions herdr", nil)
+	return result.Error == nil && strings.TrimSpace(result.Output) != ""
+}
+
+// installHerdrRelease downloads a release binary, verifies it against the
+// release manifest and swaps it into ~/.local/bin
+func installHerdrRelease(stepID, version string) error {
+	release, err := resolveHerdrRelease(version)
+	if err != nil {
+		return err
+	}
+	if pinned, ok := herdrPinned[release.Tag]; ok {
+		release = pinned
+	} else if !system.UnpinnedAllowed() {
+		return fmt.Errorf("herdr %s: %w", release.Tag, system.ErrUnpinnedRefused)
+	} else {
+		SendLog(stepID, fmt.Sprintf("⚠️  Herdr %s is not pinned in the installer; trusting the digest its GitHub release lists (--allow-unpinned)", release.Tag))
+	}
+	dest := herdrBinPath()
+	if installed := installedHerdrVersion(dest); installed != "" && installed == strings.TrimPrefix(release.Tag, "v") {
+		SendLog(stepID, fmt.Sprintf("Herdr %s already installed", installed))
+		return nil
+	}
+	artifact, err := herdrArtifact(release, herdrAsset(runtime.GOARCH))
+	if err != nil {
+		return err
+	}
+
+	staging, err := os.MkdirTemp("", "herdr-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+	binary := filepath.Join(staging, "herdr")
+	SendLog(stepID, fmt.Sprintf("Downloading Herdr %s release binary...", release.Tag))
+	if err := downloadArtifact(stepID, artifact, binary); err != nil {
+		return err
+	}
+	return replaceHerdr(stepID, binary, dest)
+}
+
+// installHerdrFromSource builds a release with `cargo install` into a
+// staging root and swaps the binary into ~/.local/bin
+func installHerdrFromSource(m *Model, stepID, version string) error {
+	if version == "latest" {
+		// Name the release so an up-to-date install is not rebuilt
+		release, err := resolveHerdrRelease("latest")
+		if err != nil {
+			return fmt.Errorf("resolving the latest herdr release: %w", err)
+		}
+		version = strings.TrimPrefix(release.Tag, "v")
+	}
+	dest := herdrBinPath()
+	if installed := installedHerdrVersion(dest); installed != "" && installed == version {
+		SendLog(stepID, fmt.Sprintf("Herdr %s already installed", installed))
+		return nil
+	}
+	onLog := func(line string) { SendLog(stepID, line) }
+	if !system.CommandExists("cargo") {
+		SendLog(stepID, "Installing the Rust toolchain...")
+		result := installPlatformPackages(m, stepID, platformPackages{
+			Termux: "rust",
+			Brew:   "rust",
+			Arch:   "rust",
+			Fedora: "cargo",
+			Debian: "cargo",
+			SUSE:   "cargo",
+			Alpine: "cargo",
+			Void:   "cargo",
+		}, onLog)
+		if result.Error != nil {
+			return fmt.Errorf("cargo is needed to build herdr: %w", result.Error)
+		}
+	}
+
+	staging, err := os.MkdirTemp("", "herdr-build-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+	command := "cargo install herdr --locked --root " + shellQuote(staging) + " --version " + version
+	if result := system.RunWithLogs(command, nil, onLog); result.Error != nil {
+		return fmt.Errorf("building herdr failed (an older distro cargo may be too old; try rustup): %w", result.Error)
+	}
+	return replaceHerdr(stepID, filepath.Join(staging, "bin", "herdr"), dest)
+}
+
+// replaceHerdr checks that a new binary runs before moving it over dest
+func replaceHerdr(stepID, binary, dest string) error {
+	if err := os.Chmod(binary, 0755); err != nil {
+		return err
+	}
+	result := system.Run(shellQuote(binary)+" --version", nil)
+	if result.Error != nil {
+		return fmt.Errorf("the new herdr binary does not run here: %w", result.Error)
+	}
+	if err := system.ReplaceBinary(binary, dest); err != nil {
+		return err
+	}
+	SendLog(stepID, fmt.Sprintf("✓ Herdr %s installed in %s", herdrVersionOutputRe.FindString(result.Output), filepath.Dir(dest)))
+	removeShadowingHerdr(stepID, dest)
+	return nil
+}
+
+// shadowingHerdr is the herdr found on PATH instead of dest, "" when dest wins
+func shadowingHerdr(dest string) string {
+	found, err := exec.LookPath("herdr")
+	if err != nil {
+		return ""
+	}
+	a, errA := os.Stat(found)
+	b, errB := os.Stat(dest)
+	if errA == nil && errB == nil && os.SameFile(a, b) {
+		return ""
+	}
+	return found
+}
+
+// removeShadowingHerdr uninstalls Homebrew's herdr when it comes before dest
+// on PATH, which would keep running the Homebrew release; any other herdr
+// found first is reported
+func removeShadowingHerdr(stepID, dest string) {
+	found := shadowingHerdr(dest)
+	if found == "" {
+		return
+	}
+	if strings.HasPrefix(found, system.GetBrewPrefix()+"/") && herdrFromBrew() {
+		SendLog(stepID, fmt.Sprintf("Removing Homebrew's herdr, which comes before %s on PATH...", filepath.Dir(dest)))
+		result := runBrewWithLogs("uninstall herdr", nil, func(line string) {
+			SendLog(stepID, line)
+		})
+		if found = shadowingHerdr(dest); result.Error == nil && found == "" {
+			return
+		}
+	}
+	SendLog(stepID, fmt.Sprintf("⚠️  %s comes before %s on PATH; remove it or put %s first to use this release",
+		found, filepath.Dir(dest), filepath.Dir(dest)))
+}
+
+// stepUpdateHerdr installs the release SetHerdrVersion selected over the current Herdr
+func stepUpdateHerdr(m *Model) error {
+	stepID := "herdr"
+	SendLog(stepID, fmt.Sprintf("Updating Herdr to %s...", herdrTarget()))
+	if err := installHerdrBinary(m, stepID); err != nil {
+		return wrapStepError(stepID, "Update Herdr",
+			"Failed to update Herdr",
+			err)
+	}
+	return nil
+}
+
+// RunHerdrUpdate replaces the installed Herdr with the release SetHerdrVersion selected, without TUI
+func RunHerdrUpdate() error {
+	SetNonInteractiveMode(true)
+
+	model := &Model{
+		SystemInfo: system.Detect(),
+		LogLines:   []string{},
+	}
+	model.Steps = []InstallStep{{
+		ID:          "herdr",
+		Name:        "Update Herdr",
+		Description: "Installing the selected release",
+		Status:      StatusPending,
+	}}
+	return runStepsNonInteractive(model, model.Steps)
+}
+
+// herdrKeySettingNames returns the `set herdr.<key>` settings
+func herdrKeySettingNames() []string {
+	names := make([]string, 0, len(system.HerdrBindings))
+	for _, b := range system.HerdrBindings {
+		names = append(names, "herdr."+b.Key)
+	}
+	return names
+}
+
+// currentHerdrKeys reads the bindings of the deployed Herdr config
+func currentHerdrKeys() (map[string]string, error) {
+	path := wmConfigPath("herdr")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no Herdr config at %s; install Herdr first", path)
+	}
+	return system.HerdrKeys(string(data)), nil
+}
+
+// cycleHerdrKey moves a binding through its presets; a custom value from
+// config.toml stays reachable as the first choice
+func cycleHerdrKey(b system.HerdrBinding, base, value string, steps int) string {
+	choices := b.Choices
+	if !slices.Contains(choices, base) {
+		choices = append([]string{base}, choices...)
+	}
+	i := max(slices.Index(choices, value), 0)
+	return choices[((i+steps)%len(choices)+len(choices))%len(choices)]
+}
+
+// stepApplyHerdrKeys writes m.Choices.HerdrKeys into the [keys] table of herdr's config.toml
+func stepApplyHerdrKeys(m *Model) error {
+	stepID := "herdrkeys"
+	path := wmConfigPath("herdr")
+	if !system.FileExists(path) {
+		return wrapStepError(stepID, "Herdr Keys",
+			"Herdr config not found",
+			fmt.Errorf("%s does not exist; install Herdr first", path))
+	}
+
+	if err := localizeConfig(path); err != nil {
+		return wrapStepError(stepID, "Herdr Keys", "Failed to prepare the Herdr config", err)
+	}
+	for _, b := range system.HerdrBindings {
+		if value, ok := m.Choices.HerdrKeys[b.Key]; ok {
+			SendLog(stepID, fmt.Sprintf("Setting %s to %s...", b.Key, value))
+		}
+	}
+	if err := system.SetHerdrKeys(path, m.Choices.HerdrKeys); err != nil {
+		return wrapStepError(stepID, "Herdr Keys", "Failed to write the Herdr bindings", err)
+	}
+
+	SendLog(stepID, "✓ Herdr bindings updated")
+	return nil
+}
@@ -0,0 +1,283 @@
+package tui
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSetHerdrVersion(t *testing.T) {
+	t.Cleanup(func() { SetHerdrVersion("") })
+
+	for _, v := range []string{"latest", "0.8.0", "v0.8.0", "1.0.0-rc.1"} {
+		if err := SetHerdrVersion(v); err != nil {
+			t.Errorf("%s: %v", v, err)
+		}
+	}
+	if herdrTarget() != "1.0.0-rc.1" {
+		t.Errorf("unexpected target %q", herdrTarget())
+	}
+	SetHerdrVersion("v0.8.0")
+	if herdrTarget() != "0.8.0" {
+		t.Errorf("the v prefix must be dropped, got %q", herdrTarget())
+	}
+	for _, v := range []string{"0.8", "main", "0.8.0; rm -rf ~"} {
+		if err := SetHerdrVersion(v); err == nil {
+			t.Errorf("%s: expected an error", v)
+		}
+	}
+	SetHerdrVersion("")
+	if herdrTarget() != herdrVersion {
+		t.Errorf("expected the default release, got %q", herdrTarget())
+	}
+}
+
+func TestHerdrArtifact(t *testing.T) {
+	release, err := resolveHerdrRelease(herdrVersion)
+	if err != nil {
+		t.Fatalf("the default release must be pinned: %v", err)
+	}
+	for _, goarch := range []string{"amd64", "arm64"} {
+		a, err := herdrArtifact(release, herdrAsset(goarch))
+		if err != nil {
+			t.Fatalf("%s: %v", goarch, err)
+		}
+		if a.URL != herdrReleases+"v"+herdrVersion+"/"+herdrAsset(goarch) || len(a.SHA256) != 64 {
+			t.Errorf("%s: unexpected artifact %+v", goarch, a)
+		}
+	}
+
+	unsigned := system.Release{Tag: "v0.9.0", Assets: map[string]string{"herdr-linux-x86_64": ""}}
+	if _, err := herdrArtifact(unsigned, "herdr-linux-x86_64"); !errors.Is(err, system.ErrUnpinned) {
+		t.Errorf("expected ErrUnpinned for an asset without a digest, got %v", err)
+	}
+	if _, err := herdrArtifact(unsigned, "herdr-linux-aarch64"); err == nil {
+		t.Error("expected an error for a missing asset")
+	}
+}
+
+func TestInstallHerdrRelease_Unpinned(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "v0.9.0", "assets": [{"name": "herdr-linux-x86_64", "digest": "sha256:` + strings.Repeat("ab", 32) + `"}]}`))
+	}))
+	defer api.Close()
+	original := herdrReleaseAPI
+	t.Cleanup(func() { herdrReleaseAPI = original })
+	herdrReleaseAPI = api.URL + "/releases/"
+
+	if err := installHerdrRelease("herdr", "0.9.0"); !errors.Is(err, system.ErrUnpinnedRefused) {
+		t.Errorf("expected an unpinned release to be refused, got %v", err)
+	}
+	if system.FileExists(herdrBinPath()) {
+		t.Error("a refused release must not be installed")
+	}
+
+	t.Cleanup(func() { SetHerdrVersion("") })
+	info := &system.SystemInfo{OS: system.OSDebian, Libc: "glibc"}
+	if herdrSourceReason(info, runtime.GOARCH) == "" {
+		for v, unpinned := range map[string]bool{"": false, herdrVersion: false, "0.9.0": true, "latest": true} {
+			SetHerdrVersion(v)
+			if got := herdrReleaseUnpinned(info); got != unpinned {
+				t.Errorf("%q: expected unpinned=%v, got %v", v, unpinned, got)
+			}
+		}
+	}
+}
+
+func TestHerdrSourceReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		info   system.SystemInfo
+		goarch string
+		source bool
+	}{
+		{"glibc x86_64", system.SystemInfo{OS: system.OSDebian, Libc: "glibc"}, "amd64", false},
+		{"glibc aarch64", system.SystemInfo{OS: system.OSArch, Libc: "glibc"}, "arm64", false},
+		{"termux", system.SystemInfo{OS: system.OSTermux, IsTermux: true}, "arm64", true},
+		{"alpine", system.SystemInfo{OS: system.OSAlpine, Libc: "musl"}, "amd64", true},
+		{"riscv", system.SystemInfo{OS: system.OSDebian, Libc: "glibc"}, "riscv64", true},
+		{"macos", system.SystemInfo{OS: system.OSMac}, "arm64", true},
+	}
+	for _, tt := range tests {
+		if got := herdrSourceReason(&tt.info, tt.goarch) != ""; got != tt.source {
+			t.Errorf("%s: expected source build %v, got %v", tt.name, tt.source, got)
+		}
+	}
+}
+
+func TestInstallHerdrFromSource(t *testing.T) {
+	home, bin := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// A cargo that "builds" a herdr reporting the requested version, once
+	cargo := `#!/bin/sh
+[ -e "$HOME/built" ] && exit 1
+touch "$HOME/built"
+while [ $# -gt 0 ]; do
+  case "$1" in --root) root="$2"; shift ;; --version) version="$2"; shift ;; esac
+  shift
+done
+mkdir -p "$root/bin"
+printf '#!/bin/sh\necho "herdr %s"\n' "$version" > "$root/bin/herdr"
+`
+	os.WriteFile(filepath.Join(bin, "cargo"), []byte(cargo), 0755)
+
+	m := NewModel()
+	m.SystemInfo = &system.SystemInfo{OS: system.OSTermux, IsTermux: true}
+	for i := 0; i < 2; i++ {
+		if err := installHerdrFromSource(&m, "herdr", "0.8.0"); err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+	}
+	if got := installedHerdrVersion(herdrBinPath()); got != "0.8.0" {
+		t.Errorf("expected herdr 0.8.0 in ~/.local/bin, got %q", got)
+	}
+
+	// "latest" is resolved first, so an up-to-date build is not redone
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/releases/latest" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"tag_name": "v0.8.0", "assets": []}`))
+	}))
+	defer api.Close()
+	original := herdrReleaseAPI
+	t.Cleanup(func() { herdrReleaseAPI = original })
+	herdrReleaseAPI = api.URL + "/releases/"
+	if err := installHerdrFromSource(&m, "herdr", "latest"); err != nil {
+		t.Errorf("expected latest to match the installed 0.8.0 without a rebuild: %v", err)
+	}
+}
+
+func TestShadowingHerdr(t *testing.T) {
+	home, first := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	dest := herdrBinPath()
+	os.MkdirAll(filepath.Dir(dest), 0755)
+	os.WriteFile(dest, []byte("#!/bin/sh\n"), 0755)
+
+	t.Setenv("PATH", filepath.Dir(dest))
+	if got := shadowingHerdr(dest); got != "" {
+		t.Errorf("expected the installed herdr to win, got %q", got)
+	}
+	os.WriteFile(filepath.Join(first, "herdr"), []byte("#!/bin/sh\n"), 0755)
+	t.Setenv("PATH", first+string(os.PathListSeparator)+filepath.Dir(dest))
+	if got := shadowingHerdr(dest); got != filepath.Join(first, "herdr") {
+		t.Errorf("expected the herdr earlier on PATH to be reported, got %q", got)
+	}
+}
+
+func TestHerdrKeyLabel(t *testing.T) {
+	tests := map[string]string{
+		"prefix+alt+k":     "Ctrl+a Alt+k",
+		"prefix+ctrl+1..9": "Ctrl+a Ctrl+1-9",
+		"prefix+shift+j/k": "Ctrl+a Shift+j/k",
+		"prefix+f2":        "Ctrl+a F2",
+		"ctrl+space":       "Ctrl+Space",
+	}
+	for spec, want := range tests {
+		if got := herdrKeyLabel(spec, "ctrl+a"); got != want {
+			t.Errorf("%s: expected %q, got %q", spec, want, got)
+		}
+	}
+}
+
+func TestCycleHerdrKey(t *testing.T) {
+	prefix, _ := system.LookupHerdrBinding("prefix")
+	if got := cycleHerdrKey(prefix, "ctrl+a", "ctrl+a", -1); got != "ctrl+space" {
+		t.Errorf("expected the last preset before the first, got %q", got)
+	}
+	if got := cycleHerdrKey(prefix, "ctrl+q", "ctrl+space", 1); got != "ctrl+q" {
+		t.Errorf("a custom prefix must stay reachable, got %q", got)
+	}
+}
+
+func TestHerdrKeysScreen(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "herdr", "config.toml"))
+	if err != nil {
+		t.Skipf("shipped herdr config not available: %v", err)
+	}
+	path := wmConfigPath("herdr")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, data, 0644)
+
+	m := NewModel()
+	m.Screen = ScreenReconfigure
+	for i, opt := range m.GetCurrentOptions() {
+		if strings.Contains(opt, "Herdr Keys") {
+			m.Cursor = i
+		}
+	}
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenReconfigureHerdr || m.HerdrNotice != "" {
+		t.Fatalf("expected the Herdr keys screen, got %v (%s)", m.Screen, m.HerdrNotice)
+	}
+	if m.HerdrKeyValues["prefix"] != "ctrl+a" || m.HerdrKeyValues["next_agent"] != "prefix+alt+j" {
+		t.Fatalf("unexpected current bindings %v", m.HerdrKeyValues)
+	}
+
+	// Prefix to ctrl+b, then focus_agent to its next preset
+	for _, key := range []string{"l", "j", "j", "j", "l"} {
+		result, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = result.(Model)
+	}
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenInstalling || cmd == nil || stepIDs(m.Steps)[0] != "herdrkeys" {
+		t.Fatalf("expected the Herdr keys step to start, got screen %v", m.Screen)
+	}
+	if len(m.Choices.HerdrKeys) != 2 || m.Choices.HerdrKeys["prefix"] != "ctrl+b" || m.Choices.HerdrKeys["focus_agent"] != "prefix+alt+1..9" {
+		t.Fatalf("expected only the edited bindings to be written, got %v", m.Choices.HerdrKeys)
+	}
+	if err := stepApplyHerdrKeys(&m); err != nil {
+		t.Fatalf("stepApplyHerdrKeys: %v", err)
+	}
+	written, _ := os.ReadFile(path)
+	if !strings.Contains(string(written), "prefix = \"ctrl+b\"\n") || !strings.Contains(string(written), "# Match tmux/Zellij-style prefix muscle memory.") {
+		t.Errorf("expected the new prefix with the comments kept, got:\n%s", written)
+	}
+
+	// The keymaps reference shows the installed bindings
+	agents := GetHerdrKeymaps()[1]
+	if agents.Keymaps[0].Keys != "Ctrl+b Alt+k" || agents.Keymaps[2].Keys != "Ctrl+b Alt+1-9" {
+		t.Errorf("unexpected agent keymaps %+v", agents.Keymaps)
+	}
+}
+
+func TestHerdrKeysScreen_WithoutHerdr(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if keys := GetHerdrKeymaps(); keys[0].Keymaps[0].Keys != "Ctrl+a" {
+		t.Errorf("expected the shipped prefix without a Herdr config, got %q", keys[0].Keymaps[0].Keys)
+	}
+
+	m := NewModel()
+	m.Screen = ScreenReconfigure
+	for i, opt := range m.GetCurrentOptions() {
+		if strings.Contains(opt, "Herdr Keys") {
+			m.Cursor = i
+		}
+	}
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.HerdrNotice == "" || !strings.Contains(m.View(), m.HerdrNotice) {
+		t.Fatal("expected a notice without a Herdr config")
+	}
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if result.(Model).Screen != ScreenReconfigureHerdr {
+		t.Error("Enter must not start a step without a Herdr config")
+	}
+}
@@ -390,9 +390,55 @@ start_multiplexer`
 	})
 }
 
+// TestStepInstallShellBash tests bash installation step
+func TestStepInstallShellBash(t *testing.T) {
+	shipped, err := os.ReadFile("../../../GentlemanBash/.bashrc")
+	if err != nil {
+		t.Skipf("shipped .bashrc not available: %v", err)
+	}
+
+	t.Run("bash step patches config based on WM choice - none", func(t *testing.T) {
+		bashPath := filepath.Join(t.TempDir(), ".bashrc")
+		os.WriteFile(bashPath, shipped, 0644)
+
+		if err := system.PatchBashForWM(bashPath, "none", false); err != nil {
+			t.Fatalf("Patch failed: %v", err)
+		}
+
+		content, _ := os.ReadFile(bashPath)
+		contentStr := string(content)
+
+		if contains(contentStr, "WM_CMD") || contains(contentStr, "\nstart_if_needed\n") {
+			t.Error("Multiplexer auto-start should be removed when WM=none")
+		}
+		if !contains(contentStr, "if command -v fzf") {
+			t.Error("fzf should be wrapped when nvim=false")
+		}
+	})
+
+	t.Run("bash step patches config based on WM choice - zellij", func(t *testing.T) {
+		bashPath := filepath.Join(t.TempDir(), ".bashrc")
+		os.WriteFile(bashPath, shipped, 0644)
+
+		if err := system.PatchBashForWM(bashPath, "zellij", true); err != nil {
+			t.Fatalf("Patch failed: %v", err)
+		}
+
+		content, _ := os.ReadFile(bashPath)
+		contentStr := string(content)
+
+		if !contains(contentStr, `WM_CMD="zellij"`) || !contains(contentStr, `WM_VAR="$ZELLIJ"`) {
+			t.Error("Zellij should be configured")
+		}
+		if !contains(contentStr, "\nstart_if_needed\n") {
+			t.Error("start_if_needed should be called")
+		}
+	})
+}
+
 // TestAllShellAndWMCombinations tests all shell+WM combinations
 func TestAllShellAndWMCombinations(t *testing.T) {
-	shells := []string{"zsh", "fish", "nushell"}
+	shells := []string{"zsh", "fish", "nushell", "bash"}
 	wms := []string{"tmux", "zellij", "herdr", "none"}
 	nvimOptions := []bool{true, false}
 
@@ -441,6 +487,11 @@ start_multiplexer`
 						path := filepath.Join(tmpDir, "config.nu")
 						os.WriteFile(path, []byte(content), 0644)
 						err = system.PatchNushellForWM(path, wm)
+
+					case "bash":
+						path := filepath.Join(tmpDir, ".bashrc")
+						os.WriteFile(path, []byte("alias ll='ls -l'\n"), 0644)
+						err = system.PatchBashForWM(path, wm, nvim)
 					}
 
 					if err != nil {
@@ -606,8 +657,9 @@ func TestEachTerminalSelection(t *testing.T) {
 	}{
 		{"alacritty", 0, "alacritty"},
 		{"wezterm", 1, "wezterm"},
-		{"ghostty", 2, "ghostty"},
-		{"none", 3, "none"},
+		{"kitty", 2, "kitty"},
+		{"ghostty", 3, "ghostty"},
+		{"none", 4, "none"},
 	}
 
 	for _, tc := range terminalsLinux {
@@ -635,6 +687,7 @@ func TestEachShellSelection(t *testing.T) {
 		{"fish", 0, "fish"},
 		{"zsh", 1, "zsh"},
 		{"nushell", 2, "nushell"},
+		{"bash", 3, "bash"},
 	}
 
 	for _, tc := range shells {
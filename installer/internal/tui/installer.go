@@ -1,12 +1,12 @@
 package tui
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
@@ -71,6 +71,24 @@ func executeStep(stepID string, m *Model) error {
 		return stepCleanup(m)
 	case "setshell":
 		return stepSetDefaultShell(m)
+	case "reconfigwm":
+		return stepReconfigureWM(m)
+	case "reconfigshell":
+		return stepReconfigureShell(m)
+	case "theme":
+		return stepApplyTheme(m)
+	case "shaders":
+		return stepApplyShaders(m)
+	case "zellijlayout":
+		return stepApplyZellijLayout(m)
+	case "herdr":
+		return stepUpdateHerdr(m)
+	case "herdrkeys":
+		return stepApplyHerdrKeys(m)
+	case "tuneterminal":
+		return stepTuneTerminal(m)
+	case "nix":
+		return stepGenerateNix(m)
 	default:
 		return fmt.Errorf("unknown step: %s", stepID)
 	}
@@ -105,6 +123,10 @@ func stepBackupConfigs(m *Model) error {
 func stepCloneRepo(m *Model) error {
 	stepID := "clone"
 
+	if linkMode.enabled {
+		return syncPersistentRepo(stepID, repoPath())
+	}
+
 	// Check if already exists
 	if _, err := os.Stat("Gentleman.Dots"); err == nil {
 		SendLog(stepID, "Removing existing Gentleman.Dots directory...")
@@ -119,13 +141,12 @@ func stepCloneRepo(m *Model) error {
 	}
 
 	SendLog(stepID, "Cloning repository from GitHub...")
-	result := system.RunWithLogs("git clone --progress https://github.com/Gentleman-Programming/Gentleman.Dots.git Gentleman.Dots", nil, func(line string) {
+	if err := system.GitClone(gentlemanRepoURL, "Gentleman.Dots", func(line string) {
 		SendLog(stepID, line)
-	})
-	if result.Error != nil {
+	}); err != nil {
 		return wrapStepError("clone", "Clone Repository",
 			"Failed to clone the repository. Check your internet connection and git installation.",
-			result.Error)
+			err)
 	}
 
 	// Verify clone was successful
@@ -139,6 +160,41 @@ func stepCloneRepo(m *Model) error {
 	return nil
 }
 
+// syncPersistentRepo clones the repo into the link-mode location, or
+// fast-forwards an existing clone so `git pull` style upgrades keep working.
+func syncPersistentRepo(stepID, dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		SendLog(stepID, fmt.Sprintf("Updating existing clone at %s...", dir))
+		result := system.RunWithLogs("git -C "+shellQuote(dir)+" pull --ff-only", nil, func(line string) {
+			SendLog(stepID, line)
+		})
+		if result.Error != nil {
+			// Local commits or a dirty tree are expected in a fork; keep going with what is there
+			SendLog(stepID, "Warning: could not fast-forward the clone, using it as-is")
+		}
+		SendLog(stepID, "✓ Repository ready")
+		return nil
+	}
+
+	if err := system.EnsureDir(filepath.Dir(dir)); err != nil {
+		return wrapStepError("clone", "Clone Repository",
+			"Failed to create the persistent repository directory",
+			err)
+	}
+
+	SendLog(stepID, fmt.Sprintf("Cloning repository into %s...", dir))
+	if err := system.GitClone(gentlemanRepoURL, dir, func(line string) {
+		SendLog(stepID, line)
+	}); err != nil {
+		return wrapStepError("clone", "Clone Repository",
+			"Failed to clone the repository. Check your internet connection and git installation.",
+			err)
+	}
+
+	SendLog(stepID, "✓ Repository cloned successfully")
+	return nil
+}
+
 func stepInstallHomebrew(m *Model) error {
 	stepID := "homebrew"
 
@@ -153,8 +209,14 @@ func stepInstallHomebrew(m *Model) error {
 		return nil
 	}
 
+	if err := system.CheckInstallScript("Homebrew"); err != nil {
+		return wrapStepError("homebrew", "Install Homebrew",
+			"Homebrew only ships an unverifiable install script. Install Homebrew yourself or allow install scripts.",
+			err)
+	}
+
 	SendLog(stepID, "Installing Homebrew package manager...")
-	result := system.RunWithLogs(`/bin/bash -c "$(curl -fsSL https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh)"`, nil, func(line string) {
+	result := system.RunWithLogs(fmt.Sprintf(`/bin/bash -c "$(curl -fsSL %s)"`, system.GitHubURL(homebrewInstallURL)), nil, func(line string) {
 		SendLog(stepID, line)
 	})
 	if result.Error != nil {
@@ -173,9 +235,12 @@ func stepInstallHomebrew(m *Model) error {
 	// Add to common shell configs
 	for _, rcFile := range []string{".bashrc", ".zshrc"} {
 		rcPath := filepath.Join(homeDir, rcFile)
-		if f, err := os.OpenFile(rcPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
-			f.WriteString("\n" + shellConfig + "\n")
-			f.Close()
+		if info, err := os.Lstat(rcPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			// Linked configs live in the repo and already load brew shellenv
+			continue
+		}
+		if err := system.SetHomebrewShellenv(rcPath, shellConfig); err != nil {
+			SendLog(stepID, fmt.Sprintf("Could not update %s: %v", rcFile, err))
 		}
 	}
 
@@ -187,83 +252,93 @@ func stepInstallHomebrew(m *Model) error {
 }
 
 func stepInstallDeps(m *Model) error {
-	stepID := "deps"
+	return runPlan(depsPlan(m))
+}
+
+// errNixOSImperative is why packages are not installed imperatively on NixOS
+var errNixOSImperative = errors.New("NixOS installs packages declaratively; generate the home-manager module with --nix instead")
+
+// depsPlan installs the base packages every later step relies on
+func depsPlan(m *Model) *stepPlan {
+	p := &stepPlan{ID: "deps", Name: "Install Dependencies", Title: "📦 Installing dependencies...",
+		Done: []string{"✓ Dependencies installed"}}
+	sudo := func(log, command, fail string) stepAction {
+		return stepAction{Kind: actionPackages, Log: log, Command: command, Sudo: true, Fail: fail}
+	}
 
 	// Termux: use pkg (no sudo needed)
 	// Check both SystemInfo and Choices.OS for redundancy
-	isTermux := m.SystemInfo.IsTermux || m.Choices.OS == "termux"
-	if isTermux {
-		SendLog(stepID, "Updating Termux packages...")
-		result := system.RunPkgWithLogs("update", nil, func(line string) {
-			SendLog(stepID, line)
-		})
-		if result.Error != nil {
-			return wrapStepError("deps", "Install Dependencies",
-				"Failed to update Termux packages",
-				result.Error)
-		}
-		result = system.RunPkgWithLogs("upgrade -y", nil, func(line string) {
-			SendLog(stepID, line)
-		})
-		if result.Error != nil {
+	if m.SystemInfo.IsTermux || m.Choices.OS == "termux" {
+		p.Actions = []stepAction{
+			{Kind: actionPackages, Log: "Updating Termux packages...", Command: "pkg update",
+				Fail: "Failed to update Termux packages"},
 			// Upgrade failures are not critical
-			SendLog(stepID, "Warning: package upgrade had issues, continuing...")
-		}
-		SendLog(stepID, "Installing base dependencies...")
-		result = system.RunPkgInstall("git curl", nil, func(line string) {
-			SendLog(stepID, line)
-		})
-		if result.Error != nil {
-			return wrapStepError("deps", "Install Dependencies",
-				"Failed to install base dependencies on Termux",
-				result.Error)
+			{Kind: actionPackages, Command: "pkg upgrade -y", Optional: true,
+				Fail: "package upgrade had issues, continuing..."},
+			{Kind: actionPackages, Log: "Installing base dependencies...", Command: "pkg install -y git curl",
+				Fail: "Failed to install base dependencies on Termux"},
 		}
-		return nil
+		return p
 	}
 
-	// Arch Linux
-	if m.SystemInfo.OS == system.OSArch {
-		result := system.RunSudo("pacman -Syu --noconfirm", nil)
-		if result.Error != nil {
-			return wrapStepError("deps", "Install Dependencies",
-				"Failed to update Arch Linux packages",
-				result.Error)
-		}
-		result = system.RunSudo("pacman -S --needed --noconfirm base-devel curl file git wget unzip fontconfig", nil)
-		if result.Error != nil {
-			return wrapStepError("deps", "Install Dependencies",
-				"Failed to install base dependencies on Arch Linux",
-				result.Error)
-		}
-		return nil
+	if m.SystemInfo.DistroFamily == system.FamilyNixOS {
+		p.Actions = []stepAction{{Kind: actionNote, Log: "Skipping system packages: " + errNixOSImperative.Error()}}
+		return p
 	}
 
-	// Fedora/RHEL
-	if m.SystemInfo.OS == system.OSFedora {
-		result := system.RunSudo("dnf check-update || true", nil) // dnf check-update returns 100 if updates available
-		result = system.RunSudo("dnf install -y @development-tools curl file git wget unzip fontconfig", nil)
-		if result.Error != nil {
-			return wrapStepError("deps", "Install Dependencies",
-				"Failed to install base dependencies on Fedora/RHEL",
-				result.Error)
+	switch m.SystemInfo.OS {
+	case system.OSArch:
+		p.Actions = []stepAction{
+			sudo("Updating Arch Linux packages...", "pacman -Syu --noconfirm",
+				"Failed to update Arch Linux packages"),
+			sudo("Installing base dependencies...", "pacman -S --needed --noconfirm base-devel curl file git wget unzip xz fontconfig",
+				"Failed to install base dependencies on Arch Linux"),
+		}
+	case system.OSFedora:
+		p.Actions = []stepAction{
+			// dnf check-update returns 100 if updates are available
+			sudo("Checking for Fedora/RHEL updates...", "dnf check-update || true",
+				"Failed to check for Fedora/RHEL updates"),
+			sudo("Installing base dependencies...", "dnf install -y @development-tools curl file git wget unzip xz fontconfig",
+				"Failed to install base dependencies on Fedora/RHEL"),
+		}
+	case system.OSSUSE:
+		p.Actions = []stepAction{
+			sudo("Refreshing openSUSE repositories...", "zypper --non-interactive refresh",
+				"Failed to refresh zypper repositories"),
+			sudo("Installing base dependencies...", "zypper --non-interactive install -t pattern devel_basis",
+				"Failed to install base dependencies on openSUSE"),
+			sudo("", "zypper --non-interactive install curl file git wget unzip xz fontconfig procps",
+				"Failed to install base dependencies on openSUSE"),
+		}
+	case system.OSAlpine:
+		// musl: gcompat lets glibc release binaries run, shadow provides chsh
+		p.Actions = []stepAction{
+			sudo("Updating Alpine package index...", "apk update",
+				"Failed to update apk package index"),
+			sudo("Installing base dependencies...", "apk add --no-cache build-base curl file git wget unzip xz fontconfig bash procps ncurses shadow gcompat",
+				"Failed to install base dependencies on Alpine Linux"),
+		}
+	case system.OSVoid:
+		// xbps has to update itself before the rest of the system
+		p.Actions = []stepAction{
+			sudo("Updating Void Linux packages...", "xbps-install -Syu xbps",
+				"Failed to update xbps"),
+			sudo("", "xbps-install -yu",
+				"Failed to update Void Linux packages"),
+			sudo("Installing base dependencies...", "xbps-install -y base-devel curl file git wget unzip xz fontconfig procps-ng",
+				"Failed to install base dependencies on Void Linux"),
+		}
+	default:
+		// Debian/Ubuntu
+		p.Actions = []stepAction{
+			sudo("Updating apt package list...", "apt-get update",
+				"Failed to update apt package list"),
+			sudo("Installing base dependencies...", "apt-get install -y build-essential curl file git unzip xz-utils fontconfig procps",
+				"Failed to install base dependencies on Debian/Ubuntu"),
 		}
-		return nil
-	}
-
-	// Debian/Ubuntu
-	result := system.RunSudo("apt-get update", nil)
-	if result.Error != nil {
-		return wrapStepError("deps", "Install Dependencies",
-			"Failed to update apt package list",
-			result.Error)
-	}
-	result = system.RunSudo("apt-get install -y build-essential curl file git unzip fontconfig procps", nil)
-	if result.Error != nil {
-		return wrapStepError("deps", "Install Dependencies",
-			"Failed to install base dependencies on Debian/Ubuntu",
-			result.Error)
 	}
-	return nil
+	return p
 }
 
 func stepInstallXcode(m *Model) error {
@@ -281,264 +356,207 @@ func stepInstallXcode(m *Model) error {
 }
 
 func stepInstallTerminal(m *Model) error {
-	terminal := m.Choices.Terminal
-	homeDir := os.Getenv("HOME")
-	repoDir := "Gentleman.Dots"
-	stepID := "terminal"
+	p, err := terminalPlan(m)
+	if err != nil {
+		return err
+	}
+	return runPlan(p)
+}
 
-	switch terminal {
-	case "alacritty":
-		if !system.CommandExists("alacritty") {
-			SendLog(stepID, "Installing Alacritty...")
-			var result *system.ExecResult
-			if m.SystemInfo.OS == system.OSArch {
-				result = system.RunSudoWithLogs("pacman -S --noconfirm alacritty", nil, func(line string) {
-					SendLog(stepID, line)
-				})
-			} else if m.SystemInfo.OS == system.OSMac {
-				result = system.RunBrewWithLogs("install --cask alacritty", nil, func(line string) {
-					SendLog(stepID, line)
-				})
-			} else if m.SystemInfo.OS == system.OSFedora {
-				// Fedora: install from dnf
-				result = system.RunSudoWithLogs("dnf install -y alacritty", nil, func(line string) {
-					SendLog(stepID, line)
-				})
-			} else if m.SystemInfo.OS == system.OSDebian || m.SystemInfo.OS == system.OSLinux {
-				// Debian/Ubuntu: compile from source (PPAs are unreliable)
-				SendLog(stepID, "Building Alacritty from source...")
-				SendLog(stepID, "Installing build dependencies...")
-				result = system.RunSudoWithLogs("apt-get install -y cmake pkg-config libfreetype6-dev libfontconfig1-dev libxcb-xfixes0-dev libxkbcommon-dev python3 gzip scdoc git curl", nil, func(line string) {
-					SendLog(stepID, line)
-				})
-				if result.Error != nil {
-					return wrapStepError("terminal", "Install Alacritty",
-						"Failed to install build dependencies",
-						result.Error)
-				}
-				// Install Rust/Cargo only for this build
-				cargoPath := filepath.Join(homeDir, ".cargo/bin/cargo")
-				if !system.CommandExists("cargo") && !system.CommandExists(cargoPath) {
-					SendLog(stepID, "Installing Rust/Cargo toolchain...")
-					result = system.RunWithLogs("curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh -s -- -y", nil, func(line string) {
-						SendLog(stepID, line)
-					})
-					if result.Error != nil {
-						return wrapStepError("terminal", "Install Alacritty",
-							"Failed to install Rust",
-							result.Error)
-					}
-					cargoPath = filepath.Join(homeDir, ".cargo/bin/cargo")
-				}
-				// Clone and build Alacritty
-				SendLog(stepID, "Cloning Alacritty repository...")
-				alacrittyDir := filepath.Join(os.TempDir(), "alacritty-build")
-				os.RemoveAll(alacrittyDir)
-				result = system.RunWithLogs(fmt.Sprintf("git clone https://github.com/alacritty/alacritty.git %s", alacrittyDir), nil, func(line string) {
-					SendLog(stepID, line)
-				})
-				if result.Error != nil {
-					return wrapStepError("terminal", "Install Alacritty",
-						"Failed to clone Alacritty repository",
-						result.Error)
-				}
-				SendLog(stepID, "Building Alacritty (this may take 5-10 minutes)...")
-				if !system.CommandExists("cargo") {
-					cargoPath = filepath.Join(homeDir, ".cargo/bin/cargo")
-				} else {
-					cargoPath = "cargo"
-				}
-				result = system.RunWithLogs(fmt.Sprintf("%s build --release --manifest-path %s/Cargo.toml", cargoPath, alacrittyDir), nil, func(line string) {
-					SendLog(stepID, line)
-				})
-				if result.Error != nil {
-					return wrapStepError("terminal", "Install Alacritty",
-						"Failed to build Alacritty",
-						result.Error)
-				}
-				SendLog(stepID, "Installing Alacritty binary...")
-				result = system.RunSudoWithLogs(fmt.Sprintf("cp %s/target/release/alacritty /usr/local/bin/alacritty", alacrittyDir), nil, func(line string) {
-					SendLog(stepID, line)
-				})
-				if result.Error != nil {
-					return wrapStepError("terminal", "Install Alacritty",
-						"Failed to install Alacritty binary",
-						result.Error)
-				}
-				system.RunSudoWithLogs(fmt.Sprintf("cp %s/extra/linux/Alacritty.desktop /usr/share/applications/", alacrittyDir), nil, func(line string) {
-					SendLog(stepID, line)
-				})
-				os.RemoveAll(alacrittyDir)
-				SendLog(stepID, "✓ Alacritty built and installed from source")
-			} else {
-				return wrapStepError("terminal", "Install Alacritty",
-					"Unsupported operating system for Alacritty installation",
-					fmt.Errorf("OS type: %v", m.SystemInfo.OS))
-			}
-			if result.Error != nil {
-				return wrapStepError("terminal", "Install Alacritty",
-					"Failed to install Alacritty terminal emulator",
-					result.Error)
-			}
-		} else {
-			SendLog(stepID, "Alacritty already installed")
-		}
-		SendLog(stepID, "Copying Alacritty configuration...")
-		if err := system.EnsureDir(filepath.Join(homeDir, ".config/alacritty")); err != nil {
-			return wrapStepError("terminal", "Install Alacritty",
-				"Failed to create Alacritty config directory",
-				err)
-		}
-		if err := system.CopyFile(filepath.Join(repoDir, "alacritty.toml"), filepath.Join(homeDir, ".config/alacritty/alacritty.toml")); err != nil {
-			return wrapStepError("terminal", "Install Alacritty",
-				"Failed to copy Alacritty configuration",
-				err)
-		}
-		SendLog(stepID, "✓ Alacritty configured")
+// terminalSpec describes how a terminal emulator is installed and configured
+type terminalSpec struct {
+	Label  string
+	Binary string
+	Native string // package on Arch, Fedora, openSUSE, Alpine and Void
+	Copr   string // Fedora COPR repository providing Native
+	Cask   string
+	Config string // config in the repo
+	Dest   string // config target relative to $HOME
+	Tree   bool   // Config is a directory
+
+	Unpackaged []system.OSType // distros whose default repositories lack Native
+}
 
-	case "wezterm":
-		if !system.CommandExists("wezterm") {
-			SendLog(stepID, "Installing WezTerm...")
-			var result *system.ExecResult
-			if m.SystemInfo.OS == system.OSArch {
-				result = system.RunSudoWithLogs("pacman -S --noconfirm wezterm", nil, func(line string) {
-					SendLog(stepID, line)
-				})
-			} else if m.SystemInfo.OS == system.OSFedora {
-				// Fedora: enable COPR and install
-				system.RunSudo("dnf copr enable -y wezfurlong/wezterm-nightly", nil)
-				result = system.RunSudoWithLogs("dnf install -y wezterm", nil, func(line string) {
-					SendLog(stepID, line)
-				})
-			} else if m.SystemInfo.OS == system.OSMac {
-				result = system.RunBrewWithLogs("install --cask wezterm", nil, func(line string) {
-					SendLog(stepID, line)
-				})
-			} else {
-				system.Run("brew tap wez/wezterm-linuxbrew", nil)
-				result = system.RunBrewWithLogs("install wezterm", nil, func(line string) {
-					SendLog(stepID, line)
-				})
-			}
-			if result.Error != nil {
-				return wrapStepError("terminal", "Install WezTerm",
-					"Failed to install WezTerm terminal emulator",
-					result.Error)
-			}
-		} else {
-			SendLog(stepID, "WezTerm already installed")
-		}
-		SendLog(stepID, "Copying WezTerm configuration...")
-		if err := system.EnsureDir(filepath.Join(homeDir, ".config/wezterm")); err != nil {
-			return wrapStepError("terminal", "Install WezTerm",
-				"Failed to create WezTerm config directory",
-				err)
-		}
-		if err := system.CopyFile(filepath.Join(repoDir, ".wezterm.lua"), filepath.Join(homeDir, ".config/wezterm/wezterm.lua")); err != nil {
-			return wrapStepError("terminal", "Install WezTerm",
-				"Failed to copy WezTerm configuration",
-				err)
+var terminalSpecs = map[string]terminalSpec{
+	"alacritty": {Label: "Alacritty", Binary: "alacritty", Native: "alacritty", Cask: "alacritty",
+		Config: "alacritty.toml", Dest: ".config/alacritty/alacritty.toml"},
+	"wezterm": {Label: "WezTerm", Binary: "wezterm", Native: "wezterm", Copr: "wezfurlong/wezterm-nightly", Cask: "wezterm",
+		Config: ".wezterm.lua", Dest: ".config/wezterm/wezterm.lua"},
+	"kitty": {Label: "Kitty", Binary: "kitty", Native: "kitty", Cask: "kitty",
+		Config: "GentlemanKitty", Dest: ".config/kitty", Tree: true},
+	"ghostty": {Label: "Ghostty", Binary: "ghostty", Native: "ghostty", Copr: "pgdev/ghostty", Cask: "ghostty",
+		Config: "GentlemanGhostty", Dest: ".config/ghostty", Tree: true,
+		Unpackaged: []system.OSType{system.OSAlpine}}, // only in edge/testing
+}
+
+// nativeInstallCommand is the distro install command for packages, or ""
+// where the distro has no package for it and Homebrew or a build is used
+func nativeInstallCommand(osType system.OSType, packages string) string {
+	switch osType {
+	case system.OSArch:
+		return "pacman -S --needed --noconfirm " + packages
+	case system.OSFedora:
+		return "dnf install -y " + packages
+	case system.OSSUSE:
+		return "zypper --non-interactive install " + packages
+	case system.OSAlpine:
+		return "apk add --no-cache " + packages
+	case system.OSVoid:
+		return "xbps-install -y " + packages
+	}
+	return ""
+}
+
+// terminalPlan installs the chosen terminal emulator and deploys its config
+func terminalPlan(m *Model) (*stepPlan, error) {
+	spec, ok := terminalSpecs[m.Choices.Terminal]
+	if !ok {
+		return &stepPlan{ID: "terminal"}, nil
+	}
+	p := &stepPlan{ID: "terminal", Name: "Install " + spec.Label, Title: "🖥️  Installing " + spec.Label + "...",
+		Done: []string{"✓ " + spec.Label + " configured"}}
+	if system.CommandExists(spec.Binary) {
+		p.Actions = append(p.Actions, stepAction{Kind: actionNote, Log: spec.Label + " already installed"})
+	} else {
+		install, err := terminalInstallActions(m, spec)
+		if err != nil {
+			return nil, err
 		}
-		SendLog(stepID, "✓ WezTerm configured")
+		p.Actions = append(p.Actions, install...)
+	}
+	p.Actions = append(p.Actions, stepAction{Kind: actionCopy, Log: "Copying " + spec.Label + " configuration...",
+		Src: filepath.Join(repoPath(), spec.Config), Dst: filepath.Join(os.Getenv("HOME"), spec.Dest), Tree: spec.Tree,
+		Fail: "Failed to copy " + spec.Label + " configuration"})
+	return p, nil
+}
+
+// terminalInstallActions installs a terminal that is not on PATH yet
+func terminalInstallActions(m *Model, spec terminalSpec) ([]stepAction, error) {
+	install := stepAction{Kind: actionPackages, Log: "Installing " + spec.Label + "...",
+		Fail: "Failed to install " + spec.Label + " terminal emulator"}
+	osType := m.SystemInfo.OS
+	brew := system.GetBrewPrefix() + "/bin/brew"
+
+	if osType == system.OSMac {
+		install.Command = brew + " install --cask " + spec.Cask
+		return []stepAction{install}, nil
+	}
+	if native := nativeInstallCommand(osType, spec.Native); spec.Native != "" && native != "" && !slices.Contains(spec.Unpackaged, osType) {
+		install.Command, install.Sudo = native, true
+		if osType == system.OSFedora && spec.Copr != "" {
+			copr := stepAction{Kind: actionRun, Command: "dnf copr enable -y " + spec.Copr, Sudo: true,
+				Optional: true, Fail: "Could not enable COPR " + spec.Copr}
+			return []stepAction{copr, install}, nil
+		}
+		return []stepAction{install}, nil
+	}
 
+	// Debian/Ubuntu: no usable distro packages
+	switch spec.Binary {
 	case "kitty":
-		if !system.CommandExists("kitty") && m.SystemInfo.OS == system.OSMac {
-			SendLog(stepID, "Installing Kitty...")
-			result := system.RunBrewWithLogs("install --cask kitty", nil, func(line string) {
-				SendLog(stepID, line)
-			})
-			if result.Error != nil {
-				return wrapStepError("terminal", "Install Kitty",
-					"Failed to install Kitty terminal emulator",
-					result.Error)
-			}
-		} else {
-			SendLog(stepID, "Kitty already installed")
-		}
-		SendLog(stepID, "Copying Kitty configuration...")
-		if err := system.EnsureDir(filepath.Join(homeDir, ".config/kitty")); err != nil {
-			return wrapStepError("terminal", "Install Kitty",
-				"Failed to create Kitty config directory",
-				err)
+		// Debian/Ubuntu ship an old kitty; use the verified upstream bundle
+		return kittyBundleActions(m)
+	case "alacritty":
+		if osType != system.OSDebian && osType != system.OSLinux {
+			return nil, wrapStepError("terminal", "Install Alacritty",
+				"Unsupported operating system for Alacritty installation",
+				fmt.Errorf("OS type: %v", osType))
 		}
-		if err := system.CopyDir(filepath.Join(repoDir, "GentlemanKitty"), filepath.Join(homeDir, ".config", "kitty")); err != nil {
-			return wrapStepError("terminal", "Install Kitty",
-				"Failed to copy Kitty configuration",
+		return alacrittyBuildActions()
+	case "wezterm":
+		install.Command = brew + " install wezterm"
+		tap := stepAction{Kind: actionRun, Command: brew + " tap wez/wezterm-linuxbrew",
+			Optional: true, Fail: "Could not tap wez/wezterm-linuxbrew"}
+		return []stepAction{tap, install}, nil
+	case "ghostty":
+		if osType != system.OSDebian && osType != system.OSLinux {
+			return nil, wrapStepError("terminal", "Install Ghostty",
+				"Ghostty has no package in this distro's default repositories. Install it yourself or pick another terminal.",
+				fmt.Errorf("OS type: %v", osType))
+		}
+		if err := system.CheckInstallScript("Ghostty (ghostty-ubuntu)"); err != nil {
+			return nil, wrapStepError("terminal", "Install Ghostty",
+				"Ghostty on Debian/Ubuntu is installed with a community script. Allow install scripts or pick another terminal.",
 				err)
 		}
-		SendLog(stepID, "✓ Kitty configured")
+		// The community script calls sudo itself
+		install.Kind, install.Prompts = actionRun, true
+		install.Command = fmt.Sprintf(`/bin/bash -c "$(curl -fsSL %s)"`, system.GitHubURL(ghosttyUbuntuInstallURL))
+		return []stepAction{install}, nil
+	}
+	return nil, nil
+}
 
-	case "ghostty":
-		if !system.CommandExists("ghostty") {
-			SendLog(stepID, "Installing Ghostty...")
-			var result *system.ExecResult
-			if m.SystemInfo.OS == system.OSArch {
-				result = system.RunSudoWithLogs("pacman -S --noconfirm ghostty", nil, func(line string) {
-					SendLog(stepID, line)
-				})
-			} else if m.SystemInfo.OS == system.OSFedora {
-				// Fedora: enable COPR and install
-				system.RunSudo("dnf copr enable -y pgdev/ghostty", nil)
-				result = system.RunSudoWithLogs("dnf install -y ghostty", nil, func(line string) {
-					SendLog(stepID, line)
-				})
-			} else if m.SystemInfo.OS == system.OSMac {
-				result = system.RunBrewWithLogs("install --cask ghostty", nil, func(line string) {
-					SendLog(stepID, line)
-				})
-			} else {
-				result = system.RunWithLogs(`/bin/bash -c "$(curl -fsSL https://raw.githubusercontent.com/mkasberg/ghostty-ubuntu/HEAD/install.sh)"`, nil, func(line string) {
-					SendLog(stepID, line)
-				})
-			}
-			if result.Error != nil {
-				return wrapStepError("terminal", "Install Ghostty",
-					"Failed to install Ghostty terminal emulator",
-					result.Error)
+// alacrittyBuildActions compiles Alacritty from source (PPAs are unreliable)
+func alacrittyBuildActions() ([]stepAction, error) {
+	actions := []stepAction{{Kind: actionPackages, Log: "Installing build dependencies...", Sudo: true,
+		Command: "apt-get install -y cmake pkg-config libfreetype6-dev libfontconfig1-dev libxcb-xfixes0-dev libxkbcommon-dev python3 gzip scdoc git curl",
+		Fail:    "Failed to install build dependencies"}}
+
+	// Install Rust/Cargo only for this build
+	cargo := "cargo"
+	if !system.CommandExists(cargo) {
+		cargo = filepath.Join(os.Getenv("HOME"), ".cargo/bin/cargo")
+		if !system.FileExists(cargo) {
+			if err := system.CheckInstallScript("rustup"); err != nil {
+				return nil, wrapStepError("terminal", "Install Alacritty",
+					"Building Alacritty needs Rust. Install rustup yourself or allow install scripts.",
+					err)
 			}
-		} else {
-			SendLog(stepID, "Ghostty already installed")
+			actions = append(actions, stepAction{Kind: actionRun, Log: "Installing Rust/Cargo toolchain...",
+				Command: "curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh -s -- -y",
+				Fail:    "Failed to install Rust"})
 		}
-		SendLog(stepID, "Copying Ghostty configuration...")
-		if err := system.EnsureDir(filepath.Join(homeDir, ".config/ghostty")); err != nil {
-			return wrapStepError("terminal", "Install Ghostty",
-				"Failed to create Ghostty config directory",
-				err)
-		}
-		if err := system.CopyDir(filepath.Join(repoDir, "GentlemanGhostty"), filepath.Join(homeDir, ".config", "ghostty")); err != nil {
-			return wrapStepError("terminal", "Install Ghostty",
-				"Failed to copy Ghostty configuration",
-				err)
-		}
-		SendLog(stepID, "✓ Ghostty configured")
 	}
 
-	return nil
+	dir := filepath.Join(os.TempDir(), "alacritty-build")
+	return append(actions,
+		stepAction{Kind: actionFetch, Log: "Cloning Alacritty repository...", Command: alacrittyRepoURL, Dir: dir,
+			Fail: "Failed to clone Alacritty repository"},
+		stepAction{Kind: actionBuild, Log: "Building Alacritty (this may take 5-10 minutes)...", Command: shellQuote(cargo) + " build --release", Dir: dir,
+			Fail: "Failed to build Alacritty"},
+		stepAction{Kind: actionRun, Log: "Installing Alacritty binary...", Sudo: true,
+			Command: fmt.Sprintf("cp %s /usr/local/bin/alacritty", shellQuote(filepath.Join(dir, "target/release/alacritty"))),
+			Fail:    "Failed to install Alacritty binary"},
+		stepAction{Kind: actionRun, Sudo: true, Optional: true,
+			Command: fmt.Sprintf("cp %s /usr/share/applications/", shellQuote(filepath.Join(dir, "extra/linux/Alacritty.desktop"))),
+			Fail:    "Could not install the Alacritty desktop entry"},
+		stepAction{Kind: actionRun, Command: "rm -rf " + shellQuote(dir), Optional: true,
+			Fail: "Could not remove " + dir},
+		stepAction{Kind: actionNote, Log: "✓ Alacritty built and installed from source"},
+	), nil
 }
 
 func stepInstallFont(m *Model) error {
 	homeDir := os.Getenv("HOME")
 	stepID := "font"
+	font := selectedFont(m.Choices)
+	version := selectedFontVersion(m.Choices)
+	stepName := "Install " + font.Label
 
 	// Termux: fonts work differently - copy to ~/.termux/font.ttf
 	isTermux := m.SystemInfo.IsTermux || m.Choices.OS == "termux"
 	if isTermux {
-		SendLog(stepID, "Downloading JetBrainsMono Nerd Font for Termux...")
+		SendLog(stepID, fmt.Sprintf("Downloading %s %s for Termux...", font.Label, version))
 		termuxDir := filepath.Join(homeDir, ".termux")
 		if err := system.EnsureDir(termuxDir); err != nil {
-			return wrapStepError("font", "Install Nerd Font",
+			return wrapStepError("font", stepName,
 				"Failed to create .termux directory",
 				err)
 		}
 
-		// Download a single TTF file for Termux
-		result := system.RunWithLogs(fmt.Sprintf("curl -fsSL -o %s/font.ttf https://github.com/ryanoasis/nerd-fonts/raw/HEAD/patched-fonts/JetBrainsMono/Ligatures/Regular/JetBrainsMonoNerdFont-Regular.ttf", termuxDir), nil, func(line string) {
-			SendLog(stepID, line)
-		})
-		if result.Error != nil {
-			return wrapStepError("font", "Install Nerd Font",
+		// Termux uses a single TTF, taken from the verified release archive
+		archive := filepath.Join(os.TempDir(), font.Archive+".zip")
+		defer os.Remove(archive)
+		if err := downloadArtifact(stepID, nerdFontArtifact(version, font.Archive), archive); err != nil {
+			return wrapStepError("font", stepName,
 				"Failed to download font. Check your internet connection.",
-				result.Error)
+				err)
+		}
+		if err := system.ExtractZipFile(archive, font.TTF, filepath.Join(termuxDir, "font.ttf")); err != nil {
+			return wrapStepError("font", stepName,
+				"Failed to extract font from archive",
+				err)
 		}
 
 		SendLog(stepID, "Reloading Termux settings...")
@@ -548,62 +566,111 @@ func stepInstallFont(m *Model) error {
 	}
 
 	if m.SystemInfo.OS == system.OSMac {
-		SendLog(stepID, "Installing Iosevka Term Nerd Font...")
-		result := system.RunBrewWithLogs("install --cask font-iosevka-term-nerd-font", nil, func(line string) {
+		SendLog(stepID, fmt.Sprintf("Installing %s...", font.Label))
+		result := system.RunBrewWithLogs("install --cask "+font.Cask, nil, func(line string) {
 			SendLog(stepID, line)
 		})
 		if result.Error != nil {
-			return wrapStepError("font", "Install Iosevka Nerd Font",
+			return wrapStepError("font", stepName,
 				"Failed to install font via Homebrew. Try installing manually from https://www.nerdfonts.com/",
 				result.Error)
 		}
-		SendLog(stepID, "✓ Font installed")
-		return nil
+		return finishFontStep(stepID, m)
 	}
 
-	// Linux
+	// Linux: only the needed faces, in a directory of their own
 	fontDir := filepath.Join(homeDir, ".local/share/fonts")
-	SendLog(stepID, "Creating fonts directory...")
-	if err := system.EnsureDir(fontDir); err != nil {
-		return wrapStepError("font", "Install Iosevka Nerd Font",
-			"Failed to create fonts directory",
-			err)
+	installDir := fontInstallDir(font)
+
+	if !system.CommandExists("fc-cache") {
+		SendLog(stepID, "Installing fontconfig...")
+		result := installPlatformPackages(m, stepID, platformPackages{
+			Brew:   "fontconfig",
+			Arch:   "fontconfig",
+			Fedora: "fontconfig",
+			Debian: "fontconfig",
+			SUSE:   "fontconfig",
+			Alpine: "fontconfig",
+			Void:   "fontconfig",
+		}, func(line string) {
+			SendLog(stepID, line)
+		})
+		if result.Error != nil {
+			return wrapStepError("font", stepName,
+				"Failed to install fontconfig",
+				result.Error)
+		}
 	}
 
-	SendLog(stepID, "Downloading Iosevka Term Nerd Font...")
-	result := system.RunWithLogs(fmt.Sprintf("curl -fsSL -o %s/IosevkaTerm.zip https://github.com/ryanoasis/nerd-fonts/releases/download/v3.3.0/IosevkaTerm.zip", fontDir), nil, func(line string) {
-		SendLog(stepID, line)
-	})
-	if result.Error != nil {
-		return wrapStepError("font", "Install Iosevka Nerd Font",
+	SendLog(stepID, fmt.Sprintf("Downloading %s %s...", font.Label, version))
+	archive := filepath.Join(os.TempDir(), font.Archive+".zip")
+	defer os.Remove(archive)
+	if err := downloadArtifact(stepID, nerdFontArtifact(version, font.Archive), archive); err != nil {
+		return wrapStepError("font", stepName,
 			"Failed to download font. Check your internet connection.",
-			result.Error)
+			err)
 	}
 
-	SendLog(stepID, "Extracting font archive...")
-	result = system.RunWithLogs(fmt.Sprintf("unzip -o %s/IosevkaTerm.zip -d %s/", fontDir, fontDir), nil, func(line string) {
-		SendLog(stepID, line)
-	})
-	if result.Error != nil {
-		return wrapStepError("font", "Install Iosevka Nerd Font",
+	SendLog(stepID, fmt.Sprintf("Extracting font files into %s...", installDir))
+	installed, err := extractFontFaces(archive, font, installDir)
+	if err != nil {
+		return wrapStepError("font", stepName,
 			"Failed to extract font archive",
-			result.Error)
+			err)
 	}
+	if err := os.WriteFile(filepath.Join(installDir, fontVersionFile), []byte(version+"\n"), 0644); err != nil {
+		return wrapStepError("font", stepName,
+			"Failed to record the installed nerd-fonts release",
+			err)
+	}
+	for _, name := range installed {
+		SendLog(stepID, "  "+name)
+	}
+	removeLegacyFontFiles(stepID, fontDir, font)
 
 	SendLog(stepID, "Updating font cache...")
-	system.RunWithLogs("fc-cache -fv", nil, func(line string) {
+	system.RunWithLogs("fc-cache -f "+shellQuote(fontDir), nil, func(line string) {
 		SendLog(stepID, line)
 	})
+	if result := system.Run("fc-list : family file", nil); result.Error != nil || !fontRegistered(result.Output, installDir, font) {
+		return wrapStepError("font", stepName,
+			fmt.Sprintf("fontconfig does not list %s after installation. Run `fc-cache -fv` and check `fc-list | grep %q`.", font.Family, font.Archive),
+			fmt.Errorf("%s not registered with fontconfig", font.Family))
+	}
+	SendLog(stepID, fmt.Sprintf("✓ fontconfig lists %s", font.Family))
+	return finishFontStep(stepID, m)
+}
+
+// finishFontStep switches the chosen terminal over to the installed font
+func finishFontStep(stepID string, m *Model) error {
+	if err := applyTerminalFont(stepID, m.Choices); err != nil {
+		return wrapStepError("font", "Install "+selectedFont(m.Choices).Label,
+			"Font installed, but the terminal configuration could not be updated",
+			err)
+	}
 	SendLog(stepID, "✓ Font installed")
 	return nil
 }
 
+// usesNativePackageManager reports whether the distro installs everything
+// through its own package manager instead of Homebrew
+func usesNativePackageManager(osType system.OSType) bool {
+	switch osType {
+	case system.OSArch, system.OSFedora, system.OSSUSE, system.OSAlpine, system.OSVoid:
+		return true
+	}
+	return false
+}
+
 type platformPackages struct {
 	Termux string
 	Brew   string
 	Arch   string
 	Fedora string
 	Debian string
+	SUSE   string
+	Alpine string
+	Void   string
 }
 
 var (
@@ -620,6 +687,15 @@ func installPlatformPackages(m *Model, stepID string, packages platformPackages,
 		return runNativeWithBrewFallback("pacman -S --needed --noconfirm "+packages.Arch, packages.Brew, m.SystemInfo.HasBrew, onLog)
 	case m.SystemInfo.OS == system.OSFedora && packages.Fedora != "":
 		return runNativeWithBrewFallback("dnf install -y "+packages.Fedora, packages.Brew, m.SystemInfo.HasBrew, onLog)
+	case m.SystemInfo.OS == system.OSSUSE && packages.SUSE != "":
+		return runNativeWithBrewFallback("zypper --non-interactive install "+packages.SUSE, packages.Brew, m.SystemInfo.HasBrew, onLog)
+	case m.SystemInfo.OS == system.OSAlpine && packages.Alpine != "":
+		// Homebrew does not support musl, so apk is the only option
+		return runSudoWithLogs("apk add --no-cache "+packages.Alpine, nil, onLog)
+	case m.SystemInfo.OS == system.OSVoid && packages.Void != "":
+		return runNativeWithBrewFallback("xbps-install -y "+packages.Void, packages.Brew, m.SystemInfo.HasBrew, onLog)
+	case m.SystemInfo.DistroFamily == system.FamilyNixOS:
+		return &system.ExecResult{Error: errNixOSImperative}
 	case (m.SystemInfo.OS == system.OSDebian || m.SystemInfo.OS == system.OSLinux) && !m.SystemInfo.HasBrew && packages.Debian != "":
 		return runSudoWithLogs("apt-get install -y "+packages.Debian, nil, onLog)
 	default:
@@ -641,66 +717,9 @@ func runNativeWithBrewFallback(nativeCommand string, brewPackages string, hasBre
 	return runBrewWithLogs("install "+brewPackages, nil, onLog)
 }
 
-func installHerdrBinary(m *Model, stepID string) error {
-	if system.CommandExists("herdr") {
-		SendLog(stepID, "Herdr already installed")
-		return nil
-	}
-	if m.SystemInfo.IsTermux {
-		return fmt.Errorf("herdr is not available through the Termux package installer")
-	}
-	if m.SystemInfo.OS == system.OSMac || m.SystemInfo.HasBrew {
-		result := system.RunBrewWithLogs("install herdr", nil, func(line string) {
-			SendLog(stepID, line)
-		})
-		return result.Error
-	}
-
-	assetArch := ""
-	expectedSHA256 := ""
-	switch runtime.GOARCH {
-	case "amd64":
-		assetArch = "x86_64"
-		expectedSHA256 = "b965acaffc2c22f54b6e6c64af7cf8e98a3f4ac2622630a0599c67a4b9d8a654"
-	case "arm64":
-		assetArch = "aarch64"
-		expectedSHA256 = "3d757ac30c631e79dc45038c3ecc6423fe13a89f9cffa0f415aedd2c27f1576c"
-	default:
-		return fmt.Errorf("unsupported Herdr architecture: %s", runtime.GOARCH)
-	}
-
-	homeDir := os.Getenv("HOME")
-	binDir := filepath.Join(homeDir, ".local", "bin")
-	if err := system.EnsureDir(binDir); err != nil {
-		return err
-	}
-
-	url := fmt.Sprintf("https://github.com/ogulcancelik/herdr/releases/download/v0.7.1/herdr-linux-%s", assetArch)
-	dest := filepath.Join(binDir, "herdr")
-	SendLog(stepID, "Downloading Herdr release binary...")
-	result := system.RunWithLogs(fmt.Sprintf("curl -fsSL %q -o %q", url, dest), nil, func(line string) {
-		SendLog(stepID, line)
-	})
-	if result.Error != nil {
-		return result.Error
-	}
-
-	data, err := os.ReadFile(dest)
-	if err != nil {
-		return err
-	}
-	actualSHA256 := sha256.Sum256(data)
-	if hex.EncodeToString(actualSHA256[:]) != expectedSHA256 {
-		os.Remove(dest)
-		return fmt.Errorf("Herdr checksum mismatch for %s", url)
-	}
-
-	return os.Chmod(dest, 0755)
-}
-
 func stepInstallShell(m *Model) error {
 	homeDir := os.Getenv("HOME")
-	repoDir := "Gentleman.Dots"
+	repoDir := repoPath()
 	shell := m.Choices.Shell
 	stepID := "shell"
 
@@ -720,6 +739,9 @@ func stepInstallShell(m *Model) error {
 			Arch:   "fish carapace zoxide atuin starship",
 			Fedora: "fish carapace zoxide atuin starship",
 			Debian: "fish zoxide starship",
+			SUSE:   "fish zoxide atuin starship",
+			Alpine: "fish zoxide atuin starship",
+			Void:   "fish-shell zoxide atuin starship",
 		}, func(line string) {
 			SendLog(stepID, line)
 		})
@@ -729,41 +751,26 @@ func stepInstallShell(m *Model) error {
 				result.Error)
 		}
 		SendLog(stepID, "Copying Fish configuration...")
-		if err := system.CopyFile(filepath.Join(repoDir, "starship.toml"), filepath.Join(homeDir, ".config/starship.toml")); err != nil {
+		if err := deployFile(filepath.Join(repoDir, "starship.toml"), filepath.Join(homeDir, ".config/starship.toml")); err != nil {
 			return wrapStepError("shell", "Install Fish",
 				"Failed to copy starship configuration",
 				err)
 		}
-		if err := system.CopyDir(filepath.Join(repoDir, "GentlemanFish", "fish"), filepath.Join(homeDir, ".config", "fish")); err != nil {
+		if err := deployDir(filepath.Join(repoDir, "GentlemanFish", "fish"), filepath.Join(homeDir, ".config", "fish")); err != nil {
 			return wrapStepError("shell", "Install Fish",
 				"Failed to copy Fish configuration",
 				err)
 		}
 		// Patch config.fish based on WM choice
 		SendLog(stepID, "Configuring shell for window manager...")
-		if err := system.PatchFishForWM(filepath.Join(homeDir, ".config/fish/config.fish"), m.Choices.WindowMgr, m.Choices.InstallNvim); err != nil {
+		if err := patchShellForWM(stepID, "fish", m.Choices.WindowMgr, m.Choices.InstallNvim); err != nil {
 			return wrapStepError("shell", "Install Fish",
 				"Failed to configure config.fish for window manager",
 				err)
 		}
-		// Remove tmux.fish function if not using tmux
-		if m.Choices.WindowMgr != "tmux" {
-			os.Remove(filepath.Join(homeDir, ".config/fish/functions/tmux.fish"))
-		}
 		// Termux: Add fish to $PREFIX/etc/shells so tmux doesn't complain
 		if m.SystemInfo.IsTermux {
-			SendLog(stepID, "Adding fish to Termux shells...")
-			prefix := os.Getenv("PREFIX")
-			if prefix == "" {
-				prefix = "/data/data/com.termux/files/usr"
-			}
-			shellsFile := filepath.Join(prefix, "etc", "shells")
-			system.EnsureDir(filepath.Join(prefix, "etc"))
-			f, err := os.OpenFile(shellsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-			if err == nil {
-				f.WriteString(filepath.Join(prefix, "bin", "fish") + "\n")
-				f.Close()
-			}
+			registerTermuxShell(stepID, "fish")
 		}
 		SendLog(stepID, "✓ Fish shell configured")
 
@@ -775,6 +782,9 @@ func stepInstallShell(m *Model) error {
 			Arch:   "zsh carapace zoxide atuin zsh-autosuggestions zsh-syntax-highlighting zsh-autocomplete zsh-theme-powerlevel10k",
 			Fedora: "zsh carapace zoxide atuin zsh-autosuggestions zsh-syntax-highlighting starship",
 			Debian: "zsh zoxide starship zsh-autosuggestions zsh-syntax-highlighting",
+			SUSE:   "zsh zoxide atuin starship zsh-autosuggestions zsh-syntax-highlighting",
+			Alpine: "zsh zoxide atuin starship zsh-autosuggestions zsh-syntax-highlighting",
+			Void:   "zsh zoxide atuin starship zsh-autosuggestions zsh-syntax-highlighting",
 		}, func(line string) {
 			SendLog(stepID, line)
 		})
@@ -784,42 +794,31 @@ func stepInstallShell(m *Model) error {
 				result.Error)
 		}
 		SendLog(stepID, "Copying Zsh configuration...")
-		if err := system.CopyFile(filepath.Join(repoDir, "GentlemanZsh/.zshrc"), filepath.Join(homeDir, ".zshrc")); err != nil {
+		if err := deployFile(filepath.Join(repoDir, "GentlemanZsh/.zshrc"), filepath.Join(homeDir, ".zshrc")); err != nil {
 			return wrapStepError("shell", "Install Zsh",
 				"Failed to copy .zshrc configuration",
 				err)
 		}
 		// Patch .zshrc based on WM choice
 		SendLog(stepID, "Configuring shell for window manager...")
-		if err := system.PatchZshForWM(filepath.Join(homeDir, ".zshrc"), m.Choices.WindowMgr, m.Choices.InstallNvim); err != nil {
+		if err := patchShellForWM(stepID, "zsh", m.Choices.WindowMgr, m.Choices.InstallNvim); err != nil {
 			return wrapStepError("shell", "Install Zsh",
 				"Failed to configure .zshrc for window manager",
 				err)
 		}
-		if err := system.CopyFile(filepath.Join(repoDir, "GentlemanZsh/.p10k.zsh"), filepath.Join(homeDir, ".p10k.zsh")); err != nil {
+		if err := deployFile(filepath.Join(repoDir, "GentlemanZsh/.p10k.zsh"), filepath.Join(homeDir, ".p10k.zsh")); err != nil {
 			return wrapStepError("shell", "Install Zsh",
 				"Failed to copy Powerlevel10k configuration",
 				err)
 		}
-		if err := system.CopyDir(filepath.Join(repoDir, "GentlemanZsh", ".oh-my-zsh"), filepath.Join(homeDir, ".oh-my-zsh")); err != nil {
+		if err := deployDir(filepath.Join(repoDir, "GentlemanZsh", ".oh-my-zsh"), filepath.Join(homeDir, ".oh-my-zsh")); err != nil {
 			return wrapStepError("shell", "Install Zsh",
 				"Failed to copy Oh-My-Zsh directory",
 				err)
 		}
 		// Termux: Add zsh to $PREFIX/etc/shells so tmux doesn't complain
 		if m.SystemInfo.IsTermux {
-			SendLog(stepID, "Adding zsh to Termux shells...")
-			prefix := os.Getenv("PREFIX")
-			if prefix == "" {
-				prefix = "/data/data/com.termux/files/usr"
-			}
-			shellsFile := filepath.Join(prefix, "etc", "shells")
-			system.EnsureDir(filepath.Join(prefix, "etc"))
-			f, err := os.OpenFile(shellsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-			if err == nil {
-				f.WriteString(filepath.Join(prefix, "bin", "zsh") + "\n")
-				f.Close()
-			}
+			registerTermuxShell(stepID, "zsh")
 		}
 		SendLog(stepID, "✓ Zsh configured with Powerlevel10k")
 
@@ -831,6 +830,9 @@ func stepInstallShell(m *Model) error {
 			Arch:   "nushell carapace zoxide atuin jq bash starship",
 			Fedora: "nushell carapace zoxide atuin jq bash starship",
 			Debian: "nushell zoxide jq bash starship",
+			SUSE:   "nushell zoxide atuin jq bash starship",
+			Alpine: "nushell zoxide atuin jq bash starship",
+			Void:   "nushell zoxide atuin jq bash starship",
 		}, func(line string) {
 			SendLog(stepID, line)
 		})
@@ -840,106 +842,290 @@ func stepInstallShell(m *Model) error {
 				result.Error)
 		}
 		SendLog(stepID, "Copying Nushell configuration...")
-		if err := system.CopyFile(filepath.Join(repoDir, "starship.toml"), filepath.Join(homeDir, ".config/starship.toml")); err != nil {
+		if err := deployFile(filepath.Join(repoDir, "starship.toml"), filepath.Join(homeDir, ".config/starship.toml")); err != nil {
 			return wrapStepError("shell", "Install Nushell",
 				"Failed to copy starship configuration",
 				err)
 		}
-		if err := system.CopyFile(filepath.Join(repoDir, "bash-env-json"), filepath.Join(homeDir, ".config/bash-env-json")); err != nil {
+		if err := deployFile(filepath.Join(repoDir, "bash-env-json"), filepath.Join(homeDir, ".config/bash-env-json")); err != nil {
 			return wrapStepError("shell", "Install Nushell",
 				"Failed to copy bash-env-json",
 				err)
 		}
-		if err := system.CopyFile(filepath.Join(repoDir, "bash-env.nu"), filepath.Join(homeDir, ".config/bash-env.nu")); err != nil {
+		if err := deployFile(filepath.Join(repoDir, "bash-env.nu"), filepath.Join(homeDir, ".config/bash-env.nu")); err != nil {
 			return wrapStepError("shell", "Install Nushell",
 				"Failed to copy bash-env.nu",
 				err)
 		}
 
-		var nuDir string
-		if runtime.GOOS == "darwin" {
-			nuDir = filepath.Join(homeDir, "Library/Application Support/nushell")
-		} else {
-			nuDir = filepath.Join(homeDir, ".config/nushell")
-		}
+		nuDir := nushellConfigDir(homeDir)
 		if err := system.EnsureDir(nuDir); err != nil {
 			return wrapStepError("shell", "Install Nushell",
 				"Failed to create Nushell config directory",
 				err)
 		}
-		if err := system.CopyDir(filepath.Join(repoDir, "GentlemanNushell"), nuDir); err != nil {
+		if err := deployDirRendering(filepath.Join(repoDir, "GentlemanNushell"), nuDir, "config.nu"); err != nil {
 			return wrapStepError("shell", "Install Nushell",
 				"Failed to copy Nushell configuration",
 				err)
 		}
 		// Patch config.nu based on WM choice
 		SendLog(stepID, "Configuring shell for window manager...")
-		if err := system.PatchNushellForWM(filepath.Join(nuDir, "config.nu"), m.Choices.WindowMgr); err != nil {
+		if err := patchShellForWM(stepID, "nushell", m.Choices.WindowMgr, m.Choices.InstallNvim); err != nil {
 			return wrapStepError("shell", "Install Nushell",
 				"Failed to configure config.nu for window manager",
 				err)
 		}
 		// Termux: Add nu to $PREFIX/etc/shells so tmux doesn't complain
 		if m.SystemInfo.IsTermux {
-			SendLog(stepID, "Adding nushell to Termux shells...")
-			prefix := os.Getenv("PREFIX")
-			if prefix == "" {
-				prefix = "/data/data/com.termux/files/usr"
-			}
-			shellsFile := filepath.Join(prefix, "etc", "shells")
-			system.EnsureDir(filepath.Join(prefix, "etc"))
-			f, err := os.OpenFile(shellsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-			if err == nil {
-				f.WriteString(filepath.Join(prefix, "bin", "nu") + "\n")
-				f.Close()
-			}
+			registerTermuxShell(stepID, "nu")
 		}
 		SendLog(stepID, "✓ Nushell configured")
+
+	case "bash":
+		SendLog(stepID, "Installing Bash and plugins...")
+		result := installPlatformPackages(m, stepID, platformPackages{
+			Termux: "bash bash-completion starship zoxide",
+			Brew:   "bash bash-completion@2 carapace zoxide atuin starship",
+			Arch:   "bash bash-completion carapace zoxide atuin starship",
+			Fedora: "bash bash-completion carapace zoxide atuin starship",
+			Debian: "bash bash-completion zoxide starship",
+			SUSE:   "bash bash-completion zoxide atuin starship",
+			Alpine: "bash bash-completion zoxide atuin starship",
+			Void:   "bash bash-completion zoxide atuin starship",
+		}, func(line string) {
+			SendLog(stepID, line)
+		})
+		if result.Error != nil {
+			return wrapStepError("shell", "Install Bash",
+				"Failed to install Bash and dependencies",
+				result.Error)
+		}
+		// atuin hooks into bash through bash-preexec, a sourced script
+		if system.CommandExists("atuin") {
+			SendLog(stepID, "Downloading bash-preexec for atuin...")
+			preexec := system.Artifact{URL: bashPreexecURL, SHA256: bashPreexecPinned[bashPreexecVersion]}
+			if err := downloadArtifact(stepID, preexec, filepath.Join(homeDir, ".bash-preexec.sh")); err != nil {
+				SendLog(stepID, fmt.Sprintf("Skipping bash-preexec (%v), atuin history will be disabled", err))
+			}
+		}
+		SendLog(stepID, "Copying Bash configuration...")
+		if err := deployFile(filepath.Join(repoDir, "starship.toml"), filepath.Join(homeDir, ".config/starship.toml")); err != nil {
+			return wrapStepError("shell", "Install Bash",
+				"Failed to copy starship configuration",
+				err)
+		}
+		if err := deployFile(filepath.Join(repoDir, "GentlemanBash/.bashrc"), filepath.Join(homeDir, ".bashrc")); err != nil {
+			return wrapStepError("shell", "Install Bash",
+				"Failed to copy .bashrc configuration",
+				err)
+		}
+		// Patch .bashrc based on WM choice
+		SendLog(stepID, "Configuring shell for window manager...")
+		if err := patchShellForWM(stepID, "bash", m.Choices.WindowMgr, m.Choices.InstallNvim); err != nil {
+			return wrapStepError("shell", "Install Bash",
+				"Failed to configure .bashrc for window manager",
+				err)
+		}
+		SendLog(stepID, "✓ Bash configured")
+	}
+
+	return nil
+}
+
+// bashPreexecVersion is the bash-preexec release tag atuin's bash hook comes from
+const bashPreexecVersion = "0.5.0"
+
+// bashPreexecURL is the preexec/precmd hook library atuin needs under bash
+const bashPreexecURL = "https://raw.githubusercontent.com/rcaloras/bash-preexec/" + bashPreexecVersion + "/bash-preexec.sh"
+
+// bashPreexecPinned are the SHA256 digests of bash-preexec.sh by release.
+// bash-preexec publishes no checksum, so an unpinned release is skipped;
+// add the digest when bumping bashPreexecVersion.
+var bashPreexecPinned = map[string]string{}
+
+// nerdFontsReleases is where nerd-fonts publishes each release
+const nerdFontsReleases = "https://github.com/ryanoasis/nerd-fonts/releases/download/"
+
+// nerdFontArtifact returns a release archive of a Nerd Font, checked against
+// nerdFontPinned or, with --allow-unpinned, the release's SHA-256.txt
+func nerdFontArtifact(version, archive string) system.Artifact {
+	release := nerdFontsReleases + version
+	asset := archive + ".zip"
+	if sum := nerdFontPinned[version].Assets[asset]; sum != "" {
+		return system.Artifact{URL: release + "/" + asset, SHA256: sum}
 	}
+	return system.Artifact{
+		URL:              release + "/" + asset,
+		ChecksumManifest: release + "/SHA-256.txt",
+	}
+}
+
+// downloadArtifact fetches a verified artifact, reporting progress on the step
+func downloadArtifact(stepID string, a system.Artifact, dest string) error {
+	if a.SHA256 == "" && (a.ChecksumManifest != "" || a.ReleaseAPI != "") && system.UnpinnedAllowed() {
+		SendLog(stepID, fmt.Sprintf("⚠️  %s has no pinned checksum; checking it against the one its release publishes (--allow-unpinned)", filepath.Base(a.URL)))
+	}
+	lastPercent := -1
+	return system.Download(a, dest, func(done, total int64) {
+		if total <= 0 {
+			return
+		}
+		if percent := int(done * 100 / total); percent != lastPercent {
+			lastPercent = percent
+			SendProgress(stepID, float64(done)/float64(total))
+		}
+	})
+}
+
+// Repositories cloned through the download cache (system.GitClone)
+const (
+	gentlemanRepoURL = "https://github.com/Gentleman-Programming/Gentleman.Dots.git"
+	tpmRepoURL       = "https://github.com/tmux-plugins/tpm"
+	alacrittyRepoURL = "https://github.com/alacritty/alacritty.git"
+)
+
+// Install scripts fetched with curl (routed through system.GitHubURL)
+const (
+	homebrewInstallURL      = "https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh"
+	ghosttyUbuntuInstallURL = "https://raw.githubusercontent.com/mkasberg/ghostty-ubuntu/HEAD/install.sh"
+)
 
+// registerTermuxShell lists a shell binary in $PREFIX/etc/shells once
+func registerTermuxShell(stepID, binary string) {
+	SendLog(stepID, fmt.Sprintf("Adding %s to Termux shells...", binary))
+	prefix := os.Getenv("PREFIX")
+	if prefix == "" {
+		prefix = "/data/data/com.termux/files/usr"
+	}
+	shellsFile := filepath.Join(prefix, "etc", "shells")
+	if err := system.AppendLineOnce(shellsFile, filepath.Join(prefix, "bin", binary)); err != nil {
+		SendLog(stepID, fmt.Sprintf("Could not update %s: %v", shellsFile, err))
+	}
+}
+
+// nushellConfigDir returns the platform-specific Nushell config directory
+func nushellConfigDir(homeDir string) string {
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(homeDir, "Library/Application Support/nushell")
+	}
+	return filepath.Join(homeDir, ".config/nushell")
+}
+
+// patchShellForWM points an installed shell config at the chosen multiplexer.
+// In link mode fish and zsh get an override file instead of an edited original.
+func patchShellForWM(stepID, shell, wm string, installNvim bool) error {
+	homeDir := os.Getenv("HOME")
+	switch shell {
+	case "fish":
+		if linkMode.enabled {
+			_, err := system.WriteOverride("config.fish", system.FishWMOverride(wm))
+			return err
+		}
+		if err := system.PatchFishForWM(filepath.Join(homeDir, ".config/fish/config.fish"), wm, installNvim); err != nil {
+			return err
+		}
+		// Remove tmux.fish function if not using tmux
+		if wm != "tmux" {
+			os.Remove(filepath.Join(homeDir, ".config/fish/functions/tmux.fish"))
+		}
+	case "zsh":
+		if linkMode.enabled {
+			_, err := system.WriteOverride("zshrc.zsh", system.ZshWMOverride(wm))
+			return err
+		}
+		return system.PatchZshForWM(filepath.Join(homeDir, ".zshrc"), wm, installNvim)
+	case "nushell":
+		return system.PatchNushellForWM(filepath.Join(nushellConfigDir(homeDir), "config.nu"), wm)
+	case "bash":
+		if linkMode.enabled {
+			// The zsh override is plain bash as well
+			_, err := system.WriteOverride("bashrc.sh", system.ZshWMOverride(wm))
+			return err
+		}
+		return system.PatchBashForWM(filepath.Join(homeDir, ".bashrc"), wm, installNvim)
+	}
 	return nil
 }
 
+// shellBinary maps a shell choice to its executable name
+func shellBinary(shell string) string {
+	if shell == "nushell" {
+		return "nu"
+	}
+	return shell
+}
+
+// resolveShellPath returns the full path of a shell binary, falling back to its name
+func resolveShellPath(m *Model, name string) string {
+	if m.SystemInfo.IsTermux {
+		// In Termux, construct the path directly (which command has issues)
+		prefix := os.Getenv("PREFIX")
+		if prefix == "" {
+			prefix = "/data/data/com.termux/files/usr"
+		}
+		return filepath.Join(prefix, "bin", name)
+	}
+	result := system.Run(fmt.Sprintf("which %s", name), nil)
+	if result.Error == nil && strings.TrimSpace(result.Output) != "" {
+		return strings.TrimSpace(result.Output)
+	}
+	return name
+}
+
+// setTmuxShell writes tmux's default-command/default-shell for shellPath
+func setTmuxShell(shellPath string) error {
+	if linkMode.enabled {
+		// The linked tmux.conf sources this override instead of being edited
+		_, err := system.WriteOverride("tmux.conf", system.TmuxShellOverride(shellPath))
+		return err
+	}
+	return system.SetTmuxDefaultShell(filepath.Join(os.Getenv("HOME"), ".tmux.conf"), shellPath)
+}
+
+// setZellijShell writes zellij's default_shell for the given shell binary
+func setZellijShell(shellName string) error {
+	return system.SetZellijDefaultShell(filepath.Join(os.Getenv("HOME"), ".config/zellij/config.kdl"), shellName)
+}
+
+// installWMBinary installs the multiplexer itself when it is not on PATH
+func installWMBinary(m *Model, stepID, wm string) error {
+	name := strings.ToUpper(wm[:1]) + wm[1:]
+	if system.CommandExists(wm) {
+		SendLog(stepID, name+" already installed")
+		return nil
+	}
+	SendLog(stepID, fmt.Sprintf("Installing %s...", name))
+	if wm == "herdr" {
+		return installHerdrBinary(m, stepID)
+	}
+	result := installPlatformPackages(m, stepID, platformPackages{
+		Termux: wm,
+		Brew:   wm,
+		Arch:   wm,
+		Fedora: wm,
+		Debian: wm,
+		SUSE:   wm,
+		Alpine: wm,
+		Void:   wm,
+	}, func(line string) {
+		SendLog(stepID, line)
+	})
+	return result.Error
+}
+
 func stepInstallWM(m *Model) error {
 	homeDir := os.Getenv("HOME")
-	repoDir := "Gentleman.Dots"
+	repoDir := repoPath()
 	wm := m.Choices.WindowMgr
 	stepID := "wm"
 
 	switch wm {
 	case "tmux":
-		if !system.CommandExists("tmux") {
-			SendLog(stepID, "Installing Tmux...")
-			result := installPlatformPackages(m, stepID, platformPackages{
-				Termux: "tmux",
-				Brew:   "tmux",
-				Arch:   "tmux",
-				Fedora: "tmux",
-				Debian: "tmux",
-			}, func(line string) {
-				SendLog(stepID, line)
-			})
-			if result.Error != nil {
-				return wrapStepError("wm", "Install Tmux",
-					"Failed to install Tmux",
-					result.Error)
-			}
-		} else {
-			SendLog(stepID, "Tmux already installed")
-		}
-
-		// TPM
-		tpmDir := filepath.Join(homeDir, ".tmux/plugins/tpm")
-		if _, err := os.Stat(tpmDir); os.IsNotExist(err) {
-			SendLog(stepID, "Cloning TPM (Tmux Plugin Manager)...")
-			result := system.RunWithLogs(fmt.Sprintf("git clone https://github.com/tmux-plugins/tpm %s", tpmDir), nil, func(line string) {
-				SendLog(stepID, line)
-			})
-			if result.Error != nil {
-				return wrapStepError("wm", "Install Tmux",
-					"Failed to clone TPM (Tmux Plugin Manager)",
-					result.Error)
-			}
+		if err := installWMBinary(m, stepID, "tmux"); err != nil {
+			return wrapStepError("wm", "Install Tmux",
+				"Failed to install Tmux",
+				err)
 		}
 
 		SendLog(stepID, "Copying Tmux configuration...")
@@ -948,12 +1134,7 @@ func stepInstallWM(m *Model) error {
 				"Failed to create .tmux directory",
 				err)
 		}
-		if err := system.CopyDir(filepath.Join(repoDir, "GentlemanTmux", "plugins"), filepath.Join(homeDir, ".tmux", "plugins")); err != nil {
-			return wrapStepError("wm", "Install Tmux",
-				"Failed to copy Tmux plugins",
-				err)
-		}
-		if err := system.CopyFile(filepath.Join(repoDir, "GentlemanTmux/tmux.conf"), filepath.Join(homeDir, ".tmux.conf")); err != nil {
+		if err := deployFile(filepath.Join(repoDir, "GentlemanTmux/tmux.conf"), filepath.Join(homeDir, ".tmux.conf")); err != nil {
 			return wrapStepError("wm", "Install Tmux",
 				"Failed to copy tmux.conf",
 				err)
@@ -961,71 +1142,30 @@ func stepInstallWM(m *Model) error {
 
 		// Configure tmux to use the user's chosen shell
 		SendLog(stepID, "Configuring tmux default shell...")
-		tmuxConfPath := filepath.Join(homeDir, ".tmux.conf")
-		shellName := ""
-		switch m.Choices.Shell {
-		case "fish":
-			shellName = "fish"
-		case "zsh":
-			shellName = "zsh"
-		case "nushell":
-			shellName = "nu"
-		}
-		if shellName != "" {
-			// Find the full path to the shell
-			shellFullPath := ""
-			if m.SystemInfo.IsTermux {
-				// In Termux, construct the path directly (which command has issues)
-				prefix := os.Getenv("PREFIX")
-				if prefix == "" {
-					prefix = "/data/data/com.termux/files/usr"
-				}
-				shellFullPath = filepath.Join(prefix, "bin", shellName)
-			} else {
-				result := system.Run(fmt.Sprintf("which %s", shellName), nil)
-				if result.Error == nil && result.Output != "" {
-					shellFullPath = strings.TrimSpace(result.Output)
-				}
-			}
-			if shellFullPath == "" {
-				shellFullPath = shellName // Fallback
-			}
-
-			// Replace placeholder in tmux.conf with actual shell config
-			content, err := os.ReadFile(tmuxConfPath)
-			if err == nil {
-				shellConfig := fmt.Sprintf("set -g default-command \"%s\"\nset -g default-shell \"%s\"", shellFullPath, shellFullPath)
-				newContent := strings.Replace(string(content), "# GENTLEMAN_DEFAULT_SHELL", shellConfig, 1)
-				os.WriteFile(tmuxConfPath, []byte(newContent), 0644)
+		if shellName := shellBinary(m.Choices.Shell); shellName != "" {
+			if err := setTmuxShell(resolveShellPath(m, shellName)); err != nil {
+				return wrapStepError("wm", "Install Tmux",
+					"Failed to set default shell in tmux.conf",
+					err)
 			}
 		}
 
-		// Install plugins
+		// Plugins, TPM included, at the commits pinned in the lock
 		SendLog(stepID, "Installing Tmux plugins...")
-		system.RunWithLogs(filepath.Join(homeDir, ".tmux/plugins/tpm/bin/install_plugins"), nil, func(line string) {
-			SendLog(stepID, line)
-		})
+		if failed, err := installTmuxPlugins(stepID); err != nil {
+			desc := "Failed to install Tmux plugins"
+			if len(failed) > 0 {
+				desc = "Failed to install Tmux plugins: " + strings.Join(failed, ", ")
+			}
+			return wrapStepError("wm", "Install Tmux", desc, err)
+		}
 		SendLog(stepID, "✓ Tmux configured")
 
 	case "zellij":
-		if !system.CommandExists("zellij") {
-			SendLog(stepID, "Installing Zellij...")
-			result := installPlatformPackages(m, stepID, platformPackages{
-				Termux: "zellij",
-				Brew:   "zellij",
-				Arch:   "zellij",
-				Fedora: "zellij",
-				Debian: "zellij",
-			}, func(line string) {
-				SendLog(stepID, line)
-			})
-			if result.Error != nil {
-				return wrapStepError("wm", "Install Zellij",
-					"Failed to install Zellij",
-					result.Error)
-			}
-		} else {
-			SendLog(stepID, "Zellij already installed")
+		if err := installWMBinary(m, stepID, "zellij"); err != nil {
+			return wrapStepError("wm", "Install Zellij",
+				"Failed to install Zellij",
+				err)
 		}
 
 		SendLog(stepID, "Copying Zellij configuration...")
@@ -1035,7 +1175,7 @@ func stepInstallWM(m *Model) error {
 				"Failed to create Zellij config directory",
 				err)
 		}
-		if err := system.CopyDir(filepath.Join(repoDir, "GentlemanZellij", "zellij"), zellijDir); err != nil {
+		if err := deployDirRendering(filepath.Join(repoDir, "GentlemanZellij", "zellij"), zellijDir, "config.kdl"); err != nil {
 			return wrapStepError("wm", "Install Zellij",
 				"Failed to copy Zellij configuration",
 				err)
@@ -1043,36 +1183,20 @@ func stepInstallWM(m *Model) error {
 
 		// Configure zellij to use the user's chosen shell
 		SendLog(stepID, "Configuring zellij default shell...")
-		zellijConfPath := filepath.Join(zellijDir, "config.kdl")
-		shellPath := ""
-		switch m.Choices.Shell {
-		case "fish":
-			shellPath = "fish"
-		case "zsh":
-			shellPath = "zsh"
-		case "nushell":
-			shellPath = "nu"
-		}
-		if shellPath != "" {
-			// Append default_shell config to zellij config.kdl
-			f, err := os.OpenFile(zellijConfPath, os.O_APPEND|os.O_WRONLY, 0644)
-			if err == nil {
-				f.WriteString(fmt.Sprintf("\n// Default shell (configured by Gentleman.Dots)\ndefault_shell \"%s\"\n", shellPath))
-				f.Close()
+		if shellName := shellBinary(m.Choices.Shell); shellName != "" {
+			if err := setZellijShell(shellName); err != nil {
+				return wrapStepError("wm", "Install Zellij",
+					"Failed to set default shell in config.kdl",
+					err)
 			}
 		}
 		SendLog(stepID, "✓ Zellij configured")
 
 	case "herdr":
-		if !system.CommandExists("herdr") {
-			SendLog(stepID, "Installing Herdr...")
-			if err := installHerdrBinary(m, stepID); err != nil {
-				return wrapStepError("wm", "Install Herdr",
-					"Failed to install Herdr",
-					err)
-			}
-		} else {
-			SendLog(stepID, "Herdr already installed")
+		if err := installWMBinary(m, stepID, "herdr"); err != nil {
+			return wrapStepError("wm", "Install Herdr",
+				"Failed to install Herdr",
+				err)
 		}
 
 		SendLog(stepID, "Copying Herdr configuration...")
@@ -1082,7 +1206,7 @@ func stepInstallWM(m *Model) error {
 				"Failed to create Herdr config directory",
 				err)
 		}
-		if err := system.CopyFile(filepath.Join(repoDir, "herdr", "config.toml"), filepath.Join(herdrDir, "config.toml")); err != nil {
+		if err := deployFile(filepath.Join(repoDir, "herdr", "config.toml"), filepath.Join(herdrDir, "config.toml")); err != nil {
 			return wrapStepError("wm", "Install Herdr",
 				"Failed to copy Herdr configuration",
 				err)
@@ -1100,7 +1224,7 @@ func stepInstallWM(m *Model) error {
 
 func stepInstallNvim(m *Model) error {
 	homeDir := os.Getenv("HOME")
-	repoDir := "Gentleman.Dots"
+	repoDir := repoPath()
 	stepID := "nvim"
 
 	// Obsidian path
@@ -1118,6 +1242,9 @@ func stepInstallNvim(m *Model) error {
 			Arch:   "nodejs npm",
 			Fedora: "nodejs npm",
 			Debian: "nodejs npm",
+			SUSE:   "nodejs-default npm-default",
+			Alpine: "nodejs npm",
+			Void:   "nodejs",
 		}, func(line string) {
 			SendLog(stepID, line)
 		})
@@ -1139,6 +1266,9 @@ func stepInstallNvim(m *Model) error {
 		Arch:   "neovim git gcc fzf fd ripgrep coreutils bat curl lazygit tree-sitter",
 		Fedora: "neovim git gcc fzf fd-find ripgrep coreutils bat curl lazygit tree-sitter-cli",
 		Debian: "neovim git gcc fzf fd-find ripgrep coreutils bat curl lazygit tree-sitter-cli",
+		SUSE:   "neovim git gcc fzf fd ripgrep coreutils bat curl lazygit tree-sitter",
+		Alpine: "neovim git gcc musl-dev fzf fd ripgrep coreutils bat curl lazygit tree-sitter-cli",
+		Void:   "neovim git gcc fzf fd ripgrep coreutils bat curl lazygit tree-sitter",
 	}, func(line string) {
 		SendLog(stepID, line)
 	})
@@ -1158,7 +1288,7 @@ func stepInstallNvim(m *Model) error {
 	}
 	// Copy nvim config directory
 	srcNvim := filepath.Join(repoDir, "GentlemanNvim", "nvim")
-	if err := system.CopyDir(srcNvim, nvimDir); err != nil {
+	if err := deployDir(srcNvim, nvimDir); err != nil {
 		return wrapStepError("nvim", "Install Neovim",
 			"Failed to copy Neovim configuration",
 			err)
@@ -1166,7 +1296,9 @@ func stepInstallNvim(m *Model) error {
 
 	// Install Claude Code CLI (optional, don't fail on error)
 	// Skip on Termux - Claude Code doesn't support Android
-	if !m.SystemInfo.IsTermux {
+	if err := system.CheckInstallScript("Claude Code"); err != nil {
+		SendLog(stepID, "Skipping Claude Code: "+err.Error())
+	} else if !m.SystemInfo.IsTermux {
 		SendLog(stepID, "Installing Claude Code CLI (optional)...")
 		system.RunWithLogs(`curl -fsSL https://claude.ai/install.sh | bash`, nil, func(line string) {
 			SendLog(stepID, line)
@@ -1178,7 +1310,9 @@ func stepInstallNvim(m *Model) error {
 
 	// Install OpenCode CLI (optional, don't fail on error)
 	// Skip on Termux - OpenCode doesn't support Android
-	if !m.SystemInfo.IsTermux {
+	if err := system.CheckInstallScript("OpenCode"); err != nil {
+		SendLog(stepID, "Skipping OpenCode: "+err.Error())
+	} else if !m.SystemInfo.IsTermux {
 		SendLog(stepID, "Installing OpenCode CLI (optional)...")
 		system.RunWithLogs(`curl -fsSL https://opencode.ai/install | bash`, nil, func(line string) {
 			SendLog(stepID, line)
@@ -1194,6 +1328,11 @@ func stepInstallNvim(m *Model) error {
 
 func stepCleanup(m *Model) error {
 	stepID := "cleanup"
+	if linkMode.enabled {
+		SendLog(stepID, fmt.Sprintf("Keeping linked repository at %s", repoPath()))
+		SendLog(stepID, "✓ Cleanup complete")
+		return nil
+	}
 	SendLog(stepID, "Removing temporary files...")
 	// Only remove the cloned repo - no sudo needed
 	result := system.Run("rm -rf Gentleman.Dots", nil)
@@ -1207,132 +1346,120 @@ func stepCleanup(m *Model) error {
 }
 
 // stepSetDefaultShell sets the selected shell as the user's default shell
-// In non-interactive mode, this handles Termux specially (via .bashrc)
-// and attempts to set the shell on other systems if possible
 func stepSetDefaultShell(m *Model) error {
-	stepID := "setshell"
-	shell := m.Choices.Shell
-	homeDir := os.Getenv("HOME")
+	p, err := setShellPlan(m)
+	if err != nil {
+		return err
+	}
+	return runPlan(p)
+}
 
-	var shellCmd string
+// setShellPlan makes the chosen shell the login shell. Termux has no chsh,
+// so ~/.bashrc starts the shell instead.
+func setShellPlan(m *Model) (*stepPlan, error) {
+	p := &stepPlan{ID: "setshell", Name: "Set Default Shell", Title: "🐚 Setting your default shell..."}
+	shell := m.Choices.Shell
 	switch shell {
-	case "fish":
-		shellCmd = "fish"
-	case "zsh":
-		shellCmd = "zsh"
-	case "nushell":
-		shellCmd = "nu"
+	case "fish", "zsh", "nushell", "bash":
 	default:
-		SendLog(stepID, fmt.Sprintf("Unknown shell: %s, skipping", shell))
-		return nil
+		p.Actions = []stepAction{{Kind: actionNote, Log: fmt.Sprintf("Unknown shell: %s, skipping", shell)}}
+		return p, nil
+	}
+	bashrcPath := filepath.Join(os.Getenv("HOME"), ".bashrc")
+
+	// Termux already starts bash: just drop any auto-start of another shell
+	if m.SystemInfo.IsTermux && shell == "bash" {
+		p.Actions = []stepAction{{Kind: actionPatch,
+			Apply: func() error {
+				return system.RemoveBlockFile(bashrcPath, system.SyntaxSh, system.BlockShellAutoStart)
+			},
+			Fail: "Failed to remove shell auto-start from ~/.bashrc",
+			OK:   "✓ Bash is Termux's default shell"}}
+		return p, nil
 	}
 
-	// Termux: no chsh available, modify .bashrc to auto-start shell
-	if m.SystemInfo.IsTermux {
-		SendLog(stepID, "Configuring shell auto-start for Termux...")
-
-		// Find the shell path
-		shellPath := system.Run(fmt.Sprintf("which %s", shellCmd), nil)
-		if shellPath.Error != nil || strings.TrimSpace(shellPath.Output) == "" {
-			SendLog(stepID, fmt.Sprintf("Shell '%s' not found in PATH, skipping", shellCmd))
-			return nil
-		}
-		shellPathStr := strings.TrimSpace(shellPath.Output)
-
-		// Read existing .bashrc
-		bashrcPath := filepath.Join(homeDir, ".bashrc")
-		existingContent := ""
-		if data, err := os.ReadFile(bashrcPath); err == nil {
-			existingContent = string(data)
-		}
-
-		// Check if already configured
-		if strings.Contains(existingContent, "# Gentleman.Dots shell auto-start") {
-			SendLog(stepID, "Shell auto-start already configured in ~/.bashrc")
-			return nil
-		}
-
-		// Append auto-start configuration
-		autoStartConfig := fmt.Sprintf(`
-# Gentleman.Dots shell auto-start
-if [ -x "%s" ] && [ -z "$GENTLEMANDOTS_SHELL_STARTED" ]; then
-    export GENTLEMANDOTS_SHELL_STARTED=1
-    exec %s
-fi
-`, shellPathStr, shellPathStr)
-
-		f, err := os.OpenFile(bashrcPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			return wrapStepError("setshell", "Set Default Shell",
-				"Failed to open ~/.bashrc for writing",
-				err)
-		}
-		defer f.Close()
-
-		if _, err := f.WriteString(autoStartConfig); err != nil {
-			return wrapStepError("setshell", "Set Default Shell",
-				"Failed to write shell auto-start to ~/.bashrc",
-				err)
-		}
+	// WezTerm starts the shell itself and may multiplex on Ctrl+a
+	p.Actions = append(p.Actions, stepAction{Kind: actionPatch,
+		Apply: func() error { return applyWeztermChoices(m, p.ID) },
+		Fail:  "Failed to update the WezTerm config"})
 
-		SendLog(stepID, fmt.Sprintf("✓ Configured %s to auto-start in ~/.bashrc", shell))
-		SendLog(stepID, "Close and reopen Termux for changes to take effect")
-		return nil
+	shellPath := loginShellPath(m, shellBinary(shell))
+	if shellPath == "" {
+		p.Actions = append(p.Actions, stepAction{Kind: actionNote, Log: fmt.Sprintf("Shell '%s' not found in PATH, skipping", shellBinary(shell))})
+		return p, nil
 	}
 
-	// Non-Termux: Try to set shell using sudo usermod (works if NOPASSWD configured)
-	// Find the shell path first
-	shellPath := system.Run(fmt.Sprintf("which %s", shellCmd), nil)
-	if shellPath.Error != nil || strings.TrimSpace(shellPath.Output) == "" {
-		SendLog(stepID, fmt.Sprintf("Shell '%s' not found in PATH, skipping", shellCmd))
-		return nil
+	if m.SystemInfo.IsTermux {
+		p.Actions = []stepAction{{Kind: actionPatch, Log: "Configuring shell auto-start for Termux...",
+			Apply: func() error {
+				return system.SetTermuxAutoStart(bashrcPath, shellPath)
+			},
+			Fail: "Failed to write shell auto-start to ~/.bashrc",
+			OK:   fmt.Sprintf("✓ Configured %s to auto-start in ~/.bashrc", shell)}}
+		p.Done = []string{"Close and reopen Termux for changes to take effect"}
+		return p, nil
 	}
-	shellPathStr := strings.TrimSpace(shellPath.Output)
 
-	// Get current username
-	currentUser := os.Getenv("USER")
+	currentUser := currentUserName()
 	if currentUser == "" {
-		currentUser = os.Getenv("LOGNAME")
+		p.Actions = append(p.Actions, stepAction{Kind: actionNote, Log: "Could not determine current user, skipping shell change"})
+		return p, nil
 	}
-	if currentUser == "" {
-		// Fallback to whoami command (useful in Docker containers)
-		whoamiResult := system.Run("whoami", nil)
-		if whoamiResult.Error == nil {
-			currentUser = strings.TrimSpace(whoamiResult.Output)
-		}
+	if !shellListed(shellPath) {
+		p.Actions = append(p.Actions, stepAction{Kind: actionRun, Sudo: true, Optional: true,
+			Log:     fmt.Sprintf("Adding %s to /etc/shells...", shellPath),
+			Command: "sh -c " + shellQuote(fmt.Sprintf("echo %s >> /etc/shells", shellQuote(shellPath))),
+			Fail:    fmt.Sprintf("Could not add %s to /etc/shells (may need manual setup)", shellPath)})
 	}
-	if currentUser == "" {
-		SendLog(stepID, "Could not determine current user, skipping shell change")
-		return nil
+	// usermod is more reliable than chsh in scripts; chsh is the fallback
+	change := fmt.Sprintf("usermod -s %s %s || chsh -s %s %s",
+		shellQuote(shellPath), shellQuote(currentUser), shellQuote(shellPath), shellQuote(currentUser))
+	p.Actions = append(p.Actions, stepAction{Kind: actionRun, Sudo: true, Optional: true,
+		Log:     fmt.Sprintf("Setting %s as default shell for %s...", shell, currentUser),
+		Command: "sh -c " + shellQuote(change),
+		Fail:    fmt.Sprintf("Could not set default shell automatically. Run manually: chsh -s %s", shellPath),
+		OK:      fmt.Sprintf("✓ Default shell set to %s", shell)})
+	p.Done = []string{"Log out and log back in for changes to take effect"}
+	return p, nil
+}
+
+// loginShellPath finds the shell binary, including Homebrew's bin which may
+// not be on the installer's PATH yet; "" when it is not installed
+func loginShellPath(m *Model, name string) string {
+	path := resolveShellPath(m, name)
+	if filepath.IsAbs(path) && system.FileExists(path) {
+		return path
 	}
+	if path := filepath.Join(system.GetBrewPrefix(), "bin", name); !m.SystemInfo.IsTermux && system.FileExists(path) {
+		return path
+	}
+	return ""
+}
 
-	// First, ensure shell is in /etc/shells
-	SendLog(stepID, fmt.Sprintf("Adding %s to /etc/shells if needed...", shellPathStr))
-	checkShells := system.Run(fmt.Sprintf("grep -q '^%s$' /etc/shells", shellPathStr), nil)
-	if checkShells.Error != nil {
-		// Shell not in /etc/shells, try to add it
-		addResult := system.RunSudo(fmt.Sprintf("sh -c 'echo \"%s\" >> /etc/shells'", shellPathStr), nil)
-		if addResult.Error != nil {
-			SendLog(stepID, fmt.Sprintf("Could not add %s to /etc/shells (may need manual setup)", shellPathStr))
+// currentUserName returns the login name, falling back to whoami (useful in
+// Docker containers)
+func currentUserName() string {
+	for _, key := range []string{"USER", "LOGNAME"} {
+		if name := os.Getenv(key); name != "" {
+			return name
 		}
 	}
+	if result := system.Run("whoami", nil); result.Error == nil {
+		return strings.TrimSpace(result.Output)
+	}
+	return ""
+}
 
-	// Try sudo usermod first (more reliable than chsh in scripts)
-	SendLog(stepID, fmt.Sprintf("Setting %s as default shell for %s...", shell, currentUser))
-	result := system.RunSudo(fmt.Sprintf("usermod -s %s %s", shellPathStr, currentUser), nil)
-	if result.Error != nil {
-		// usermod failed, try chsh as fallback
-		SendLog(stepID, "usermod failed, trying chsh...")
-		result = system.RunSudo(fmt.Sprintf("chsh -s %s %s", shellPathStr, currentUser), nil)
-		if result.Error != nil {
-			// Both failed - not critical, just inform user
-			SendLog(stepID, fmt.Sprintf("Could not set default shell automatically"))
-			SendLog(stepID, fmt.Sprintf("Run manually: chsh -s %s", shellPathStr))
-			return nil
+// shellListed reports whether /etc/shells already lists path
+func shellListed(path string) bool {
+	data, err := os.ReadFile("/etc/shells")
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == path {
+			return true
 		}
 	}
-
-	SendLog(stepID, fmt.Sprintf("✓ Default shell set to %s", shell))
-	SendLog(stepID, "Log out and log back in for changes to take effect")
-	return nil
+	return false
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,20 +22,24 @@ fi`
 type needsExecProcessMsg struct {
 	stepID string
 	cmd    *exec.Cmd
+	finish func() error // in-process remainder of the step, run after cmd succeeds
 }
 
 // runInteractiveStep creates a tea.Cmd that runs an interactive step
 // This suspends the TUI and gives full terminal control to the process
 func runInteractiveStep(stepID string, m *Model) tea.Cmd {
 	return func() tea.Msg {
-		script, err := getInteractiveScript(stepID, m)
+		script, finish, err := prepareInteractiveStep(stepID, m)
 		if err != nil {
 			return execFinishedMsg{stepID: stepID, err: fmt.Errorf("failed to get script for %s: %w", stepID, err)}
 		}
 
-		// If no script needed (e.g., already installed), just succeed
+		// If no script needed (e.g., already installed), just finish in-process
 		if script == "" {
-			return execFinishedMsg{stepID: stepID, err: nil}
+			if finish != nil {
+				err = finish()
+			}
+			return execFinishedMsg{stepID: stepID, err: err}
 		}
 
 		cmd, err := createTempScriptCommand(script)
@@ -42,25 +48,52 @@ func runInteractiveStep(stepID string, m *Model) tea.Cmd {
 		}
 
 		// Return message that tells Update to use tea.ExecProcess
-		return needsExecProcessMsg{stepID: stepID, cmd: cmd}
+		return needsExecProcessMsg{stepID: stepID, cmd: cmd, finish: finish}
 	}
 }
 
-// getInteractiveScript returns the bash script for interactive steps only
-// Interactive steps are those that NEED user input (sudo password, chsh, etc)
-func getInteractiveScript(stepID string, m *Model) (string, error) {
-	switch stepID {
-	case "homebrew":
-		return getHomebrewScript(m)
-	case "deps":
-		return getDepsScript(m)
-	case "terminal":
-		return getTerminalScript(m)
-	case "setshell":
-		return getSetShellScript(m)
-	default:
-		return "", fmt.Errorf("unknown interactive step: %s", stepID)
+// prepareInteractiveStep returns the script for the part of an interactive
+// step that needs the terminal (sudo password, chsh, etc). Actions before it
+// run in-process right away; finish runs the ones after it.
+func prepareInteractiveStep(stepID string, m *Model) (script string, finish func() error, err error) {
+	if stepID == "homebrew" {
+		script, err = getHomebrewScript(m)
+		return script, nil, err
 	}
+	p, err := planStep(stepID, m)
+	if err != nil {
+		return "", nil, err
+	}
+	before, inScript, after := splitPlan(p)
+	if err := runActions(p, before); err != nil {
+		return "", nil, err
+	}
+	finish = func() error {
+		if err := runActions(p, after); err != nil {
+			return err
+		}
+		for _, line := range p.Done {
+			SendLog(p.ID, line)
+		}
+		return nil
+	}
+	if len(inScript) == 0 {
+		return "", finish, nil
+	}
+	// Clones inside the script go through the download cache like runActions'
+	for _, a := range inScript {
+		if a.Kind == actionFetch && system.CommandExists("git") {
+			if err := system.SyncGitMirror(a.Command, func(line string) { SendLog(p.ID, line) }); err != nil {
+				SendLog(p.ID, "Warning: could not cache "+a.Command+", the script clones it directly")
+			}
+		}
+	}
+	var done []string
+	if len(after) == 0 {
+		done = p.Done
+	}
+	script, err = renderScript(p, inScript, done)
+	return script, finish, err
 }
 
 // getHomebrewScript returns script to install Homebrew (needs password on first install)
@@ -68,27 +101,32 @@ func getHomebrewScript(m *Model) (string, error) {
 	if system.CommandExists("brew") {
 		return "", nil // Already installed
 	}
+	if err := system.CheckInstallScript("Homebrew"); err != nil {
+		return "", err
+	}
 
 	brewPrefix := system.GetBrewPrefix()
+	blockBegin, blockEnd := system.BlockMarkers(system.SyntaxSh, system.BlockHomebrew)
 	script := fmt.Sprintf(`#!/bin/bash
 set -e
 echo ""
 echo "🍺 Installing Homebrew package manager..."
 echo "   (You may be prompted for your password)"
 echo ""
-NONINTERACTIVE=1 /bin/bash -c "$(curl -fsSL https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh)"
+NONINTERACTIVE=1 /bin/bash -c "$(curl -fsSL %s)"
 
 echo ""
 echo "📝 Configuring shell to use Homebrew..."
 
 # Add to shell configs
 BREW_CONFIG='eval "$(%s/bin/brew shellenv)"'
+BLOCK_BEGIN='%s'
+BLOCK_END='%s'
 
 for RC_FILE in "$HOME/.bashrc" "$HOME/.zshrc"; do
-    if [ -f "$RC_FILE" ]; then
+    if [ -f "$RC_FILE" ] && [ ! -L "$RC_FILE" ]; then
         if ! grep -q "brew shellenv" "$RC_FILE" 2>/dev/null; then
-            echo "" >> "$RC_FILE"
-            echo "$BREW_CONFIG" >> "$RC_FILE"
+            printf '\n%%s\n%%s\n%%s\n' "$BLOCK_BEGIN" "$BREW_CONFIG" "$BLOCK_END" >> "$RC_FILE"
         fi
     fi
 done
@@ -100,299 +138,46 @@ eval "$(%s/bin/brew shellenv)"
 	echo "✅ Homebrew installed successfully!"
 	echo ""
 	%s
-	`, brewPrefix, brewPrefix, interactiveContinuePrompt)
+	`, system.GitHubURL(homebrewInstallURL), brewPrefix, blockBegin, blockEnd, brewPrefix, interactiveContinuePrompt)
 
 	return script, nil
 }
 
-// getDepsScript returns script to install dependencies on Linux (needs sudo)
-func getDepsScript(m *Model) (string, error) {
-	var script string
-
-	if m.SystemInfo.OS == system.OSArch {
-		script = `#!/bin/sh
-set -e
-echo ""
-echo "🔄 Updating Arch Linux packages..."
-echo "   (You may be prompted for your password)"
-echo ""
-sudo pacman -Syu --noconfirm
-echo ""
-echo "📦 Installing base dependencies..."
-sudo pacman -S --needed --noconfirm base-devel curl file git wget unzip fontconfig
-echo ""
-echo "✅ Dependencies installed successfully!"
-echo ""
-echo "Press Enter to continue..."
-read dummy
-`
-	} else if m.SystemInfo.OS == system.OSFedora {
-		// Fedora/RHEL
-		script = `#!/bin/sh
-set -e
-echo ""
-echo "🔄 Checking for Fedora/RHEL updates..."
-echo "   (You may be prompted for your password)"
-echo ""
-sudo dnf check-update || true
-echo ""
-echo "📦 Installing base dependencies..."
-sudo dnf install -y @development-tools curl file git wget unzip fontconfig
-echo ""
-echo "✅ Dependencies installed successfully!"
-echo ""
-echo "Press Enter to continue..."
-read dummy
-`
-	} else {
-		// Debian/Ubuntu
-		script = `#!/bin/sh
-set -e
-echo ""
-echo "🔄 Updating apt package list..."
-echo "   (You may be prompted for your password)"
-echo ""
-sudo apt-get update
-echo ""
-echo "📦 Installing base dependencies..."
-sudo apt-get install -y build-essential curl file git unzip fontconfig procps
-echo ""
-echo "✅ Dependencies installed successfully!"
-echo ""
-echo "Press Enter to continue..."
-read dummy
-`
+// deployConfigCommand returns the shell snippet that copies (or links) a config file
+func deployConfigCommand(src, dst string) string {
+	if linkMode.enabled {
+		return fmt.Sprintf(`mkdir -p %s
+ln -sfn %s %s`, shellQuote(filepath.Dir(dst)), shellQuote(src), shellQuote(dst))
 	}
-
-	return script, nil
+	return fmt.Sprintf(`mkdir -p %s
+cp %s %s`, shellQuote(filepath.Dir(dst)), shellQuote(src), shellQuote(dst))
 }
 
-// getTerminalScript returns script to install terminal on Linux (needs sudo)
-func getTerminalScript(m *Model) (string, error) {
-	terminal := m.Choices.Terminal
-	homeDir := os.Getenv("HOME")
-
-	var installCmd string
-	var configCmd string
-
-	switch terminal {
-	case "alacritty":
-		if system.CommandExists("alacritty") {
-			installCmd = `echo "✓ Alacritty already installed"`
-		} else if m.SystemInfo.OS == system.OSArch {
-			installCmd = `sudo pacman -S --noconfirm alacritty`
-		} else if m.SystemInfo.OS == system.OSFedora {
-			installCmd = `sudo dnf install -y alacritty`
-		} else {
-			// Debian/Ubuntu: compile from source (PPAs are unreliable)
-			installCmd = `echo "📦 Installing build dependencies..."
-sudo apt-get install -y cmake pkg-config libfreetype6-dev libfontconfig1-dev libxcb-xfixes0-dev libxkbcommon-dev python3 gzip scdoc git curl
-
-# Install Rust if not present
-if ! command -v cargo &> /dev/null && [ ! -f "$HOME/.cargo/bin/cargo" ]; then
-    echo "🦀 Installing Rust/Cargo toolchain..."
-    curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh -s -- -y
-    source "$HOME/.cargo/env"
-fi
-
-# Make sure cargo is in PATH
-export PATH="$HOME/.cargo/bin:$PATH"
-
-echo "📥 Cloning Alacritty repository..."
-ALACRITTY_DIR=$(mktemp -d)/alacritty
-git clone https://github.com/alacritty/alacritty.git "$ALACRITTY_DIR"
-
-echo "🔨 Building Alacritty (this may take 5-10 minutes)..."
-cd "$ALACRITTY_DIR"
-cargo build --release
-
-echo "📦 Installing Alacritty binary..."
-sudo cp target/release/alacritty /usr/local/bin/alacritty
-sudo cp extra/linux/Alacritty.desktop /usr/share/applications/ 2>/dev/null || true
-
-echo "🧹 Cleaning up..."
-rm -rf "$ALACRITTY_DIR"
-cd -
-
-echo "✓ Alacritty built and installed from source"`
-		}
-		configCmd = fmt.Sprintf(`mkdir -p "%s/.config/alacritty"
-cp "Gentleman.Dots/alacritty.toml" "%s/.config/alacritty/alacritty.toml"`, homeDir, homeDir)
-
-	case "wezterm":
-		if system.CommandExists("wezterm") {
-			installCmd = `echo "✓ WezTerm already installed"`
-		} else if m.SystemInfo.OS == system.OSArch {
-			installCmd = `sudo pacman -S --noconfirm wezterm`
-		} else if m.SystemInfo.OS == system.OSFedora {
-			installCmd = `sudo dnf copr enable -y wezfurlong/wezterm-nightly
-sudo dnf install -y wezterm`
-		} else {
-			// Debian uses brew, not interactive
-			return "", nil
-		}
-		configCmd = fmt.Sprintf(`mkdir -p "%s/.config/wezterm"
-cp "Gentleman.Dots/.wezterm.lua" "%s/.config/wezterm/wezterm.lua"`, homeDir, homeDir)
-
-	case "ghostty":
-		if system.CommandExists("ghostty") {
-			installCmd = `echo "✓ Ghostty already installed"`
-		} else if m.SystemInfo.OS == system.OSArch {
-			installCmd = `sudo pacman -S --noconfirm ghostty`
-		} else if m.SystemInfo.OS == system.OSFedora {
-			installCmd = `sudo dnf copr enable -y pgdev/ghostty
-sudo dnf install -y ghostty`
-		} else {
-			// Debian uses install script
-			installCmd = `curl -fsSL https://raw.githubusercontent.com/mkasberg/ghostty-ubuntu/HEAD/install.sh | bash`
-		}
-		configCmd = fmt.Sprintf(`mkdir -p "%s/.config/ghostty"
-cp -r Gentleman.Dots/GentlemanGhostty/* "%s/.config/ghostty/"`, homeDir, homeDir)
-
-	default:
-		return "", nil
-	}
-
-	script := fmt.Sprintf(`#!/bin/sh
-set -e
-echo ""
-echo "🖥️  Installing %s..."
-echo "   (You may be prompted for your password)"
-echo ""
-%s
-echo ""
-echo "📝 Copying %s configuration..."
+// linkTreeFunction is system.LinkTree in shell: a real directory at the
+// destination is unfolded entry by entry, anything else is replaced by a link
+const linkTreeFunction = `link_tree() {
+    if [ -d "$1" ] && [ -d "$2" ] && [ ! -L "$2" ]; then
+        for entry in "$1"/* "$1"/.[!.]* "$1"/..?*; do
+            [ -e "$entry" ] || [ -L "$entry" ] || continue
+            link_tree "$entry" "$2/${entry##*/}"
+        done
+    else
+        rm -rf "$2"
+        ln -s "$1" "$2"
+    fi
+}`
+
+// deployConfigDirCommand returns the shell snippet that copies (or links) a config directory
+func deployConfigDirCommand(src, dst string) string {
+	parent := shellQuote(filepath.Dir(dst))
+	src, dst = shellQuote(src), shellQuote(dst)
+	if linkMode.enabled {
+		return fmt.Sprintf(`mkdir -p %s
 %s
-echo ""
-echo "✅ %s configured!"
-echo ""
-echo "Press Enter to continue..."
-read dummy
-`, terminal, installCmd, terminal, configCmd, terminal)
-
-	return script, nil
-}
-
-// getSetShellScript returns script to set the default shell (needs chsh password)
-func getSetShellScript(m *Model) (string, error) {
-	shell := m.Choices.Shell
-	var shellCmd string
-
-	switch shell {
-	case "fish":
-		shellCmd = "fish"
-	case "zsh":
-		shellCmd = "zsh"
-	case "nushell":
-		shellCmd = "nu"
-	default:
-		return "", fmt.Errorf("unknown shell: %s", shell)
+link_tree %s %s`, parent, linkTreeFunction, src, dst)
 	}
-
-	// Termux: no chsh, we modify ~/.bashrc to start the shell
-	if m.SystemInfo.IsTermux {
-		return getSetShellScriptTermux(shellCmd)
-	}
-
-	brewPrefix := system.GetBrewPrefix()
-
-	script := fmt.Sprintf(`#!/bin/sh
-set -e
-
-# Add brew to PATH for this script
-export PATH="%s/bin:$PATH"
-
-SHELL_PATH=$(which %s 2>/dev/null)
-
-if [ -z "$SHELL_PATH" ]; then
-    echo "❌ Shell '%s' not found in PATH"
-    echo ""
-    echo "Press Enter to continue..."
-    read dummy
-    exit 1
-fi
-
-echo ""
-echo "🐚 Setting $SHELL_PATH as your default shell..."
-echo ""
-
-# Check if shell is already in /etc/shells
-if ! grep -q "^$SHELL_PATH$" /etc/shells 2>/dev/null; then
-    echo "📝 Adding $SHELL_PATH to /etc/shells (requires sudo)..."
-    echo "$SHELL_PATH" | sudo tee -a /etc/shells > /dev/null
-fi
-
-# Change shell
-echo ""
-echo "🔐 Changing default shell..."
-echo "   (You may need to enter your password)"
-echo ""
-if chsh -s "$SHELL_PATH" 2>/dev/null; then
-    echo ""
-    echo "✅ Default shell changed to $SHELL_PATH"
-elif sudo usermod -s "$SHELL_PATH" "$(whoami)" 2>/dev/null; then
-    echo ""
-    echo "✅ Default shell changed to $SHELL_PATH (via usermod)"
-else
-    echo ""
-    echo "⚠️  Could not change default shell automatically."
-    echo "   Run manually: chsh -s $SHELL_PATH"
-fi
-
-echo "   Please log out and log back in for changes to take effect."
-echo ""
-echo "Press Enter to continue..."
-read dummy
-`, brewPrefix, shellCmd, shellCmd)
-
-	return script, nil
-}
-
-// getSetShellScriptTermux returns script to set default shell in Termux
-// Termux doesn't have chsh, so we add shell launch to ~/.bashrc
-func getSetShellScriptTermux(shellCmd string) (string, error) {
-	script := fmt.Sprintf(`#!/data/data/com.termux/files/usr/bin/sh
-set -e
-
-SHELL_PATH=$(which %s 2>/dev/null)
-
-if [ -z "$SHELL_PATH" ]; then
-    echo "❌ Shell '%s' not found in PATH"
-    echo ""
-    echo "Press Enter to continue..."
-    read dummy
-    exit 1
-fi
-
-echo ""
-echo "🐚 Setting $SHELL_PATH as your default shell in Termux..."
-echo ""
-
-# Termux doesn't have chsh, so we add to ~/.bashrc
-BASHRC="$HOME/.bashrc"
-
-# Check if already configured
-if grep -q "# Gentleman.Dots shell auto-start" "$BASHRC" 2>/dev/null; then
-    echo "Shell auto-start already configured in ~/.bashrc"
-else
-    echo "" >> "$BASHRC"
-    echo "# Gentleman.Dots shell auto-start" >> "$BASHRC"
-    echo "if [ -x \"$SHELL_PATH\" ] && [ -z \"\$GENTLEMANDOTS_SHELL_STARTED\" ]; then" >> "$BASHRC"
-    echo "    export GENTLEMANDOTS_SHELL_STARTED=1" >> "$BASHRC"
-    echo "    exec $SHELL_PATH" >> "$BASHRC"
-    echo "fi" >> "$BASHRC"
-    echo "✅ Added shell auto-start to ~/.bashrc"
-fi
-
-echo ""
-echo "✅ Default shell set to $SHELL_PATH"
-echo "   Close and reopen Termux for changes to take effect."
-echo ""
-echo "Press Enter to continue..."
-read dummy
-`, shellCmd, shellCmd)
-
-	return script, nil
+	return fmt.Sprintf(`mkdir -p %s
+cp -r %s/* %s/`, dst, src, dst)
 }
 
 // createTempScriptCommand creates a temporary bash script and returns a command to execute it
@@ -403,6 +188,15 @@ func createTempScriptCommand(script string) (*exec.Cmd, error) {
 		return nil, fmt.Errorf("failed to create temp script: %w", err)
 	}
 
+	// Keep proxy/CA variables for every sudo call in the script
+	if wrapper := system.SudoShellWrapper(); wrapper != "" {
+		if shebang, body, ok := strings.Cut(script, "\n"); ok && strings.HasPrefix(shebang, "#!") {
+			script = shebang + "\n" + wrapper + body
+		} else {
+			script = wrapper + script
+		}
+	}
+
 	// Write script
 	if _, err := tmpFile.WriteString(script); err != nil {
 		tmpFile.Close()
@@ -420,7 +214,7 @@ func createTempScriptCommand(script string) (*exec.Cmd, error) {
 	// Return command - use available shell (bash, sh, or zsh)
 	shellPath := system.GetShell()
 	cmd := exec.Command(shellPath, tmpFile.Name())
-	cmd.Env = os.Environ()
+	cmd.Env = append(os.Environ(), system.NetworkEnv()...)
 
 	return cmd, nil
 }
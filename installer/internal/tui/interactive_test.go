@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
 )
 
 func TestGetHomebrewScriptUsesTTYSafePrompt(t *testing.T) {
@@ -18,6 +20,12 @@ func TestGetHomebrewScriptUsesTTYSafePrompt(t *testing.T) {
 		t.Fatalf("failed to clear PATH: %v", err)
 	}
 
+	if _, err := getHomebrewScript(&Model{}); !errors.Is(err, system.ErrInstallScriptRefused) {
+		t.Fatalf("expected the Homebrew script to be refused by default, got %v", err)
+	}
+	system.SetAllowInstallScripts(true)
+	t.Cleanup(func() { system.SetAllowInstallScripts(false) })
+
 	script, err := getHomebrewScript(&Model{})
 	if err != nil {
 		t.Fatalf("getHomebrewScript returned error: %v", err)
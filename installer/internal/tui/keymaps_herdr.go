@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// GetHerdrKeymaps returns the Herdr keymaps organized by category, read from
+// the installed config.toml (the shipped bindings when Herdr is not installed)
+func GetHerdrKeymaps() []KeymapCategory {
+	content, _ := os.ReadFile(wmConfigPath("herdr"))
+	keys := system.HerdrKeys(string(content))
+	prefix := keys["prefix"]
+
+	return []KeymapCategory{
+		{
+			Name:        "Prefix",
+			Description: "Press the prefix, then the key",
+			Keymaps: []Keymap{
+				{Keys: herdrKeyLabel(prefix, ""), Description: "Prefix key", Mode: ""},
+			},
+		},
+		{
+			Name:        "Agents",
+			Description: "Move between agents in the sidebar",
+			Keymaps: []Keymap{
+				{Keys: herdrKeyLabel(keys["previous_agent"], prefix), Description: "Previous agent", Mode: ""},
+				{Keys: herdrKeyLabel(keys["next_agent"], prefix), Description: "Next agent", Mode: ""},
+				{Keys: herdrKeyLabel(keys["focus_agent"], prefix), Description: "Jump to agent N as listed in the sidebar", Mode: ""},
+			},
+		},
+		{
+			Name:        "Panes",
+			Description: "Built-in Herdr bindings",
+			Keymaps: []Keymap{
+				{Keys: herdrKeyLabel("prefix+shift+j/k", prefix), Description: "Swap panes", Mode: ""},
+			},
+		},
+	}
+}
+
+// herdrKeyLabel renders a Herdr key spec the way the other keymap tables do:
+// prefix+alt+k becomes "Ctrl+a Alt+k"
+func herdrKeyLabel(spec, prefix string) string {
+	parts := strings.Split(spec, "+")
+	lead := ""
+	if parts[0] == "prefix" && prefix != "" {
+		lead = herdrKeyLabel(prefix, "") + " "
+		parts = parts[1:]
+	}
+	for i, part := range parts {
+		switch {
+		case part == "1..9":
+			parts[i] = "1-9"
+		case part != "" && (i < len(parts)-1 || len(part) > 1 && !strings.Contains(part, "/")):
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return lead + strings.Join(parts, "+")
+}
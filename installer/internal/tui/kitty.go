@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// kittyVersion is the upstream release installed where no distro package exists
+const kittyVersion = "0.42.2"
+
+// Kitty publishes its Linux bundles as GitHub release assets; the release API
+// lists the SHA256 digest of every asset
+const (
+	kittyReleases   = "https://github.com/kovidgoyal/kitty/releases/download/"
+	kittyReleaseAPI = "https://api.github.com/repos/kovidgoyal/kitty/releases/tags/"
+)
+
+// kittyPinned are the bundle digests shipped with the installer. Add the
+// x86_64 and arm64 digests of the release assets when bumping kittyVersion;
+// without them the bundle is refused unless --allow-unpinned is passed.
+var kittyPinned = map[string]system.Release{}
+
+// kittyBundleArtifact is the verified upstream bundle for a Go architecture
+func kittyBundleArtifact(version, goarch string) (system.Artifact, error) {
+	arch := map[string]string{"amd64": "x86_64", "arm64": "arm64"}[goarch]
+	if arch == "" {
+		return system.Artifact{}, fmt.Errorf("kitty publishes no Linux bundle for %s", goarch)
+	}
+	tag := "v" + version
+	asset := fmt.Sprintf("kitty-%s-%s.txz", version, arch)
+	url := kittyReleases + tag + "/" + asset
+	if release, ok := kittyPinned[tag]; ok {
+		sum := release.Assets[asset]
+		if sum == "" {
+			return system.Artifact{}, fmt.Errorf("kitty %s pins no checksum for %s: %w", tag, asset, system.ErrUnpinned)
+		}
+		return system.Artifact{URL: url, SHA256: sum}, nil
+	}
+	return system.Artifact{URL: url, ReleaseAPI: kittyReleaseAPI + tag}, nil
+}
+
+// kittyAppDir is where the upstream bundle is unpacked, as kitty's own installer does
+func kittyAppDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".local", "kitty.app")
+}
+
+// kittyBundleActions installs the upstream bundle into the home directory,
+// so neither sudo nor a distro package is needed
+func kittyBundleActions(m *Model) ([]stepAction, error) {
+	if m.SystemInfo.Libc == "musl" {
+		return nil, wrapStepError("terminal", "Install Kitty",
+			"The Kitty bundle needs glibc. Install kitty from your distro's packages.",
+			fmt.Errorf("libc: %s", m.SystemInfo.Libc))
+	}
+	if system.FileExists(filepath.Join(kittyAppDir(), "bin", "kitty")) {
+		return []stepAction{{Kind: actionNote, Log: "Kitty already installed in " + kittyAppDir()}}, nil
+	}
+	actions := []stepAction{{Kind: actionPatch, Log: fmt.Sprintf("Installing Kitty %s from the upstream bundle...", kittyVersion),
+		Apply: func() error { return installKittyBundle("terminal", kittyVersion) },
+		Fail:  "Failed to install the Kitty bundle",
+		OK:    "✓ Kitty installed in " + kittyAppDir()}}
+	if m.SystemInfo.IsProot {
+		actions = append(actions, stepAction{Kind: actionNote,
+			Log: "Running under proot: start Kitty inside the Termux:X11 desktop; without GPU access use LIBGL_ALWAYS_SOFTWARE=1 kitty"})
+	}
+	return actions, nil
+}
+
+// installKittyBundle downloads and verifies the bundle, swaps it into
+// kittyAppDir, links kitty and kitten into ~/.local/bin and registers the
+// desktop entries
+func installKittyBundle(stepID, version string) error {
+	if !system.CommandExists("xz") {
+		return fmt.Errorf("xz is needed to unpack the bundle (install xz-utils)")
+	}
+	artifact, err := kittyBundleArtifact(version, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+	archive := filepath.Join(os.TempDir(), filepath.Base(artifact.URL))
+	defer os.Remove(archive)
+	if err := downloadArtifact(stepID, artifact, archive); err != nil {
+		return err
+	}
+
+	// Unpack next to the target so a failed extraction leaves the old bundle
+	app := kittyAppDir()
+	staging := app + ".new"
+	os.RemoveAll(staging)
+	if err := system.EnsureDir(staging); err != nil {
+		return err
+	}
+	extract := fmt.Sprintf("tar -xJf %s -C %s", shellQuote(archive), shellQuote(staging))
+	if result := system.Run(extract, nil); result.Error != nil {
+		os.RemoveAll(staging)
+		return fmt.Errorf("unpacking %s: %w", filepath.Base(archive), result.Error)
+	}
+	if err := os.RemoveAll(app); err != nil {
+		return err
+	}
+	if err := os.Rename(staging, app); err != nil {
+		return err
+	}
+
+	bin := filepath.Join(os.Getenv("HOME"), ".local", "bin")
+	if err := system.EnsureDir(bin); err != nil {
+		return err
+	}
+	for _, name := range []string{"kitty", "kitten"} {
+		link := filepath.Join(bin, name)
+		os.Remove(link)
+		if err := os.Symlink(filepath.Join(app, "bin", name), link); err != nil {
+			return err
+		}
+	}
+	return registerKittyDesktopEntries(app, filepath.Join(os.Getenv("HOME"), ".local", "share", "applications"))
+}
+
+// registerKittyDesktopEntries installs the bundle's launcher entries with
+// absolute paths, since ~/.local/bin is often not on the desktop's PATH
+func registerKittyDesktopEntries(app, appsDir string) error {
+	if err := system.EnsureDir(appsDir); err != nil {
+		return err
+	}
+	for _, name := range []string{"kitty.desktop", "kitty-open.desktop"} {
+		data, err := os.ReadFile(filepath.Join(app, "share", "applications", name))
+		if err != nil {
+			return err
+		}
+		entry := kittyDesktopEntry(string(data), app)
+		if err := os.WriteFile(filepath.Join(appsDir, name), []byte(entry), 0644); err != nil {
+			return err
+		}
+	}
+	if system.CommandExists("update-desktop-database") {
+		system.Run("update-desktop-database "+shellQuote(appsDir), nil)
+	}
+	return nil
+}
+
+// kittyDesktopEntry points the Exec, TryExec and Icon keys at the bundle
+func kittyDesktopEntry(content, app string) string {
+	kitty := filepath.Join(app, "bin", "kitty")
+	icon := filepath.Join(app, "share", "icons", "hicolor", "256x256", "apps", "kitty.png")
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		for _, key := range []string{"Exec=", "TryExec="} {
+			if rest, ok := strings.CutPrefix(line, key+"kitty"); ok && (rest == "" || rest[0] == ' ') {
+				lines[i] = key + kitty + rest
+			}
+		}
+		if line == "Icon=kitty" {
+			lines[i] = "Icon=" + icon
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+func TestKittyBundleArtifact(t *testing.T) {
+	a, err := kittyBundleArtifact("0.42.2", "arm64")
+	if err != nil {
+		t.Fatalf("kittyBundleArtifact: %v", err)
+	}
+	if a.URL != kittyReleases+"v0.42.2/kitty-0.42.2-arm64.txz" || a.ReleaseAPI != kittyReleaseAPI+"v0.42.2" {
+		t.Errorf("unexpected artifact %+v", a)
+	}
+	if a.SHA256 != "" || a.ChecksumManifest != "" {
+		t.Errorf("the digest must come from the release, got %+v", a)
+	}
+	if _, err := kittyBundleArtifact("0.42.2", "386"); err == nil {
+		t.Error("expected an error for an architecture without a bundle")
+	}
+
+	// A shipped digest skips the release API
+	kittyPinned["v9.9.9"] = system.Release{Tag: "v9.9.9", Assets: map[string]string{"kitty-9.9.9-x86_64.txz": strings.Repeat("ab", 32)}}
+	t.Cleanup(func() { delete(kittyPinned, "v9.9.9") })
+	if a, err := kittyBundleArtifact("9.9.9", "amd64"); err != nil || a.SHA256 != strings.Repeat("ab", 32) || a.ReleaseAPI != "" {
+		t.Errorf("expected the pinned digest, got %+v, %v", a, err)
+	}
+	if _, err := kittyBundleArtifact("9.9.9", "arm64"); !errors.Is(err, system.ErrUnpinned) {
+		t.Errorf("expected an unpinned asset of a pinned release to be refused, got %v", err)
+	}
+}
+
+func TestKittyDesktopEntry(t *testing.T) {
+	entry := "[Desktop Entry]\nName=kitty\nTryExec=kitty\nExec=kitty +open %U\nIcon=kitty\nKeywords=kitty;term\n"
+	got := kittyDesktopEntry(entry, "/home/u/.local/kitty.app")
+	for _, want := range []string{
+		"TryExec=/home/u/.local/kitty.app/bin/kitty\n",
+		"Exec=/home/u/.local/kitty.app/bin/kitty +open %U\n",
+		"Icon=/home/u/.local/kitty.app/share/icons/hicolor/256x256/apps/kitty.png\n",
+		"Name=kitty\n", "Keywords=kitty;term\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestRegisterKittyDesktopEntries(t *testing.T) {
+	app, appsDir := t.TempDir(), filepath.Join(t.TempDir(), "applications")
+	shipped := filepath.Join(app, "share", "applications")
+	os.MkdirAll(shipped, 0755)
+	os.WriteFile(filepath.Join(shipped, "kitty.desktop"), []byte("Exec=kitty\n"), 0644)
+	os.WriteFile(filepath.Join(shipped, "kitty-open.desktop"), []byte("Exec=kitty +open %U\n"), 0644)
+
+	if err := registerKittyDesktopEntries(app, appsDir); err != nil {
+		t.Fatalf("registerKittyDesktopEntries: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(appsDir, "kitty-open.desktop"))
+	if string(data) != "Exec="+filepath.Join(app, "bin", "kitty")+" +open %U\n" {
+		t.Errorf("unexpected entry %q", data)
+	}
+}
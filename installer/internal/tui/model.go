@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
 	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/tui/trainer"
@@ -40,6 +41,8 @@ const (
 	ScreenKeymapsZellijCat  // Zellij keymap category
 	ScreenKeymapsGhostty    // Ghostty keymaps
 	ScreenKeymapsGhosttyCat // Ghostty keymap category
+	ScreenKeymapsHerdr      // Herdr keymaps
+	ScreenKeymapsHerdrCat   // Herdr keymap category
 	// LazyVim learn screens
 	ScreenLearnLazyVim
 	ScreenLazyVimTopic
@@ -56,6 +59,19 @@ const (
 	ScreenTrainerBoss       // Boss fight
 	ScreenTrainerResult     // Result after exercise
 	ScreenTrainerBossResult // Result after boss fight
+	// Reconfigure screens
+	ScreenReconfigure         // Pick which setting of an existing install to change
+	ScreenReconfigureWM       // Pick the new window manager
+	ScreenReconfigureShell    // Pick the new shell
+	ScreenReconfigureTheme    // Pick the new color theme
+	ScreenReconfigureShaders  // Enable and order Ghostty shaders
+	ScreenReconfigureTerminal // Tune font size, opacity, padding and blur
+	ScreenReconfigureLayout   // Pick the Zellij default layout
+	ScreenReconfigureHerdr    // Change the Herdr prefix and agent bindings
+	// Nix screens
+	ScreenNixMode // Generate a home-manager module or install imperatively
+	// Pre-flight screen
+	ScreenPreflight // System checks between backup confirmation and install
 )
 
 // InstallStep represents a single installation step
@@ -81,13 +97,21 @@ const (
 
 // UserChoices stores all user selections
 type UserChoices struct {
-	OS           string // "mac", "linux"
-	Terminal     string // "alacritty", "wezterm", "kitty", "ghostty", "none"
-	InstallFont  bool
-	Shell        string // "fish", "zsh", "nushell"
-	WindowMgr    string // "tmux", "zellij", "herdr", "none"
-	InstallNvim  bool
-	CreateBackup bool // Whether to backup existing configs
+	OS               string // "mac", "linux"
+	Terminal         string // "alacritty", "wezterm", "kitty", "ghostty", "none"
+	InstallFont      bool
+	Font             string // Nerd Font ID, see nerdFonts (empty: platform default)
+	FontVersion      string // nerd-fonts release, e.g. "v3.3.0" (empty: default)
+	Shell            string // "fish", "zsh", "nushell", "bash"
+	WindowMgr        string // "tmux", "zellij", "herdr", "none"
+	InstallNvim      bool
+	CreateBackup     bool               // Whether to backup existing configs
+	NixMode          bool               // Generate a home-manager module instead of installing
+	Theme            string             // palette ID, see system.Palettes (empty: keep the shipped colors)
+	Shaders          []string           // Ghostty shader files in the order they run
+	TerminalSettings map[string]float64 // system.TerminalSettings keys to write into terminal configs
+	ZellijLayout     string             // layout in ~/.config/zellij/layouts zellij starts with
+	HerdrKeys        map[string]string  // system.HerdrBindings keys to write into herdr's config.toml
 }
 
 // Model is the main application state
@@ -105,6 +129,10 @@ type Model struct {
 	ShowDetails bool
 	LogLines    []string
 	TotalTime   float64
+	StepStarted time.Time // when the current step began, for recorded timings
+	// Estimates: read when the wizard starts so rendering never touches the disk
+	StepTimings map[string]float64 // durations recorded on previous runs
+	HasNode     bool               // node is installed, so the Neovim estimate skips it
 	Quitting    bool
 	// Program reference for sending messages during installation
 	Program *tea.Program
@@ -126,6 +154,9 @@ type Model struct {
 	GhosttyKeymapCategories []KeymapCategory
 	GhosttySelectedCategory int
 	GhosttyKeymapScroll     int
+	HerdrKeymapCategories   []KeymapCategory
+	HerdrSelectedCategory   int
+	HerdrKeymapScroll       int
 	// LazyVim mode
 	LazyVimTopics        []LazyVimTopic
 	SelectedLazyVimTopic int
@@ -135,6 +166,10 @@ type Model struct {
 	AvailableBackups []system.BackupInfo // Available backups for restore
 	SelectedBackup   int                 // Selected backup index
 	BackupDir        string              // Last backup directory created
+	// Pre-flight checks
+	PreflightChecks  []PreflightCheck // Results of the last pre-flight run
+	PreflightRunning bool             // Checks are still in progress
+	PreflightConfirm bool             // "Start anyway" was picked once; Enter again confirms
 	// Vim Trainer mode
 	TrainerStats       *trainer.UserStats   // User's training stats
 	TrainerGameState   *trainer.GameState   // Current game session state
@@ -145,6 +180,21 @@ type Model struct {
 	TrainerMessage     string               // Feedback message to display
 	// Leader key mode (like Vim's <space> leader)
 	LeaderMode bool // True when waiting for next key after <space>
+	// Reconfigure mode: setting being changed on an existing install ("" during a full install)
+	Reconfiguring string
+	ShaderNotice  string // why the shader picker cannot apply (no Ghostty config)
+	// Zellij layout screen
+	ZellijLayouts []string // deployed layouts
+	LayoutNotice  string   // why the layout picker cannot apply (no Zellij config)
+	// Terminal tuning screen
+	TuningValues map[string]float64 // edited values
+	TuningBase   map[string]float64 // values read from TuningSource
+	TuningSource string             // terminal whose config the values come from
+	TuningNotice string             // why the tuning screen cannot apply (no terminal config)
+	// Herdr keys screen
+	HerdrKeyValues map[string]string // edited bindings
+	HerdrKeyBase   map[string]string // bindings read from config.toml
+	HerdrNotice    string            // why the keys screen cannot apply (no Herdr config)
 }
 
 // NewModel creates a new Model with initial state
@@ -174,6 +224,9 @@ func NewModel() Model {
 		GhosttyKeymapCategories: GetGhosttyKeymaps(),
 		GhosttySelectedCategory: 0,
 		GhosttyKeymapScroll:     0,
+		HerdrKeymapCategories:   GetHerdrKeymaps(),
+		HerdrSelectedCategory:   0,
+		HerdrKeymapScroll:       0,
 		LazyVimTopics:           GetLazyVimTopics(),
 		SelectedLazyVimTopic:    0,
 		LazyVimScroll:           0,
@@ -231,6 +284,17 @@ func SendLog(stepID string, log string) {
 	}
 }
 
+// SendProgress updates the progress bar of a running step (0.0-1.0)
+func SendProgress(stepID string, progress float64) {
+	if nonInteractiveMode || globalProgram == nil {
+		return
+	}
+	globalProgram.Send(stepProgressMsg{
+		stepID:   stepID,
+		progress: progress,
+	})
+}
+
 // SendLogLine is an alias for SendLog for compatibility
 func (m *Model) SendLog(stepID string, log string) {
 	SendLog(stepID, log)
@@ -251,10 +315,23 @@ func (m Model) GetCurrentOptions() []string {
 		if len(m.AvailableBackups) > 0 {
 			opts = append(opts, "🔄 Restore from Backup")
 		}
-		opts = append(opts, "❌ Exit")
+		opts = append(opts, "🔧 Reconfigure", "❌ Exit")
 		return opts
 	case ScreenKeymapsMenu:
-		return []string{"Neovim", "Tmux", "Zellij", "Ghostty", "─────────────", "← Back"}
+		return []string{"Neovim", "Tmux", "Zellij", "Ghostty", "Herdr", "─────────────", "← Back"}
+	case ScreenReconfigure:
+		return []string{"🪟 Window Manager", "🐚 Shell", "🎨 Color Theme", "✨ Ghostty Shaders", "💻 Terminal Settings", "🧩 Zellij Layout", "⌨️ Herdr Keys", "─────────────", "← Back"}
+	case ScreenReconfigureWM:
+		if m.SystemInfo != nil && m.SystemInfo.IsTermux {
+			return []string{"Tmux", "Zellij", "None", "─────────────", "← Back"}
+		}
+		return []string{"Tmux", "Zellij", "Herdr", "None", "─────────────", "← Back"}
+	case ScreenReconfigureShell:
+		return []string{"Fish", "Zsh", "Nushell", "Bash", "─────────────", "← Back"}
+	case ScreenReconfigureTheme:
+		return themePickerOptions()
+	case ScreenReconfigureLayout:
+		return layoutPickerOptions(m.ZellijLayouts, m.Choices.ZellijLayout)
 	case ScreenOSSelect:
 		macLabel := "macOS"
 		linuxLabel := "Linux"
@@ -263,7 +340,7 @@ func (m Model) GetCurrentOptions() []string {
 			macLabel = "macOS (detected)"
 		} else if m.SystemInfo.OS == system.OSTermux {
 			termuxLabel = "Termux (detected)"
-		} else if m.SystemInfo.OS == system.OSLinux || m.SystemInfo.OS == system.OSArch || m.SystemInfo.OS == system.OSDebian || m.SystemInfo.OS == system.OSFedora {
+		} else if m.SystemInfo.OS == system.OSLinux || m.SystemInfo.OS == system.OSArch || m.SystemInfo.OS == system.OSDebian || m.SystemInfo.OS == system.OSFedora || m.SystemInfo.OS == system.OSSUSE || m.SystemInfo.OS == system.OSAlpine || m.SystemInfo.OS == system.OSVoid {
 			linuxLabel = "Linux (detected)"
 		}
 		return []string{macLabel, linuxLabel, termuxLabel}
@@ -272,16 +349,17 @@ func (m Model) GetCurrentOptions() []string {
 		// On Debian/Ubuntu, Alacritty needs to be built from source (PPAs are unreliable)
 		// This applies to ALL Debian-based systems, not just ARM
 		if m.SystemInfo != nil && (m.SystemInfo.OS == system.OSDebian || m.SystemInfo.OS == system.OSLinux) && m.Choices.OS == "linux" {
-			alacrittyLabel = "Alacritty ⏱️  (builds from source, installs Rust ~5-10 min)"
+			alacrittyLabel = "Alacritty ⏱️  (builds from source, installs Rust)"
 		}
-		if m.Choices.OS == "mac" {
-			return []string{alacrittyLabel, "WezTerm", "Kitty", "Ghostty", "None", "─────────────", "ℹ️  Learn about terminals"}
+		// Alpine only carries Ghostty in edge/testing
+		if m.SystemInfo != nil && m.SystemInfo.OS == system.OSAlpine && m.Choices.OS == "linux" {
+			return []string{alacrittyLabel, "WezTerm", "Kitty", "None", "─────────────", "ℹ️  Learn about terminals"}
 		}
-		return []string{alacrittyLabel, "WezTerm", "Ghostty", "None", "─────────────", "ℹ️  Learn about terminals"}
+		return []string{alacrittyLabel, "WezTerm", "Kitty", "Ghostty", "None", "─────────────", "ℹ️  Learn about terminals"}
 	case ScreenFontSelect:
-		return []string{"Yes, install Iosevka Term Nerd Font", "No, I already have it"}
+		return fontPickerOptions()
 	case ScreenShellSelect:
-		return []string{"Fish", "Zsh", "Nushell", "─────────────", "ℹ️  Learn about shells"}
+		return []string{"Fish", "Zsh", "Nushell", "Bash", "─────────────", "ℹ️  Learn about shells"}
 	case ScreenWMSelect:
 		if m.SystemInfo != nil && m.SystemInfo.IsTermux {
 			return []string{"Tmux", "Zellij", "None", "─────────────", "ℹ️  Learn about multiplexers"}
@@ -289,6 +367,27 @@ func (m Model) GetCurrentOptions() []string {
 		return []string{"Tmux", "Zellij", "Herdr", "None", "─────────────", "ℹ️  Learn about multiplexers"}
 	case ScreenNvimSelect:
 		return []string{"Yes, install Neovim with config", "No, skip Neovim", "─────────────", "ℹ️  Learn about Neovim", "⌨️  View Keymaps", "📖 LazyVim Guide"}
+	case ScreenPreflight:
+		if m.PreflightRunning {
+			return nil
+		}
+		start := "▶ Start installation"
+		if preflightHasFailures(m.PreflightChecks) {
+			start = "⚠️  Start installation anyway"
+			if m.PreflightConfirm {
+				start = "⚠️  Press Enter again to install despite failed checks"
+			}
+		}
+		opts := []string{start, "🔄 Run checks again"}
+		if scriptsBlocked(&m) {
+			opts = append(opts, preflightAllowScriptsOption)
+		}
+		if unpinnedBlocked(&m) {
+			opts = append(opts, preflightAllowUnpinnedOption)
+		}
+		return append(opts, "❌ Cancel")
+	case ScreenNixMode:
+		return []string{"❄️  Generate home-manager module (recommended)", "📦 Install imperatively anyway"}
 	case ScreenBackupConfirm:
 		return []string{
 			"✅ Install with Backup (recommended)",
@@ -319,7 +418,7 @@ func (m Model) GetCurrentOptions() []string {
 	case ScreenLearnTerminals:
 		return []string{"Alacritty", "WezTerm", "Kitty", "Ghostty", "─────────────", "← Back"}
 	case ScreenLearnShells:
-		return []string{"Fish", "Zsh", "Nushell", "─────────────", "← Back"}
+		return []string{"Fish", "Zsh", "Nushell", "Bash", "─────────────", "← Back"}
 	case ScreenLearnWM:
 		return []string{"Tmux", "Zellij", "Herdr", "─────────────", "← Back"}
 	case ScreenLearnNvim:
@@ -356,6 +455,14 @@ func (m Model) GetCurrentOptions() []string {
 		categories[len(m.GhosttyKeymapCategories)] = "─────────────"
 		categories[len(m.GhosttyKeymapCategories)+1] = "← Back"
 		return categories
+	case ScreenKeymapsHerdr:
+		categories := make([]string, len(m.HerdrKeymapCategories)+2)
+		for i, cat := range m.HerdrKeymapCategories {
+			categories[i] = cat.Name
+		}
+		categories[len(m.HerdrKeymapCategories)] = "─────────────"
+		categories[len(m.HerdrKeymapCategories)+1] = "← Back"
+		return categories
 	case ScreenLearnLazyVim:
 		titles := GetLazyVimTopicTitles()
 		result := make([]string, len(titles)+2)
@@ -387,6 +494,10 @@ func (m Model) GetScreenTitle() string {
 		return "Step 5: Choose Window Manager"
 	case ScreenNvimSelect:
 		return "Step 6: Neovim Configuration"
+	case ScreenNixMode:
+		return "❄️  Nix Detected"
+	case ScreenPreflight:
+		return "🛫 Pre-flight Checks"
 	case ScreenBackupConfirm:
 		return "⚠️  Existing Configs Detected"
 	case ScreenRestoreBackup:
@@ -395,6 +506,22 @@ func (m Model) GetScreenTitle() string {
 		return "🔄 Confirm Restore"
 	case ScreenGhosttyWarning:
 		return "⚠️  Ghostty Compatibility Warning"
+	case ScreenReconfigure:
+		return "🔧 Reconfigure"
+	case ScreenReconfigureWM:
+		return "🔧 Reconfigure: Window Manager"
+	case ScreenReconfigureShell:
+		return "🔧 Reconfigure: Shell"
+	case ScreenReconfigureTheme:
+		return "🔧 Reconfigure: Color Theme"
+	case ScreenReconfigureShaders:
+		return "🔧 Reconfigure: Ghostty Shaders"
+	case ScreenReconfigureTerminal:
+		return "🔧 Reconfigure: Terminal Settings"
+	case ScreenReconfigureLayout:
+		return "🔧 Reconfigure: Zellij Layout"
+	case ScreenReconfigureHerdr:
+		return "🔧 Reconfigure: Herdr Keys"
 	case ScreenInstalling:
 		return "Installing..."
 	case ScreenComplete:
@@ -439,6 +566,13 @@ func (m Model) GetScreenTitle() string {
 			return "⌨️  " + m.GhosttyKeymapCategories[m.GhosttySelectedCategory].Name
 		}
 		return "⌨️  Ghostty Keymaps"
+	case ScreenKeymapsHerdr:
+		return "⌨️  Herdr Keymaps"
+	case ScreenKeymapsHerdrCat:
+		if m.HerdrSelectedCategory < len(m.HerdrKeymapCategories) {
+			return "⌨️  " + m.HerdrKeymapCategories[m.HerdrSelectedCategory].Name
+		}
+		return "⌨️  Herdr Keymaps"
 	case ScreenLearnLazyVim:
 		return "📖 LazyVim Guide"
 	case ScreenLazyVimTopic:
@@ -478,15 +612,35 @@ func (m Model) GetScreenDescription() string {
 		}
 		return "Select your preferred terminal emulator"
 	case ScreenFontSelect:
-		return "Iosevka Term Nerd Font is required for icons and glyphs"
+		return "A Nerd Font is required for icons and glyphs; the configs ship with Iosevka Term"
 	case ScreenShellSelect:
 		return "Current shell: " + m.SystemInfo.UserShell
 	case ScreenWMSelect:
 		return "Terminal multiplexer for managing sessions"
+	case ScreenReconfigure:
+		return "Change a setting of your existing Gentleman.Dots install"
+	case ScreenReconfigureWM:
+		return "Installed shell configs will be re-patched to start the new multiplexer"
+	case ScreenReconfigureShell:
+		return "Keeps your multiplexer setup and backs up the current shell config"
+	case ScreenReconfigureTheme:
+		return "Recolors the terminal, multiplexer, prompt and Neovim configs together"
+	case ScreenReconfigureShaders:
+		return "Shaders run in order, each on the output of the previous one"
+	case ScreenReconfigureTerminal:
+		return "Changes are written to every installed terminal config"
+	case ScreenReconfigureLayout:
+		return "New Zellij sessions open with this layout"
+	case ScreenReconfigureHerdr:
+		return "Bindings are written to the [keys] table of ~/.config/herdr/config.toml"
 	case ScreenNvimSelect:
 		return "Includes LSP, TreeSitter, and Gentleman config"
 	case ScreenGhosttyWarning:
 		return "Ghostty installation may fail on Ubuntu/Debian.\nThe installer script only supports certain versions."
+	case ScreenPreflight:
+		return "Verifying your system before anything is installed"
+	case ScreenNixMode:
+		return "Imperative installs fight with Nix. The module declares the packages and\nconfig files; import it into home-manager and switch."
 	default:
 		return ""
 	}
@@ -496,6 +650,29 @@ func (m Model) GetScreenDescription() string {
 func (m *Model) SetupInstallSteps() {
 	m.Steps = []InstallStep{}
 
+	// Nix: packages and configs are declared in a module, nothing is installed
+	if m.Choices.NixMode {
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "clone",
+			Name:        "Clone Repository",
+			Description: "Downloading Gentleman.Dots",
+			Status:      StatusPending,
+		})
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "nix",
+			Name:        "Generate Nix Module",
+			Description: "home-manager packages and config files",
+			Status:      StatusPending,
+		})
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "cleanup",
+			Name:        "Cleanup",
+			Description: "Removing temporary files",
+			Status:      StatusPending,
+		})
+		return
+	}
+
 	// Backup step if user chose to backup (not interactive - just file copies)
 	if m.Choices.CreateBackup && len(m.ExistingConfigs) > 0 {
 		m.Steps = append(m.Steps, InstallStep{
@@ -544,8 +721,9 @@ func (m *Model) SetupInstallSteps() {
 	})
 
 	// Homebrew (interactive - first install needs password)
-	// Skip Termux and native package manager Linux distributions.
-	if !m.SystemInfo.HasBrew && !m.SystemInfo.IsTermux && m.SystemInfo.OS != system.OSArch && m.SystemInfo.OS != system.OSFedora {
+	// Skip Termux, NixOS and native package manager Linux distributions
+	// (Homebrew does not support musl, so Alpine never gets it).
+	if !m.SystemInfo.HasBrew && !m.SystemInfo.IsTermux && !usesNativePackageManager(m.SystemInfo.OS) && m.SystemInfo.DistroFamily != system.FamilyNixOS {
 		m.Steps = append(m.Steps, InstallStep{
 			ID:          "homebrew",
 			Name:        "Install Homebrew",
@@ -570,7 +748,7 @@ func (m *Model) SetupInstallSteps() {
 	if m.Choices.InstallFont {
 		m.Steps = append(m.Steps, InstallStep{
 			ID:          "font",
-			Name:        "Install Iosevka Nerd Font",
+			Name:        "Install " + selectedFont(m.Choices).Label,
 			Description: "Nerd font with icons",
 			Status:      StatusPending,
 		})
@@ -604,6 +782,16 @@ func (m *Model) SetupInstallSteps() {
 		})
 	}
 
+	// Recolor the freshly deployed configs
+	if m.Choices.Theme != "" {
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "theme",
+			Name:        "Apply Theme",
+			Description: "Recoloring installed configs",
+			Status:      StatusPending,
+		})
+	}
+
 	// Set default shell (interactive - chsh needs password)
 	m.Steps = append(m.Steps, InstallStep{
 		ID:          "setshell",
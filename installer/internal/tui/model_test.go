@@ -82,32 +82,35 @@ func TestGetCurrentOptions(t *testing.T) {
 		}
 	})
 
-	t.Run("should return terminal options for linux without kitty", func(t *testing.T) {
+	t.Run("should return terminal options for linux with kitty", func(t *testing.T) {
 		m.Screen = ScreenTerminalSelect
 		m.Choices.OS = "linux"
 		opts := m.GetCurrentOptions()
 
-		// Should have: Alacritty, WezTerm, Ghostty, None, separator, Learn
-		if len(opts) != 6 {
-			t.Errorf("Expected 6 terminal options for linux (including separator and learn), got %d", len(opts))
+		// Should have: Alacritty, WezTerm, Kitty, Ghostty, None, separator, Learn
+		if len(opts) != 7 {
+			t.Errorf("Expected 7 terminal options for linux (including separator and learn), got %d", len(opts))
 		}
-		// Should NOT include Kitty on linux
+		hasKitty := false
 		for _, opt := range opts {
 			if opt == "Kitty" {
-				t.Error("Linux should not have Kitty option")
+				hasKitty = true
 			}
 		}
+		if !hasKitty {
+			t.Error("Linux should have Kitty option")
+		}
 	})
 
 	t.Run("should return shell options", func(t *testing.T) {
 		m.Screen = ScreenShellSelect
 		opts := m.GetCurrentOptions()
 
-		// Should have: Fish, Zsh, Nushell, separator, Learn
-		if len(opts) != 5 {
-			t.Errorf("Expected 5 shell options (including separator and learn), got %d", len(opts))
+		// Should have: Fish, Zsh, Nushell, Bash, separator, Learn
+		if len(opts) != 6 {
+			t.Errorf("Expected 6 shell options (including separator and learn), got %d", len(opts))
 		}
-		expected := []string{"Fish", "Zsh", "Nushell"}
+		expected := []string{"Fish", "Zsh", "Nushell", "Bash"}
 		for i, exp := range expected {
 			if opts[i] != exp {
 				t.Errorf("Expected %s at position %d, got %s", exp, i, opts[i])
@@ -227,6 +230,27 @@ func TestSetupInstallSteps(t *testing.T) {
 		}
 	})
 
+	t.Run("should skip homebrew on openSUSE, Alpine and Void", func(t *testing.T) {
+		for _, osType := range []system.OSType{system.OSSUSE, system.OSAlpine, system.OSVoid} {
+			m := NewModel()
+			m.SystemInfo = &system.SystemInfo{OS: osType}
+			m.Choices = UserChoices{
+				OS:        "linux",
+				Terminal:  "none",
+				Shell:     "zsh",
+				WindowMgr: "tmux",
+			}
+
+			m.SetupInstallSteps()
+
+			for _, step := range m.Steps {
+				if step.ID == "homebrew" {
+					t.Errorf("OS %v uses its native package manager, got a homebrew step", osType)
+				}
+			}
+		}
+	})
+
 	t.Run("should include terminal step when selected", func(t *testing.T) {
 		m := NewModel()
 		m.SystemInfo = &system.SystemInfo{
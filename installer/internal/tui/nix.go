@@ -0,0 +1,341 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// nixOutput holds where the home-manager module is generated
+var nixOutput struct {
+	dir string
+}
+
+// SetNixOutputDir sets the directory the home-manager module is written to
+func SetNixOutputDir(dir string) {
+	if abs, err := filepath.Abs(dir); err == nil && dir != "" {
+		dir = abs
+	}
+	nixOutput.dir = dir
+}
+
+// NixOutputDir returns the directory the home-manager module is written to
+func NixOutputDir() string {
+	if nixOutput.dir == "" {
+		return filepath.Join(os.Getenv("HOME"), ".config", "home-manager", "gentleman")
+	}
+	return nixOutput.dir
+}
+
+// nixDetected reports whether Nix manages this system or user profile
+func nixDetected(info *system.SystemInfo) bool {
+	if info == nil || info.IsTermux {
+		return false
+	}
+	return info.DistroFamily == system.FamilyNixOS || info.HasPackageManager(system.PkgNix)
+}
+
+// nixEntry is one file or directory the generated module links into $HOME
+type nixEntry struct {
+	attr   string // "xdg.configFile" or "home.file"
+	target string // relative to ~/.config for xdg.configFile, to $HOME for home.file
+	source string // relative to the repository; empty when expr is set
+	expr   string // Nix expression used as source instead of a copied file
+	dir    bool
+}
+
+// localPath is where the entry is copied inside the output directory
+func (e nixEntry) localPath() string {
+	if e.attr == "xdg.configFile" {
+		return filepath.Join("config", e.target)
+	}
+	return filepath.Join("home", e.target)
+}
+
+// nixPackages returns the nixpkgs attributes for the chosen tools
+func nixPackages(choices UserChoices) []string {
+	var pkgs []string
+	switch choices.Terminal {
+	case "alacritty", "wezterm", "kitty", "ghostty":
+		pkgs = append(pkgs, choices.Terminal)
+	}
+	if choices.InstallFont {
+		pkgs = append(pkgs, selectedFont(choices).Nix)
+	}
+
+	switch choices.Shell {
+	case "fish":
+		pkgs = append(pkgs, "fish", "carapace", "zoxide", "atuin", "starship")
+	case "zsh":
+		pkgs = append(pkgs, "zsh", "carapace", "zoxide", "atuin", "zsh-autosuggestions", "zsh-syntax-highlighting", "zsh-autocomplete", "zsh-powerlevel10k")
+	case "nushell":
+		pkgs = append(pkgs, "nushell", "carapace", "zoxide", "atuin", "jq", "bash", "starship")
+	case "bash":
+		pkgs = append(pkgs, "bash", "bash-completion", "bash-preexec", "carapace", "zoxide", "atuin", "starship")
+	}
+
+	switch choices.WindowMgr {
+	case "tmux", "zellij":
+		pkgs = append(pkgs, choices.WindowMgr)
+	}
+
+	if choices.InstallNvim {
+		pkgs = append(pkgs, "neovim", "git", "gcc", "fzf", "fd", "ripgrep", "coreutils", "bat", "curl", "lazygit", "tree-sitter", "nodejs")
+	}
+	return pkgs
+}
+
+// nixEntries returns the config files the module declares for the chosen tools
+func nixEntries(choices UserChoices) []nixEntry {
+	var entries []nixEntry
+	switch choices.Terminal {
+	case "alacritty":
+		entries = append(entries, nixEntry{attr: "xdg.configFile", target: "alacritty/alacritty.toml", source: "alacritty.toml"})
+	case "wezterm":
+		entries = append(entries, nixEntry{attr: "xdg.configFile", target: "wezterm/wezterm.lua", source: ".wezterm.lua"})
+	case "kitty":
+		entries = append(entries, nixEntry{attr: "xdg.configFile", target: "kitty", source: "GentlemanKitty", dir: true})
+	case "ghostty":
+		entries = append(entries, nixEntry{attr: "xdg.configFile", target: "ghostty", source: "GentlemanGhostty", dir: true})
+	}
+
+	starship := nixEntry{attr: "xdg.configFile", target: "starship.toml", source: "starship.toml"}
+	switch choices.Shell {
+	case "fish":
+		entries = append(entries, starship,
+			nixEntry{attr: "xdg.configFile", target: "fish", source: "GentlemanFish/fish", dir: true})
+	case "zsh":
+		entries = append(entries,
+			nixEntry{attr: "home.file", target: ".zshrc", source: "GentlemanZsh/.zshrc"},
+			nixEntry{attr: "home.file", target: ".p10k.zsh", source: "GentlemanZsh/.p10k.zsh"},
+			nixEntry{attr: "home.file", target: ".oh-my-zsh", source: "GentlemanZsh/.oh-my-zsh", dir: true})
+	case "nushell":
+		nuAttr, nuTarget := "xdg.configFile", "nushell"
+		if runtime.GOOS == "darwin" {
+			nuAttr, nuTarget = "home.file", "Library/Application Support/nushell"
+		}
+		entries = append(entries, starship,
+			nixEntry{attr: "xdg.configFile", target: "bash-env-json", source: "bash-env-json"},
+			nixEntry{attr: "xdg.configFile", target: "bash-env.nu", source: "bash-env.nu"},
+			nixEntry{attr: nuAttr, target: nuTarget, source: "GentlemanNushell", dir: true})
+	case "bash":
+		entries = append(entries, starship,
+			nixEntry{attr: "home.file", target: ".bashrc", source: "GentlemanBash/.bashrc"},
+			nixEntry{attr: "home.file", target: ".bash-preexec.sh", expr: `"${pkgs.bash-preexec}/share/bash/bash-preexec.sh"`})
+	}
+
+	switch choices.WindowMgr {
+	case "tmux":
+		entries = append(entries,
+			nixEntry{attr: "home.file", target: ".tmux.conf", source: "GentlemanTmux/tmux.conf"},
+			nixEntry{attr: "home.file", target: ".tmux/plugins", source: "GentlemanTmux/plugins", dir: true})
+	case "zellij":
+		entries = append(entries, nixEntry{attr: "xdg.configFile", target: "zellij", source: "GentlemanZellij/zellij", dir: true})
+	}
+
+	if choices.InstallNvim {
+		entries = append(entries, nixEntry{attr: "xdg.configFile", target: "nvim", source: "GentlemanNvim/nvim", dir: true})
+	}
+	return entries
+}
+
+// nixProfileBin returns where home-manager puts package binaries
+func nixProfileBin() string {
+	perUser := filepath.Join("/etc/profiles/per-user", os.Getenv("USER"), "bin")
+	if os.Getenv("USER") != "" && system.FileExists(perUser) {
+		return perUser
+	}
+	return filepath.Join(os.Getenv("HOME"), ".nix-profile", "bin")
+}
+
+// renderHomeManagerModule writes the module declaring packages and config links
+func renderHomeManagerModule(choices UserChoices, entries []nixEntry) string {
+	var s strings.Builder
+	s.WriteString("# Generated by gentleman.dots. Re-run the installer to regenerate;\n")
+	s.WriteString("# local edits to this directory are overwritten.\n")
+	s.WriteString("{ lib, pkgs, ... }:\n\n{\n")
+
+	if choices.WindowMgr == "herdr" {
+		s.WriteString("  # Herdr is not in nixpkgs; the shipped module installs it on activation\n")
+		s.WriteString("  imports = [ ./herdr.nix ];\n\n")
+	}
+
+	s.WriteString("  home.packages = with pkgs; [\n")
+	for _, pkg := range nixPackages(choices) {
+		fmt.Fprintf(&s, "    %s\n", pkg)
+	}
+	s.WriteString("  ];\n")
+
+	if choices.InstallFont {
+		s.WriteString("\n  fonts.fontconfig.enable = true;\n")
+	}
+
+	if len(entries) > 0 {
+		s.WriteString("\n")
+	}
+	for _, e := range entries {
+		source := e.expr
+		if source == "" {
+			source = "./" + filepath.ToSlash(e.localPath())
+			if strings.ContainsAny(source, " ") {
+				source = fmt.Sprintf("./. + %q", "/"+filepath.ToSlash(e.localPath()))
+			}
+		}
+		if e.dir {
+			fmt.Fprintf(&s, "  %s.%q = {\n    source = %s;\n    recursive = true;\n  };\n", e.attr, e.target, source)
+		} else {
+			fmt.Fprintf(&s, "  %s.%q.source = %s;\n", e.attr, e.target, source)
+		}
+	}
+
+	if choices.WindowMgr == "tmux" {
+		fmt.Fprintf(&s, `
+  # TPM is cloned on activation so tmux can install the remaining plugins
+  home.activation.gentlemanTpm = lib.hm.dag.entryAfter [ "linkGeneration" ] ''
+    if [ ! -d "$HOME/.tmux/plugins/tpm/.git" ]; then
+      ${pkgs.git}/bin/git clone --depth 1 %s "$HOME/.tmux/plugins/tpm" || true
+    fi
+  '';
+`, system.GitHubURL(tpmRepoURL))
+	}
+
+	s.WriteString("}\n")
+	return s.String()
+}
+
+// nixFlake exposes the generated module to flake-based home-manager setups
+const nixFlake = `{
+  description = "Gentleman.Dots home-manager module (generated by gentleman.dots)";
+
+  outputs = { self }: {
+    homeManagerModules.default = import ./default.nix;
+  };
+}
+`
+
+// stepGenerateNix writes a home-manager module instead of installing imperatively
+func stepGenerateNix(m *Model) error {
+	stepID := "nix"
+	repoDir := repoPath()
+	outDir := NixOutputDir()
+	entries := nixEntries(m.Choices)
+
+	SendLog(stepID, fmt.Sprintf("Generating home-manager module in %s...", outDir))
+	if err := system.EnsureDir(outDir); err != nil {
+		return wrapStepError(stepID, "Generate Nix Module",
+			"Failed to create the output directory",
+			err)
+	}
+	// Start from a clean tree so deselected tools disappear on regeneration
+	for _, dir := range []string{"config", "home", "herdr"} {
+		os.RemoveAll(filepath.Join(outDir, dir))
+	}
+
+	for _, e := range entries {
+		if e.source == "" {
+			continue
+		}
+		src := filepath.Join(repoDir, e.source)
+		dst := filepath.Join(outDir, e.localPath())
+		SendLog(stepID, fmt.Sprintf("  → %s", e.target))
+		var err error
+		if e.dir {
+			err = system.CopyDir(src, dst)
+		} else {
+			err = system.CopyFile(src, dst)
+		}
+		if err != nil {
+			return wrapStepError(stepID, "Generate Nix Module",
+				fmt.Sprintf("Failed to copy %s", e.source),
+				err)
+		}
+	}
+
+	SendLog(stepID, "Applying shell and window manager settings...")
+	if err := patchNixConfigs(m, outDir, entries); err != nil {
+		return wrapStepError(stepID, "Generate Nix Module",
+			"Failed to apply window manager settings",
+			err)
+	}
+
+	if m.Choices.WindowMgr == "herdr" {
+		if err := system.CopyFile(filepath.Join(repoDir, "herdr.nix"), filepath.Join(outDir, "herdr.nix")); err != nil {
+			return wrapStepError(stepID, "Generate Nix Module",
+				"Failed to copy herdr.nix",
+				err)
+		}
+		if err := system.CopyFile(filepath.Join(repoDir, "herdr", "config.toml"), filepath.Join(outDir, "herdr", "config.toml")); err != nil {
+			return wrapStepError(stepID, "Generate Nix Module",
+				"Failed to copy Herdr configuration",
+				err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(outDir, "default.nix"), []byte(renderHomeManagerModule(m.Choices, entries)), 0644); err != nil {
+		return wrapStepError(stepID, "Generate Nix Module",
+			"Failed to write default.nix",
+			err)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "flake.nix"), []byte(nixFlake), 0644); err != nil {
+		return wrapStepError(stepID, "Generate Nix Module",
+			"Failed to write flake.nix",
+			err)
+	}
+
+	SendLog(stepID, fmt.Sprintf("✓ Add `imports = [ %s ];` to your home-manager config", outDir))
+	return nil
+}
+
+// patchNixConfigs applies the same WM/shell patches the imperative install
+// makes, on the copies inside the module directory
+func patchNixConfigs(m *Model, outDir string, entries []nixEntry) error {
+	wm := m.Choices.WindowMgr
+	local := func(target string) string {
+		for _, e := range entries {
+			if e.target == target {
+				return filepath.Join(outDir, e.localPath())
+			}
+		}
+		return ""
+	}
+
+	switch m.Choices.Shell {
+	case "fish":
+		fishDir := local("fish")
+		if err := system.PatchFishForWM(filepath.Join(fishDir, "config.fish"), wm, m.Choices.InstallNvim); err != nil {
+			return err
+		}
+		if wm != "tmux" {
+			os.Remove(filepath.Join(fishDir, "functions", "tmux.fish"))
+		}
+	case "zsh":
+		if err := system.PatchZshForWM(local(".zshrc"), wm, m.Choices.InstallNvim); err != nil {
+			return err
+		}
+	case "nushell":
+		nuTarget := "nushell"
+		if runtime.GOOS == "darwin" {
+			nuTarget = "Library/Application Support/nushell"
+		}
+		if err := system.PatchNushellForWM(filepath.Join(local(nuTarget), "config.nu"), wm); err != nil {
+			return err
+		}
+	case "bash":
+		if err := system.PatchBashForWM(local(".bashrc"), wm, m.Choices.InstallNvim); err != nil {
+			return err
+		}
+	}
+
+	shellName := shellBinary(m.Choices.Shell)
+	switch wm {
+	case "tmux":
+		return system.SetTmuxDefaultShell(local(".tmux.conf"), filepath.Join(nixProfileBin(), shellName))
+	case "zellij":
+		return system.SetZellijDefaultShell(filepath.Join(local("zellij"), "config.kdl"), shellName)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// useRepoCheckout points repoPath at this checkout and the module output at a temp dir
+func useRepoCheckout(t *testing.T) string {
+	t.Helper()
+	originalLink, originalNix := linkMode, nixOutput
+	t.Cleanup(func() {
+		linkMode = originalLink
+		nixOutput = originalNix
+	})
+	if _, err := os.Stat("../../../GentlemanZsh/.zshrc"); err != nil {
+		t.Skipf("repository configs not available: %v", err)
+	}
+	SetLinkMode(true, "../../..")
+	out := filepath.Join(t.TempDir(), "gentleman")
+	SetNixOutputDir(out)
+	return out
+}
+
+func TestStepGenerateNix(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	out := useRepoCheckout(t)
+	m := &Model{
+		SystemInfo: &system.SystemInfo{DistroFamily: system.FamilyNixOS},
+		Choices:    UserChoices{Terminal: "kitty", Shell: "zsh", WindowMgr: "tmux", InstallFont: true, NixMode: true},
+	}
+
+	if err := stepGenerateNix(m); err != nil {
+		t.Fatalf("stepGenerateNix failed: %v", err)
+	}
+
+	module, err := os.ReadFile(filepath.Join(out, "default.nix"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"    kitty\n",
+		"    zsh-powerlevel10k\n",
+		"    nerd-fonts.iosevka-term\n",
+		"fonts.fontconfig.enable = true;",
+		`home.file.".zshrc".source = ./home/.zshrc;`,
+		`xdg.configFile."kitty" = {`,
+		"home.activation.gentlemanTpm",
+	} {
+		if !strings.Contains(string(module), want) {
+			t.Errorf("module missing %q:\n%s", want, module)
+		}
+	}
+	if !system.FileExists(filepath.Join(out, "flake.nix")) {
+		t.Error("expected flake.nix next to the module")
+	}
+
+	zshrc, _ := os.ReadFile(filepath.Join(out, "home", ".zshrc"))
+	if got := system.ConfiguredWM(string(zshrc), system.SyntaxSh); got != "tmux" {
+		t.Errorf("expected the copied .zshrc to start tmux, got %q", got)
+	}
+	tmux, _ := os.ReadFile(filepath.Join(out, "home", ".tmux.conf"))
+	if !strings.Contains(string(tmux), "/bin/zsh\"") {
+		t.Errorf("expected tmux default shell from the Nix profile, got:\n%s", tmux)
+	}
+
+	// Regenerating for zellij drops the tmux files
+	m.Choices.WindowMgr = "zellij"
+	if err := stepGenerateNix(m); err != nil {
+		t.Fatalf("regeneration failed: %v", err)
+	}
+	if system.FileExists(filepath.Join(out, "home", ".tmux.conf")) {
+		t.Error("stale tmux.conf should be removed on regeneration")
+	}
+	kdl, _ := os.ReadFile(filepath.Join(out, "config", "zellij", "config.kdl"))
+	if !strings.Contains(string(kdl), `default_shell "zsh"`) {
+		t.Errorf("expected zellij default shell, got:\n%s", kdl)
+	}
+}
+
+func TestRenderHomeManagerModule_Herdr(t *testing.T) {
+	choices := UserChoices{Shell: "bash", WindowMgr: "herdr"}
+	module := renderHomeManagerModule(choices, nixEntries(choices))
+	if !strings.Contains(module, "imports = [ ./herdr.nix ];") {
+		t.Errorf("expected herdr.nix import:\n%s", module)
+	}
+	if !strings.Contains(module, `home.file.".bash-preexec.sh".source = "${pkgs.bash-preexec}/share/bash/bash-preexec.sh";`) {
+		t.Errorf("expected bash-preexec from nixpkgs:\n%s", module)
+	}
+}
+
+func TestNixModeScreenFlow(t *testing.T) {
+	m := NewModel()
+	m.SystemInfo = &system.SystemInfo{DistroFamily: system.FamilyNixOS}
+	m.Choices = UserChoices{OS: "linux", Terminal: "none", Shell: "fish", WindowMgr: "zellij"}
+	m.Screen = ScreenNvimSelect
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenNixMode {
+		t.Fatalf("expected ScreenNixMode on NixOS, got %v", m.Screen)
+	}
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenPreflight || !m.Choices.NixMode {
+		t.Fatalf("expected pre-flight checks before generation, got screen %v", m.Screen)
+	}
+
+	result, _ = m.Update(preflightDoneMsg{})
+	m = result.(Model)
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenInstalling || cmd == nil {
+		t.Fatalf("expected module generation to start, got screen %v", m.Screen)
+	}
+	if got := strings.Join(stepIDs(m.Steps), ","); got != "clone,nix,cleanup" {
+		t.Errorf("unexpected steps: %s", got)
+	}
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"runtime"
+	"time"
 
 	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
 )
@@ -34,28 +35,82 @@ func RunNonInteractive(choices UserChoices) error {
 		model.ExistingConfigs = system.DetectExistingConfigs()
 	}
 
+	// Stop before touching anything if the system can't complete the install
+	checks := runPreflightChecks(model)
+	printPreflightReport(checks)
+	fmt.Printf("📐 Estimated: %s\n\n", estimateInstall(model))
+	if preflightHasFailures(checks) {
+		if !skipPreflight {
+			return fmt.Errorf("pre-flight checks failed (rerun with --skip-preflight to install anyway)")
+		}
+		fmt.Println("⚠️  Continuing despite failed checks (--skip-preflight)")
+		fmt.Println()
+	}
+
 	// Define steps to run based on choices
 	steps := buildStepsForChoices(model)
 
+	if err := runStepsNonInteractive(model, steps); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println("✅ Installation complete!")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	if choices.NixMode {
+		fmt.Printf("Add `imports = [ %s ];` to your home-manager config and run `home-manager switch`.\n", NixOutputDir())
+	}
+
+	return nil
+}
+
+// printPreflightReport prints pre-flight results with remediation for problems
+func printPreflightReport(checks []PreflightCheck) {
+	fmt.Println("🛫 Pre-flight checks")
+	for _, c := range checks {
+		icon := "✓"
+		switch c.Status {
+		case CheckWarn:
+			icon = "⚠"
+		case CheckFail:
+			icon = "✗"
+		}
+		fmt.Printf("    %s %s: %s\n", icon, c.Name, c.Detail)
+		if c.Status != CheckPass && c.Remediation != "" {
+			fmt.Printf("      → %s\n", c.Remediation)
+		}
+	}
+	fmt.Println()
+}
+
+// runStepsNonInteractive executes steps in order, printing progress to stdout
+func runStepsNonInteractive(model *Model, steps []InstallStep) error {
 	fmt.Printf("📋 Running %d installation steps...\n\n", len(steps))
 
-	// Execute each step
+	// Steps the TUI hands the terminal to may wait at a sudo prompt; their
+	// durations would inflate the estimates
+	plan := *model
+	plan.SetupInstallSteps()
+	interactive := map[string]bool{}
+	for _, step := range plan.Steps {
+		interactive[step.ID] = step.Interactive
+	}
+
 	for i, step := range steps {
 		fmt.Printf("[%d/%d] %s...\n", i+1, len(steps), step.Name)
 
+		started := time.Now()
 		err := executeStep(step.ID, model)
 		if err != nil {
 			fmt.Printf("    ❌ FAILED: %v\n", err)
 			return fmt.Errorf("step '%s' failed: %w", step.Name, err)
 		}
+		if !interactive[step.ID] {
+			recordStepTiming(step.ID, model, time.Since(started))
+		}
 		fmt.Printf("    ✓ Done\n")
 	}
-
-	fmt.Println()
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("✅ Installation complete!")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
 	return nil
 }
 
@@ -63,6 +118,14 @@ func RunNonInteractive(choices UserChoices) error {
 func buildStepsForChoices(m *Model) []InstallStep {
 	var steps []InstallStep
 
+	// Nix: only generate the home-manager module
+	if m.Choices.NixMode {
+		steps = append(steps, InstallStep{ID: "clone", Name: "Clone Gentleman.Dots repository"})
+		steps = append(steps, InstallStep{ID: "nix", Name: "Generate home-manager module"})
+		steps = append(steps, InstallStep{ID: "cleanup", Name: "Cleanup"})
+		return steps
+	}
+
 	// Always backup first if enabled
 	if m.Choices.CreateBackup {
 		steps = append(steps, InstallStep{ID: "backup", Name: "Backup existing configs"})
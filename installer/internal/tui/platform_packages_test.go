@@ -129,3 +129,89 @@ func TestInstallPlatformPackagesArchFallsBackToBrewWhenNativeFails(t *testing.T)
 		t.Fatalf("calls = %#v, want %#v", *calls, expected)
 	}
 }
+
+func TestInstallPlatformPackagesSUSEUsesZypper(t *testing.T) {
+	calls := withPackageCommandMocks(t, nil)
+
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSSUSE}}
+	result := installPlatformPackages(m, "shell", platformPackages{
+		Brew: "fish carapace zoxide atuin starship",
+		SUSE: "fish zoxide atuin starship",
+	}, nil)
+
+	if result.Error != nil {
+		t.Fatalf("expected zypper install to succeed, got error: %v", result.Error)
+	}
+
+	expected := []packageCommandCall{
+		{runner: "sudo", command: "zypper --non-interactive install fish zoxide atuin starship"},
+	}
+	if !reflect.DeepEqual(*calls, expected) {
+		t.Fatalf("calls = %#v, want %#v", *calls, expected)
+	}
+}
+
+func TestInstallPlatformPackagesAlpineNeverFallsBackToBrew(t *testing.T) {
+	calls := withPackageCommandMocks(t, errors.New("apk failed"))
+
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSAlpine, HasBrew: true, Libc: "musl"}}
+	result := installPlatformPackages(m, "shell", platformPackages{
+		Brew:   "fish carapace zoxide atuin starship",
+		Alpine: "fish zoxide atuin starship",
+	}, nil)
+
+	if result.Error == nil {
+		t.Fatal("expected apk failure to be returned")
+	}
+
+	expected := []packageCommandCall{
+		{runner: "sudo", command: "apk add --no-cache fish zoxide atuin starship"},
+	}
+	if !reflect.DeepEqual(*calls, expected) {
+		t.Fatalf("calls = %#v, want %#v", *calls, expected)
+	}
+}
+
+func TestInstallPlatformPackagesVoidUsesXbps(t *testing.T) {
+	calls := withPackageCommandMocks(t, nil)
+
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSVoid, DistroFamily: system.FamilyVoid}}
+	result := installPlatformPackages(m, "shell", platformPackages{
+		Brew:   "fish carapace zoxide atuin starship",
+		Debian: "fish zoxide starship",
+		Void:   "fish-shell zoxide atuin starship",
+	}, nil)
+
+	if result.Error != nil {
+		t.Fatalf("expected xbps install to succeed, got error: %v", result.Error)
+	}
+
+	expected := []packageCommandCall{
+		{runner: "sudo", command: "xbps-install -y fish-shell zoxide atuin starship"},
+	}
+	if !reflect.DeepEqual(*calls, expected) {
+		t.Fatalf("calls = %#v, want %#v", *calls, expected)
+	}
+}
+
+func TestInstallPlatformPackagesNixOSNeverUsesApt(t *testing.T) {
+	calls := withPackageCommandMocks(t, nil)
+
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSLinux, DistroFamily: system.FamilyNixOS}}
+	result := installPlatformPackages(m, "shell", platformPackages{
+		Debian: "fish zoxide starship",
+	}, nil)
+
+	if !errors.Is(result.Error, errNixOSImperative) {
+		t.Fatalf("expected the NixOS refusal, got %v", result.Error)
+	}
+	if len(*calls) != 0 {
+		t.Fatalf("expected no package commands, got %#v", *calls)
+	}
+	if c, ok := checkNixOS(&Model{SystemInfo: m.SystemInfo}); !ok || c.Status != CheckFail {
+		t.Errorf("expected pre-flight to refuse NixOS without --nix, got %+v", c)
+	}
+	if _, ok := checkNixOS(&Model{SystemInfo: m.SystemInfo, Choices: UserChoices{NixMode: true}}); ok {
+		t.Error("expected no NixOS check when generating the module")
+	}
+}
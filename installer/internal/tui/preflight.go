@@ -0,0 +1,514 @@
+package tui
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CheckStatus is the outcome of a single pre-flight check
+type CheckStatus int
+
+const (
+	CheckPass CheckStatus = iota
+	CheckWarn
+	CheckFail
+)
+
+// PreflightCheck is one line of the pre-flight report
+type PreflightCheck struct {
+	Name        string
+	Status      CheckStatus
+	Detail      string
+	Remediation string // shown for warnings and failures
+}
+
+// preflightAllowScriptsOption opts in to unverifiable install scripts for this run
+const preflightAllowScriptsOption = "🔓 Allow install scripts for this run"
+
+// preflightAllowUnpinnedOption opts in to downloads without a pinned checksum for this run
+const preflightAllowUnpinnedOption = "🔓 Trust the checksums releases publish for this run"
+
+// skipPreflight lets --non-interactive runs continue past failed checks
+var skipPreflight bool
+
+// SetSkipPreflight makes --non-interactive runs report failed checks without stopping (--skip-preflight)
+func SetSkipPreflight(skip bool) {
+	skipPreflight = skip
+}
+
+// preflightDoneMsg carries the results of an asynchronous pre-flight run
+type preflightDoneMsg struct {
+	checks []PreflightCheck
+}
+
+var (
+	// preflightProbe reports whether url answers through the configured
+	// proxy and CA bundle; any HTTP response counts
+	preflightProbe = func(url string) error {
+		resp, err := system.HTTPClient(5 * time.Second).Head(url)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+	preflightRun      = system.Run
+	preflightLookPath = system.CommandExists
+)
+
+// preflightCmd runs the checks off the UI goroutine
+func preflightCmd(m Model) tea.Cmd {
+	return func() tea.Msg {
+		return preflightDoneMsg{checks: runPreflightChecks(&m)}
+	}
+}
+
+// runPreflightChecks validates the system against the current choices
+func runPreflightChecks(m *Model) []PreflightCheck {
+	checks := []PreflightCheck{
+		checkHomeWritable(),
+		checkSudo(m),
+		checkDiskSpace(m),
+	}
+	checks = append(checks, checkNetworkConfig()...)
+	checks = append(checks, checkNetwork(m)...)
+	checks = append(checks, checkBaseCommands(m))
+	if check, ok := checkNixOS(m); ok {
+		checks = append(checks, check)
+	}
+	if check, ok := checkInstallScripts(m); ok {
+		checks = append(checks, check)
+	}
+	if check, ok := checkUnpinnedDownloads(m); ok {
+		checks = append(checks, check)
+	}
+	checks = append(checks, checkTruecolor(), checkNerdFont(m))
+	checks = append(checks, checkConflicts(m)...)
+	return checks
+}
+
+// preflightHasFailures reports whether any check failed
+func preflightHasFailures(checks []PreflightCheck) bool {
+	for _, c := range checks {
+		if c.Status == CheckFail {
+			return true
+		}
+	}
+	return false
+}
+
+// needsSudo reports whether the chosen install runs system package managers
+func needsSudo(m *Model) bool {
+	return m.Choices.OS == "linux" && !m.SystemInfo.IsTermux && !m.Choices.NixMode
+}
+
+// hasDepsStep reports whether missing base commands get installed for us
+func hasDepsStep(m *Model) bool {
+	return (m.Choices.OS == "linux" || m.Choices.OS == "termux" || m.SystemInfo.IsTermux) && !m.Choices.NixMode
+}
+
+// usesHomebrew reports whether packages will come from Homebrew
+func usesHomebrew(m *Model) bool {
+	if m.Choices.NixMode || m.SystemInfo.IsTermux {
+		return false
+	}
+	return m.SystemInfo.OS == system.OSMac || !usesNativePackageManager(m.SystemInfo.OS)
+}
+
+func checkHomeWritable() PreflightCheck {
+	check := PreflightCheck{Name: "Home directory"}
+	home := os.Getenv("HOME")
+	f, err := os.CreateTemp(home, ".gentleman-preflight-*")
+	if err != nil {
+		check.Status = CheckFail
+		check.Detail = fmt.Sprintf("%s is not writable", home)
+		check.Remediation = "Fix ownership with: sudo chown -R \"$USER\" \"$HOME\""
+		return check
+	}
+	f.Close()
+	os.Remove(f.Name())
+	check.Detail = home + " is writable"
+	return check
+}
+
+func checkSudo(m *Model) PreflightCheck {
+	check := PreflightCheck{Name: "sudo"}
+	switch {
+	case !needsSudo(m):
+		check.Detail = "not needed"
+	case m.SystemInfo.IsRoot:
+		check.Detail = "running as root"
+	case !m.SystemInfo.HasSudo:
+		check.Status = CheckFail
+		check.Detail = "sudo is not installed"
+		check.Remediation = "Install sudo and add your user to the wheel/sudo group, or run the installer as root"
+	case preflightRun("sudo -n true", nil).Error == nil:
+		check.Detail = "passwordless (NOPASSWD or cached credentials)"
+	default:
+		check.Detail = "available, will ask for your password"
+	}
+	return check
+}
+
+// parseDfAvailableKB reads the "Available" column of `df -Pk` output
+func parseDfAvailableKB(output string) (uint64, bool) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return 0, false
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 4 {
+		return 0, false
+	}
+	kb, err := strconv.ParseUint(fields[3], 10, 64)
+	return kb, err == nil
+}
+
+func checkDiskSpace(m *Model) PreflightCheck {
+	check := PreflightCheck{Name: "Disk space"}
+	need := estimateInstall(m).DiskMB
+	result := preflightRun("df -Pk "+shellQuote(os.Getenv("HOME")), nil)
+	kb, ok := parseDfAvailableKB(result.Output)
+	if result.Error != nil || !ok {
+		check.Status = CheckWarn
+		check.Detail = fmt.Sprintf("could not read free space (need ~%d MB)", need)
+		check.Remediation = "Make sure $HOME has room for the selected tools"
+		return check
+	}
+	free := int(kb / 1024)
+	check.Detail = fmt.Sprintf("%d MB free, ~%d MB needed", free, need)
+	switch {
+	case free < need:
+		check.Status = CheckFail
+		check.Remediation = fmt.Sprintf("Free at least %d MB or deselect Neovim/terminal builds", need-free)
+	case free < need*2:
+		check.Status = CheckWarn
+		check.Remediation = "Space is tight; package caches may need more during install"
+	}
+	return check
+}
+
+// defaultPackageMirror returns the distro's package server
+func defaultPackageMirror(info *system.SystemInfo) string {
+	if info.IsTermux {
+		return "https://packages.termux.dev"
+	}
+	switch info.OS {
+	case system.OSArch:
+		return "https://geo.mirror.pkgbuild.com"
+	case system.OSFedora:
+		return "https://mirrors.fedoraproject.org"
+	case system.OSSUSE:
+		return "https://download.opensuse.org"
+	case system.OSAlpine:
+		return "https://dl-cdn.alpinelinux.org"
+	case system.OSVoid:
+		return "https://repo-default.voidlinux.org"
+	case system.OSDebian:
+		switch info.DistroID {
+		case "ubuntu", "pop", "linuxmint", "elementary":
+			return "http://archive.ubuntu.com"
+		}
+		return "http://deb.debian.org"
+	}
+	return ""
+}
+
+// checkNetworkConfig validates the proxy settings before they are used
+func checkNetworkConfig() []PreflightCheck {
+	cfg := system.Network()
+	var checks []PreflightCheck
+	for _, proxy := range []struct{ name, url string }{{"HTTP proxy", cfg.HTTPProxy}, {"HTTPS proxy", cfg.HTTPSProxy}} {
+		if proxy.url == "" {
+			continue
+		}
+		check := PreflightCheck{Name: proxy.name, Detail: proxy.url}
+		if u, err := url.Parse(proxy.url); err != nil || u.Scheme == "" || u.Host == "" {
+			check.Status = CheckFail
+			check.Detail = proxy.url + " is not a valid proxy URL"
+			check.Remediation = "Use the form http://host:port (set with --proxy or " + system.NetworkProfilePath() + ")"
+		}
+		checks = append(checks, check)
+	}
+	if cfg.CABundle != "" {
+		checks = append(checks, PreflightCheck{Name: "CA bundle", Detail: "trusting extra certificates from " + cfg.CABundle})
+	}
+	return checks
+}
+
+func checkNetwork(m *Model) []PreflightCheck {
+	type target struct {
+		name, url, remediation string
+	}
+	cfg := system.Network()
+	githubHint := "The repository and release downloads come from GitHub; check your connection, set --proxy/--ca-bundle, or use --github-mirror"
+	targets := []target{{"GitHub", system.GitHubURL("https://github.com/"), githubHint}}
+	if usesHomebrew(m) {
+		targets = append(targets, target{"Homebrew", "https://formulae.brew.sh", "Homebrew formulae are unreachable; check your proxy settings"})
+		if cfg.BrewBottleMirror != "" {
+			targets = append(targets, target{"Homebrew bottles", cfg.BrewBottleMirror, "Check the --brew-mirror URL"})
+		}
+	}
+	if mirror := cfg.PackageMirror; mirror != "" || hasDepsStep(m) {
+		if mirror == "" {
+			mirror = defaultPackageMirror(m.SystemInfo)
+		}
+		if mirror != "" {
+			targets = append(targets, target{"Package mirror", mirror, "Pass --mirror=<url> to use a reachable local mirror"})
+		}
+	}
+
+	// Offline machines provisioned from a cache bundle expect every probe to fail
+	offline := system.HasGitMirror(gentlemanRepoURL)
+	var checks []PreflightCheck
+	for _, t := range targets {
+		check := PreflightCheck{Name: t.name, Detail: t.url + " reachable"}
+		if err := preflightProbe(t.url); err != nil {
+			check.Status = CheckFail
+			check.Detail = fmt.Sprintf("%s unreachable", t.url)
+			check.Remediation = t.remediation
+			if offline {
+				check.Status = CheckWarn
+				check.Detail += ", using the download cache"
+				check.Remediation = "Only downloads already in " + system.CacheDir() + " and packages already installed will work"
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// installScriptsNeeded lists the vendor `curl | sh` installers the plan would run.
+// Optional ones are skipped when refused; required ones fail their step.
+func installScriptsNeeded(m *Model) (required, optional []string) {
+	info := m.SystemInfo
+	if m.Choices.NixMode || info == nil {
+		return nil, nil
+	}
+	if !info.HasBrew && !info.IsTermux && !usesNativePackageManager(info.OS) && info.DistroFamily != system.FamilyNixOS && !system.CommandExists("brew") {
+		required = append(required, "Homebrew")
+	}
+	debianLike := m.Choices.OS == "linux" && (info.OS == system.OSDebian || info.OS == system.OSLinux)
+	switch {
+	case !debianLike:
+	case m.Choices.Terminal == "alacritty" && !system.CommandExists("alacritty") &&
+		!system.CommandExists("cargo") && !system.FileExists(filepath.Join(os.Getenv("HOME"), ".cargo/bin/cargo")):
+		required = append(required, "rustup")
+	case m.Choices.Terminal == "ghostty" && !system.CommandExists("ghostty"):
+		required = append(required, "Ghostty (ghostty-ubuntu)")
+	}
+	if m.Choices.InstallNvim && !info.IsTermux {
+		optional = append(optional, "Claude Code", "OpenCode")
+	}
+	return required, optional
+}
+
+// checkInstallScripts reports unverifiable installers and whether policy allows them
+func checkInstallScripts(m *Model) (PreflightCheck, bool) {
+	required, optional := installScriptsNeeded(m)
+	if len(required)+len(optional) == 0 {
+		return PreflightCheck{}, false
+	}
+	check := PreflightCheck{Name: "Install scripts"}
+	all := strings.Join(append(append([]string{}, required...), optional...), ", ")
+	switch {
+	case system.InstallScriptsAllowed():
+		check.Detail = "allowed, runs unverified: " + all
+	case len(required) > 0:
+		check.Status = CheckFail
+		check.Detail = "refused: " + strings.Join(required, ", ")
+		check.Remediation = "Choose \"Allow install scripts\" below or pass --allow-install-scripts"
+	default:
+		check.Status = CheckWarn
+		check.Detail = "refused, will skip: " + strings.Join(optional, ", ")
+		check.Remediation = "Choose \"Allow install scripts\" below or pass --allow-install-scripts to include them"
+	}
+	return check, true
+}
+
+// scriptsBlocked reports whether allowing install scripts would change the plan
+func scriptsBlocked(m *Model) bool {
+	if system.InstallScriptsAllowed() {
+		return false
+	}
+	required, optional := installScriptsNeeded(m)
+	return len(required)+len(optional) > 0
+}
+
+// unpinnedDownloadsNeeded lists the downloads of the plan that have no
+// SHA256 pinned in the installer. Optional ones are skipped when refused.
+func unpinnedDownloadsNeeded(m *Model) (required, optional []string) {
+	info := m.SystemInfo
+	if m.Choices.NixMode || info == nil {
+		return nil, nil
+	}
+	if m.Choices.InstallFont && m.Choices.OS != "mac" {
+		font, version := selectedFont(m.Choices), selectedFontVersion(m.Choices)
+		if nerdFontArtifact(version, font.Archive).SHA256 == "" {
+			required = append(required, font.Label+" "+version)
+		}
+	}
+	debianLike := m.Choices.OS == "linux" && (info.OS == system.OSDebian || info.OS == system.OSLinux)
+	if m.Choices.Terminal == "kitty" && debianLike && !system.FileExists(filepath.Join(kittyAppDir(), "bin", "kitty")) {
+		if a, err := kittyBundleArtifact(kittyVersion, runtime.GOARCH); err == nil && a.SHA256 == "" {
+			required = append(required, "Kitty "+kittyVersion)
+		}
+	}
+	if m.Choices.WindowMgr == "herdr" && herdrReleaseUnpinned(info) {
+		required = append(required, "Herdr "+herdrTarget())
+	}
+	return required, optional
+}
+
+// checkUnpinnedDownloads reports downloads only their release's own checksums can verify
+func checkUnpinnedDownloads(m *Model) (PreflightCheck, bool) {
+	required, optional := unpinnedDownloadsNeeded(m)
+	if len(required)+len(optional) == 0 {
+		return PreflightCheck{}, false
+	}
+	check := PreflightCheck{Name: "Pinned checksums"}
+	all := strings.Join(append(append([]string{}, required...), optional...), ", ")
+	switch {
+	case system.UnpinnedAllowed():
+		check.Detail = "allowed, checked against their release's checksums: " + all
+	case len(required) > 0:
+		check.Status = CheckFail
+		check.Detail = "no pinned checksum: " + strings.Join(required, ", ")
+		check.Remediation = "Choose \"Trust the checksums releases publish\" below or pass --allow-unpinned"
+	default:
+		check.Status = CheckWarn
+		check.Detail = "no pinned checksum, will skip: " + strings.Join(optional, ", ")
+		check.Remediation = "Choose \"Trust the checksums releases publish\" below or pass --allow-unpinned to include them"
+	}
+	return check, true
+}
+
+// unpinnedBlocked reports whether allowing unpinned downloads would change the plan
+func unpinnedBlocked(m *Model) bool {
+	if system.UnpinnedAllowed() {
+		return false
+	}
+	required, optional := unpinnedDownloadsNeeded(m)
+	return len(required)+len(optional) > 0
+}
+
+// checkNixOS refuses imperative installs on NixOS, where only the
+// home-manager module can install packages
+func checkNixOS(m *Model) (PreflightCheck, bool) {
+	if m.SystemInfo == nil || m.SystemInfo.DistroFamily != system.FamilyNixOS || m.Choices.NixMode {
+		return PreflightCheck{}, false
+	}
+	return PreflightCheck{Name: "NixOS", Status: CheckFail,
+		Detail:      "system packages cannot be installed imperatively",
+		Remediation: "Go back and generate the home-manager module, or pass --nix"}, true
+}
+
+func checkBaseCommands(m *Model) PreflightCheck {
+	check := PreflightCheck{Name: "Base commands"}
+	required := []string{"git", "curl"}
+	var missing []string
+	for _, cmd := range required {
+		if !preflightLookPath(cmd) {
+			missing = append(missing, cmd)
+		}
+	}
+	if len(missing) == 0 {
+		check.Detail = strings.Join(required, ", ") + " found"
+		return check
+	}
+	check.Detail = "missing: " + strings.Join(missing, ", ")
+	switch {
+	case hasDepsStep(m):
+		check.Status = CheckWarn
+		check.Remediation = "They will be installed by the Install Dependencies step"
+	case m.SystemInfo.OS == system.OSMac && !m.SystemInfo.HasXcode:
+		check.Status = CheckWarn
+		check.Remediation = "They come with the Xcode Command Line Tools, installed first"
+	default:
+		check.Status = CheckFail
+		check.Remediation = "Install " + strings.Join(missing, " and ") + " and run the installer again"
+	}
+	return check
+}
+
+func checkTruecolor() PreflightCheck {
+	check := PreflightCheck{Name: "Truecolor"}
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		check.Detail = "supported"
+	default:
+		check.Status = CheckWarn
+		check.Detail = "COLORTERM does not advertise 24-bit color"
+		check.Remediation = "Themes may look off in this terminal; the installed terminal emulator supports truecolor"
+	}
+	return check
+}
+
+// nerdFontInstalled looks for any Nerd Font known to the system
+func nerdFontInstalled() bool {
+	if runtime.GOOS == "darwin" {
+		for _, dir := range []string{filepath.Join(os.Getenv("HOME"), "Library/Fonts"), "/Library/Fonts"} {
+			if matches, _ := filepath.Glob(filepath.Join(dir, "*Nerd*")); len(matches) > 0 {
+				return true
+			}
+		}
+		return false
+	}
+	result := preflightRun("fc-list : family", nil)
+	return result.Error == nil && strings.Contains(result.Output, "Nerd Font")
+}
+
+func checkNerdFont(m *Model) PreflightCheck {
+	check := PreflightCheck{Name: "Nerd Font"}
+	switch {
+	case m.Choices.InstallFont:
+		check.Detail = "will be installed"
+	case nerdFontInstalled():
+		check.Detail = "installed"
+	default:
+		check.Status = CheckWarn
+		check.Detail = "none found. These should be icons: \uf015 \ue7a8 \uf418 \ue62b"
+		check.Remediation = "If they render as boxes, go back and choose to install the Nerd Font"
+	}
+	return check
+}
+
+func checkConflicts(m *Model) []PreflightCheck {
+	if m.Choices.NixMode {
+		return nil
+	}
+	home := os.Getenv("HOME")
+	backupHint := "Go back and choose Install with Backup, or move it aside"
+	if m.Choices.CreateBackup {
+		backupHint = "It is included in the backup"
+	}
+
+	var checks []PreflightCheck
+	if m.Choices.Shell == "zsh" && system.FileExists(filepath.Join(home, ".oh-my-zsh")) {
+		checks = append(checks, PreflightCheck{
+			Name:        "oh-my-zsh",
+			Status:      CheckWarn,
+			Detail:      "an existing ~/.oh-my-zsh will be replaced",
+			Remediation: backupHint + "; custom plugins live in ~/.oh-my-zsh/custom",
+		})
+	}
+	nvimDir := filepath.Join(home, ".config", "nvim")
+	if m.Choices.InstallNvim && system.FileExists(nvimDir) && !system.FileExists(filepath.Join(nvimDir, "lua", "config", "gentleman")) {
+		checks = append(checks, PreflightCheck{
+			Name:        "Neovim config",
+			Status:      CheckWarn,
+			Detail:      "a non-Gentleman config in ~/.config/nvim will be replaced",
+			Remediation: backupHint + "; also clear ~/.local/share/nvim to avoid stale plugins",
+		})
+	}
+	return checks
+}
@@ -0,0 +1,267 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// withPreflightMocks stubs the probes that touch the network and shell
+func withPreflightMocks(t *testing.T, probe func(string) error, run func(string, *system.ExecOptions) *system.ExecResult) {
+	t.Helper()
+	originalProbe, originalRun, originalLook, originalNetwork := preflightProbe, preflightRun, preflightLookPath, system.Network()
+	t.Cleanup(func() {
+		preflightProbe, preflightRun, preflightLookPath = originalProbe, originalRun, originalLook
+		system.SetNetwork(originalNetwork)
+	})
+	preflightProbe = probe
+	preflightRun = run
+	preflightLookPath = func(string) bool { return true }
+}
+
+func TestParseDfAvailableKB(t *testing.T) {
+	out := "Filesystem     1024-blocks      Used Available Capacity Mounted on\n/dev/nvme0n1p2   479079112 201234568 253440012      45% /home\n"
+	kb, ok := parseDfAvailableKB(out)
+	if !ok || kb != 253440012 {
+		t.Errorf("expected 253440012 KB, got %d (ok=%v)", kb, ok)
+	}
+	if _, ok := parseDfAvailableKB("garbage"); ok {
+		t.Error("expected parse failure on malformed output")
+	}
+}
+
+func TestCheckSudo(t *testing.T) {
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSArch, HasSudo: true}, Choices: UserChoices{OS: "linux"}}
+
+	withPreflightMocks(t, nil, func(string, *system.ExecOptions) *system.ExecResult { return &system.ExecResult{} })
+	if c := checkSudo(m); c.Status != CheckPass || !strings.Contains(c.Detail, "passwordless") {
+		t.Errorf("expected passwordless pass, got %+v", c)
+	}
+
+	preflightRun = func(string, *system.ExecOptions) *system.ExecResult {
+		return &system.ExecResult{Error: errors.New("a password is required")}
+	}
+	if c := checkSudo(m); c.Status != CheckPass || !strings.Contains(c.Detail, "password") {
+		t.Errorf("expected pass with password prompt, got %+v", c)
+	}
+
+	m.SystemInfo.HasSudo = false
+	if c := checkSudo(m); c.Status != CheckFail || c.Remediation == "" {
+		t.Errorf("expected failure with remediation without sudo, got %+v", c)
+	}
+
+	m.Choices.OS = "mac"
+	if c := checkSudo(m); c.Status != CheckPass {
+		t.Errorf("sudo should not be required on mac, got %+v", c)
+	}
+}
+
+func TestCheckNetwork_Mirror(t *testing.T) {
+	var probed []string
+	withPreflightMocks(t, func(url string) error {
+		probed = append(probed, url)
+		if strings.Contains(url, "mirror.local") {
+			return errors.New("connection refused")
+		}
+		return nil
+	}, nil)
+	system.SetNetwork(system.NetworkConfig{PackageMirror: "http://mirror.local/"})
+
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSFedora}, Choices: UserChoices{OS: "linux"}}
+	checks := checkNetwork(m)
+	if len(checks) != 2 {
+		t.Fatalf("expected GitHub and mirror checks, got %+v", checks)
+	}
+	if checks[0].Status != CheckPass {
+		t.Errorf("GitHub should pass, got %+v", checks[0])
+	}
+	if checks[1].Status != CheckFail || !strings.Contains(checks[1].Remediation, "--mirror") {
+		t.Errorf("expected failing mirror with remediation, got %+v", checks[1])
+	}
+	if probed[1] != "http://mirror.local" {
+		t.Errorf("expected configured mirror to be probed, got %v", probed)
+	}
+}
+
+func TestCheckNetwork_OfflineCache(t *testing.T) {
+	withPreflightMocks(t, func(string) error { return errors.New("no route to host") }, nil)
+	cache := t.TempDir()
+	system.SetCacheDir(cache)
+	t.Cleanup(func() { system.SetCacheDir("") })
+
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSMac, HasBrew: true}, Choices: UserChoices{OS: "mac"}}
+	if checks := checkNetwork(m); checks[1].Status != CheckFail {
+		t.Fatalf("expected Homebrew to fail without a cache, got %+v", checks[1])
+	}
+
+	// A repository mirror as left by a cache bundle
+	mirror := system.GitMirrorDir(gentlemanRepoURL)
+	os.MkdirAll(mirror, 0755)
+	os.WriteFile(filepath.Join(mirror, "HEAD"), []byte("ref: refs/heads/main\n"), 0644)
+	if !system.HasGitMirror(gentlemanRepoURL) {
+		t.Fatal("expected the fake mirror to be found")
+	}
+	for _, c := range checkNetwork(m) {
+		if c.Status != CheckWarn {
+			t.Errorf("%s: expected a warning with a cached repository, got %+v", c.Name, c)
+		}
+	}
+}
+
+func TestCheckConflicts_ForeignNvim(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	os.MkdirAll(filepath.Join(home, ".config", "nvim", "lua"), 0755)
+	os.MkdirAll(filepath.Join(home, ".oh-my-zsh"), 0755)
+
+	m := &Model{SystemInfo: &system.SystemInfo{}, Choices: UserChoices{Shell: "zsh", InstallNvim: true}}
+	if got := len(checkConflicts(m)); got != 2 {
+		t.Fatalf("expected oh-my-zsh and nvim warnings, got %d", got)
+	}
+
+	// A Gentleman nvim config is not a conflict
+	os.MkdirAll(filepath.Join(home, ".config", "nvim", "lua", "config", "gentleman"), 0755)
+	checks := checkConflicts(m)
+	if len(checks) != 1 || checks[0].Name != "oh-my-zsh" {
+		t.Errorf("expected only the oh-my-zsh warning, got %+v", checks)
+	}
+}
+
+func TestPreflightScreenFlow(t *testing.T) {
+	m := NewModel()
+	m.Screen = ScreenBackupConfirm
+	m.Cursor = 1 // Install without Backup
+	m.Choices = UserChoices{OS: "mac", Shell: "fish", Terminal: "none"}
+	m.SystemInfo = &system.SystemInfo{OS: system.OSMac, HasBrew: true, HasXcode: true}
+
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenPreflight || !m.PreflightRunning || cmd == nil {
+		t.Fatalf("expected running pre-flight, got screen %v", m.Screen)
+	}
+	if opts := m.GetCurrentOptions(); len(opts) != 0 {
+		t.Errorf("no options while checks run, got %v", opts)
+	}
+
+	failed := []PreflightCheck{{Name: "GitHub", Status: CheckFail, Detail: "unreachable", Remediation: "check your proxy"}}
+	result, _ = m.Update(preflightDoneMsg{checks: failed})
+	m = result.(Model)
+	if m.PreflightRunning || !strings.Contains(m.GetCurrentOptions()[0], "anyway") {
+		t.Errorf("expected results with an override option, got %v", m.GetCurrentOptions())
+	}
+	if view := m.renderPreflight(); !strings.Contains(view, "check your proxy") {
+		t.Errorf("expected remediation in view:\n%s", view)
+	}
+
+	// Esc returns to the screen that started the checks
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if back := result.(Model); back.Screen != ScreenBackupConfirm {
+		t.Errorf("expected BackupConfirm on Esc, got %v", back.Screen)
+	}
+
+	// Failed checks need a confirmation before installing
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenPreflight || !strings.Contains(m.GetCurrentOptions()[0], "again") {
+		t.Fatalf("expected a confirmation prompt, got screen %v with %v", m.Screen, m.GetCurrentOptions())
+	}
+	result, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenInstalling || cmd == nil || len(m.Steps) == 0 {
+		t.Errorf("expected installation to start, got screen %v", m.Screen)
+	}
+}
+
+func TestCheckNetworkConfig(t *testing.T) {
+	withPreflightMocks(t, nil, nil)
+	system.SetNetwork(system.NetworkConfig{HTTPSProxy: "proxy.corp:3128", GitHubMirror: "https://git.corp/github"})
+
+	checks := checkNetworkConfig()
+	if len(checks) != 1 || checks[0].Status != CheckFail {
+		t.Fatalf("expected an invalid proxy failure, got %+v", checks)
+	}
+
+	var probed []string
+	preflightProbe = func(url string) error {
+		probed = append(probed, url)
+		return nil
+	}
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSMac, HasBrew: true}, Choices: UserChoices{OS: "mac"}}
+	checkNetwork(m)
+	if probed[0] != "https://git.corp/github/" {
+		t.Errorf("expected the GitHub mirror to be probed, got %v", probed)
+	}
+}
+
+func TestCheckInstallScripts(t *testing.T) {
+	t.Cleanup(func() { system.SetAllowInstallScripts(false) })
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSMac}, Choices: UserChoices{OS: "mac", InstallNvim: true}}
+	if system.CommandExists("brew") {
+		t.Skip("Homebrew is installed")
+	}
+
+	if c, ok := checkInstallScripts(m); !ok || c.Status != CheckFail || !strings.Contains(c.Detail, "Homebrew") {
+		t.Errorf("expected Homebrew refusal to fail, got %+v", c)
+	}
+	m.Screen = ScreenPreflight
+	if !containsOption(m.GetCurrentOptions(), preflightAllowScriptsOption) {
+		t.Error("expected an allow-scripts option on the preflight screen")
+	}
+
+	m.SystemInfo.HasBrew = true
+	if c, _ := checkInstallScripts(m); c.Status != CheckWarn || !strings.Contains(c.Detail, "Claude Code") {
+		t.Errorf("expected optional scripts to warn, got %+v", c)
+	}
+
+	// bash-preexec is a checksummed download, not an install script
+	m.Choices.Shell = "bash"
+	if c, _ := checkInstallScripts(m); strings.Contains(c.Detail, "bash-preexec") {
+		t.Errorf("expected bash-preexec not to be listed as a script, got %+v", c)
+	}
+
+	system.SetAllowInstallScripts(true)
+	if c, _ := checkInstallScripts(m); c.Status != CheckPass || scriptsBlocked(m) {
+		t.Errorf("expected allowed scripts to pass, got %+v", c)
+	}
+}
+
+func TestCheckUnpinnedDownloads(t *testing.T) {
+	t.Cleanup(func() { system.SetAllowUnpinned(false) })
+	m := &Model{SystemInfo: &system.SystemInfo{OS: system.OSDebian}, Choices: UserChoices{OS: "linux", InstallFont: true}}
+	font, version := selectedFont(m.Choices), selectedFontVersion(m.Choices)
+	if nerdFontArtifact(version, font.Archive).SHA256 != "" {
+		t.Skip("the default Nerd Font release is pinned")
+	}
+
+	if c, ok := checkUnpinnedDownloads(m); !ok || c.Status != CheckFail || !strings.Contains(c.Detail, version) {
+		t.Errorf("expected the unpinned font to fail, got %+v", c)
+	}
+	m.Screen = ScreenPreflight
+	if !containsOption(m.GetCurrentOptions(), preflightAllowUnpinnedOption) {
+		t.Error("expected an allow-unpinned option on the preflight screen")
+	}
+
+	system.SetAllowUnpinned(true)
+	if c, _ := checkUnpinnedDownloads(m); c.Status != CheckPass || unpinnedBlocked(m) {
+		t.Errorf("expected allowed unpinned downloads to pass, got %+v", c)
+	}
+
+	m.Choices.InstallFont = false
+	if _, ok := checkUnpinnedDownloads(m); ok {
+		t.Error("expected no check when nothing unpinned is planned")
+	}
+}
+
+func containsOption(options []string, want string) bool {
+	for _, o := range options {
+		if o == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,418 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// ReconfigureSettings lists the settings `gentleman.dots set` can change
+var ReconfigureSettings = append(append([]string{"wm", "shell", "theme", "layout"}, terminalSettingNames()...), herdrKeySettingNames()...)
+
+// ValidWMs lists the multiplexers accepted by `set wm`
+var ValidWMs = []string{"tmux", "zellij", "herdr", "none"}
+
+// ValidShells lists the shells accepted by `set shell`
+var ValidShells = []string{"fish", "zsh", "nushell", "bash"}
+
+// shellBackupKeys maps a shell to the system.ConfigPaths entries it owns
+var shellBackupKeys = map[string][]string{
+	"fish":    {"fish", "starship"},
+	"zsh":     {"zsh", "zsh_p10k"},
+	"nushell": {"nushell", "starship"},
+	"bash":    {"bash", "starship"},
+}
+
+// shellConfigPath returns the main config file the installer deploys for a shell
+func shellConfigPath(shell string) string {
+	homeDir := os.Getenv("HOME")
+	switch shell {
+	case "fish":
+		return filepath.Join(homeDir, ".config/fish/config.fish")
+	case "zsh":
+		return filepath.Join(homeDir, ".zshrc")
+	case "nushell":
+		return filepath.Join(nushellConfigDir(homeDir), "config.nu")
+	case "bash":
+		return filepath.Join(homeDir, ".bashrc")
+	}
+	return ""
+}
+
+// wmConfigPath returns the main config file the installer deploys for a multiplexer
+func wmConfigPath(wm string) string {
+	homeDir := os.Getenv("HOME")
+	switch wm {
+	case "tmux":
+		return filepath.Join(homeDir, ".tmux.conf")
+	case "zellij":
+		return filepath.Join(homeDir, ".config/zellij/config.kdl")
+	case "herdr":
+		return filepath.Join(homeDir, ".config/herdr/config.toml")
+	}
+	return ""
+}
+
+// installedShells returns the shells whose Gentleman configs are present
+func installedShells() []string {
+	var shells []string
+	for _, shell := range []string{"fish", "zsh", "nushell"} {
+		if system.FileExists(shellConfigPath(shell)) {
+			shells = append(shells, shell)
+		}
+	}
+	if isGentlemanBashrc() {
+		shells = append(shells, "bash")
+	}
+	return shells
+}
+
+// isGentlemanBashrc tells the Gentleman .bashrc apart from the distribution
+// default that exists on every system.
+func isGentlemanBashrc() bool {
+	data, err := os.ReadFile(shellConfigPath("bash"))
+	return err == nil && system.HasBlock(string(data), system.SyntaxSh, system.BlockMultiplexer)
+}
+
+// currentShellChoice maps the login shell to a shell choice.
+// Falls back to the first installed shell config (Termux keeps bash as login shell).
+func currentShellChoice(m *Model) string {
+	switch m.SystemInfo.UserShell {
+	case "fish", "zsh":
+		return m.SystemInfo.UserShell
+	case "nu":
+		return "nushell"
+	case "bash":
+		if isGentlemanBashrc() {
+			return "bash"
+		}
+	}
+	if shells := installedShells(); len(shells) > 0 {
+		return shells[0]
+	}
+	return ""
+}
+
+// currentWM returns the multiplexer the shell's config auto-starts, falling
+// back to the first multiplexer whose config is deployed.
+func currentWM(shell string) string {
+	syntax := system.SyntaxSh
+	var paths []string
+	switch shell {
+	case "fish":
+		syntax = system.SyntaxFish
+		if linkMode.enabled {
+			paths = append(paths, filepath.Join(system.OverridesDir(), "config.fish"))
+		}
+	case "zsh":
+		if linkMode.enabled {
+			paths = append(paths, filepath.Join(system.OverridesDir(), "zshrc.zsh"))
+		}
+	case "nushell":
+		syntax = system.SyntaxNu
+	case "bash":
+		if linkMode.enabled {
+			paths = append(paths, filepath.Join(system.OverridesDir(), "bashrc.sh"))
+		}
+	}
+	paths = append(paths, shellConfigPath(shell))
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if wm := system.ConfiguredWM(string(data), syntax); wm != "" {
+			return wm
+		}
+	}
+	for _, wm := range []string{"tmux", "zellij", "herdr"} {
+		if system.FileExists(wmConfigPath(wm)) {
+			return wm
+		}
+	}
+	return "none"
+}
+
+// existingConfigKeys returns the backup keys among keys whose paths exist
+func existingConfigKeys(keys ...string) []string {
+	paths := system.ConfigPaths()
+	var existing []string
+	for _, key := range keys {
+		if containsValue(existing, key) {
+			continue
+		}
+		if system.FileExists(paths[key]) {
+			existing = append(existing, key)
+		}
+	}
+	return existing
+}
+
+// containsValue reports whether values holds value
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupReconfigureSteps builds the steps that apply one setting to an
+// existing installation. The new value must already be in m.Choices.
+func (m *Model) SetupReconfigureSteps(setting string) {
+	m.Reconfiguring = setting
+	m.Steps = []InstallStep{}
+	detectLinkMode()
+	previousShell := currentShellChoice(m)
+	if m.Choices.Shell == "" {
+		m.Choices.Shell = previousShell
+	}
+
+	switch setting {
+	case "wm":
+		wm := m.Choices.WindowMgr
+		// A missing config means the multiplexer was never installed by us:
+		// run the regular install step, which needs the repository.
+		needsDeploy := wm != "none" && !system.FileExists(wmConfigPath(wm))
+		if needsDeploy {
+			m.Steps = append(m.Steps, InstallStep{
+				ID:          "clone",
+				Name:        "Clone Repository",
+				Description: "Downloading Gentleman.Dots",
+				Status:      StatusPending,
+			})
+			m.Steps = append(m.Steps, InstallStep{
+				ID:          "wm",
+				Name:        "Install " + wm,
+				Description: "Terminal multiplexer",
+				Status:      StatusPending,
+			})
+		}
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "reconfigwm",
+			Name:        "Switch shells to " + wm,
+			Description: "Re-patching installed shell configs",
+			Status:      StatusPending,
+		})
+		if needsDeploy {
+			m.Steps = append(m.Steps, InstallStep{
+				ID:          "cleanup",
+				Name:        "Cleanup",
+				Description: "Removing temporary files",
+				Status:      StatusPending,
+			})
+		}
+
+	case "shell":
+		shell := m.Choices.Shell
+		// Keep whatever multiplexer the previous shell started
+		if m.Choices.WindowMgr == "" {
+			m.Choices.WindowMgr = "none"
+			if previousShell != "" {
+				m.Choices.WindowMgr = currentWM(previousShell)
+			}
+		}
+		// fzf comes with the Neovim setup; guard its shell hook when it is missing
+		m.Choices.InstallNvim = system.CommandExists("fzf")
+
+		// Back up the old shell's config plus everything the new shell overwrites
+		keys := append([]string{}, shellBackupKeys[previousShell]...)
+		keys = append(keys, shellBackupKeys[shell]...)
+		keys = append(keys, "tmux", "zellij")
+		m.ExistingConfigs = existingConfigKeys(keys...)
+		if len(m.ExistingConfigs) > 0 {
+			m.Steps = append(m.Steps, InstallStep{
+				ID:          "backup",
+				Name:        "Backup Existing Configs",
+				Description: "Creating backup of your current configuration",
+				Status:      StatusPending,
+			})
+		}
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "clone",
+			Name:        "Clone Repository",
+			Description: "Downloading Gentleman.Dots",
+			Status:      StatusPending,
+		})
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "shell",
+			Name:        "Install " + shell,
+			Description: "Shell and plugins",
+			Status:      StatusPending,
+		})
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "reconfigshell",
+			Name:        "Point multiplexers at " + shell,
+			Description: "Rewriting multiplexer default shell",
+			Status:      StatusPending,
+		})
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "setshell",
+			Name:        "Set Default Shell",
+			Description: "Configure default shell",
+			Status:      StatusPending,
+			Interactive: true,
+		})
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "cleanup",
+			Name:        "Cleanup",
+			Description: "Removing temporary files",
+			Status:      StatusPending,
+		})
+
+	case "theme":
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "theme",
+			Name:        "Apply Theme",
+			Description: "Recoloring installed configs",
+			Status:      StatusPending,
+		})
+
+	case "layout":
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "zellijlayout",
+			Name:        "Zellij Layout",
+			Description: "Setting the default layout",
+			Status:      StatusPending,
+		})
+
+	case "shaders":
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "shaders",
+			Name:        "Ghostty Shaders",
+			Description: "Writing custom-shader entries",
+			Status:      StatusPending,
+		})
+
+	case "terminal":
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "tuneterminal",
+			Name:        "Terminal Settings",
+			Description: "Updating installed terminal configs",
+			Status:      StatusPending,
+		})
+
+	case "herdrkeys":
+		m.Steps = append(m.Steps, InstallStep{
+			ID:          "herdrkeys",
+			Name:        "Herdr Keys",
+			Description: "Writing the [keys] bindings",
+			Status:      StatusPending,
+		})
+	}
+}
+
+// stepReconfigureWM points an existing installation at m.Choices.WindowMgr
+func stepReconfigureWM(m *Model) error {
+	stepID := "reconfigwm"
+	wm := m.Choices.WindowMgr
+
+	if wm != "none" {
+		if err := installWMBinary(m, stepID, wm); err != nil {
+			return wrapStepError(stepID, "Reconfigure Window Manager",
+				fmt.Sprintf("Failed to install %s", wm),
+				err)
+		}
+	}
+
+	// Keep the multiplexer's default shell in sync with the current shell
+	if shellName := shellBinary(m.Choices.Shell); shellName != "" {
+		var err error
+		switch wm {
+		case "tmux":
+			SendLog(stepID, "Configuring tmux default shell...")
+			err = setTmuxShell(resolveShellPath(m, shellName))
+		case "zellij":
+			SendLog(stepID, "Configuring zellij default shell...")
+			err = setZellijShell(shellName)
+		}
+		if err != nil {
+			return wrapStepError(stepID, "Reconfigure Window Manager",
+				fmt.Sprintf("Failed to set the %s default shell", wm),
+				err)
+		}
+	}
+
+	shells := installedShells()
+	if len(shells) == 0 {
+		SendLog(stepID, "No Gentleman shell configs found, nothing to patch")
+	}
+	// fzf comes with the Neovim setup; guard its shell hook when it is missing
+	hasFzf := system.CommandExists("fzf")
+	for _, shell := range shells {
+		SendLog(stepID, fmt.Sprintf("Configuring %s for %s...", shell, wm))
+		if err := patchShellForWM(stepID, shell, wm, hasFzf); err != nil {
+			return wrapStepError(stepID, "Reconfigure Window Manager",
+				fmt.Sprintf("Failed to patch %s config for %s", shell, wm),
+				err)
+		}
+	}
+
+	if err := applyWeztermChoices(m, stepID); err != nil {
+		return wrapStepError(stepID, "Reconfigure Window Manager",
+			"Failed to update the WezTerm config",
+			err)
+	}
+
+	SendLog(stepID, fmt.Sprintf("✓ Window manager set to %s", wm))
+	return nil
+}
+
+// stepReconfigureShell rewrites the multiplexer default shell written for the
+// previous shell. Multiplexers that were never deployed are left alone.
+func stepReconfigureShell(m *Model) error {
+	stepID := "reconfigshell"
+	shellName := shellBinary(m.Choices.Shell)
+
+	if system.FileExists(wmConfigPath("tmux")) {
+		SendLog(stepID, "Configuring tmux default shell...")
+		if err := setTmuxShell(resolveShellPath(m, shellName)); err != nil {
+			return wrapStepError(stepID, "Reconfigure Shell",
+				"Failed to set the tmux default shell",
+				err)
+		}
+	}
+	if system.FileExists(wmConfigPath("zellij")) {
+		SendLog(stepID, "Configuring zellij default shell...")
+		if err := setZellijShell(shellName); err != nil {
+			return wrapStepError(stepID, "Reconfigure Shell",
+				"Failed to set the zellij default shell",
+				err)
+		}
+	}
+
+	if err := applyWeztermChoices(m, stepID); err != nil {
+		return wrapStepError(stepID, "Reconfigure Shell",
+			"Failed to update the WezTerm config",
+			err)
+	}
+
+	SendLog(stepID, fmt.Sprintf("✓ Multiplexers now start %s", m.Choices.Shell))
+	return nil
+}
+
+// RunReconfigure applies a single setting to an existing installation without TUI
+func RunReconfigure(setting string, choices UserChoices) error {
+	SetNonInteractiveMode(true)
+
+	model := &Model{
+		SystemInfo: system.Detect(),
+		Choices:    choices,
+		LogLines:   []string{},
+	}
+	model.SetupReconfigureSteps(setting)
+	if err := runStepsNonInteractive(model, model.Steps); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println("✅ Reconfiguration complete!")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// setupInstalledHome fakes an existing zsh + tmux installation in a temp HOME
+func setupInstalledHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	zshrc, err := os.ReadFile("../../../GentlemanZsh/.zshrc")
+	if err != nil {
+		t.Skipf("shipped .zshrc not available: %v", err)
+	}
+	os.WriteFile(filepath.Join(home, ".zshrc"), zshrc, 0644)
+	os.WriteFile(filepath.Join(home, ".tmux.conf"), []byte("set -g mouse on\n"), 0644)
+	return home
+}
+
+func stepIDs(steps []InstallStep) []string {
+	ids := make([]string, len(steps))
+	for i, s := range steps {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+func TestSetupReconfigureSteps(t *testing.T) {
+	setupInstalledHome(t)
+
+	t.Run("existing multiplexer config only re-patches", func(t *testing.T) {
+		m := NewModel()
+		m.Choices = UserChoices{WindowMgr: "tmux"}
+		m.SetupReconfigureSteps("wm")
+		if got := strings.Join(stepIDs(m.Steps), ","); got != "reconfigwm" {
+			t.Errorf("expected only reconfigwm, got %s", got)
+		}
+		if m.Reconfiguring != "wm" {
+			t.Errorf("expected Reconfiguring=wm, got %q", m.Reconfiguring)
+		}
+	})
+
+	t.Run("missing multiplexer config installs it first", func(t *testing.T) {
+		m := NewModel()
+		m.Choices = UserChoices{WindowMgr: "zellij"}
+		m.SetupReconfigureSteps("wm")
+		if got := strings.Join(stepIDs(m.Steps), ","); got != "clone,wm,reconfigwm,cleanup" {
+			t.Errorf("unexpected steps: %s", got)
+		}
+	})
+}
+
+func TestStepReconfigureWM_RepatchesShells(t *testing.T) {
+	home := setupInstalledHome(t)
+	m := &Model{
+		SystemInfo: &system.SystemInfo{UserShell: "zsh"},
+		Choices:    UserChoices{WindowMgr: "none", Shell: "zsh"},
+	}
+
+	if err := stepReconfigureWM(m); err != nil {
+		t.Fatalf("stepReconfigureWM failed: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".zshrc"))
+	if strings.Contains(string(data), "WM_CMD") || strings.Contains(string(data), "\nstart_if_needed\n") {
+		t.Errorf("expected multiplexer auto-start removed, got:\n%s", data)
+	}
+	tmux, _ := os.ReadFile(filepath.Join(home, ".tmux.conf"))
+	if string(tmux) != "set -g mouse on\n" {
+		t.Errorf("tmux.conf should be untouched when switching to none, got:\n%s", tmux)
+	}
+}
+
+func TestCurrentShellChoice(t *testing.T) {
+	setupInstalledHome(t)
+	tests := map[string]string{"fish": "fish", "nu": "nushell", "bash": "zsh"}
+	for userShell, want := range tests {
+		m := &Model{SystemInfo: &system.SystemInfo{UserShell: userShell}}
+		if got := currentShellChoice(m); got != want {
+			t.Errorf("UserShell %q: expected %q, got %q", userShell, want, got)
+		}
+	}
+}
+
+func TestDetectLinkMode(t *testing.T) {
+	original := linkMode
+	t.Cleanup(func() { linkMode = original })
+	linkMode.enabled = false
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := filepath.Join(t.TempDir(), "dots")
+	os.MkdirAll(filepath.Join(repo, "GentlemanZsh"), 0755)
+	os.WriteFile(filepath.Join(repo, "GentlemanZsh", ".zshrc"), []byte("# zsh\n"), 0644)
+	os.Symlink(filepath.Join(repo, "GentlemanZsh", ".zshrc"), filepath.Join(home, ".zshrc"))
+
+	detectLinkMode()
+	if !LinkModeEnabled() {
+		t.Fatal("expected link mode to be detected from symlinked .zshrc")
+	}
+	resolvedRepo, _ := filepath.EvalSymlinks(repo)
+	if LinkRepoDir() != resolvedRepo {
+		t.Errorf("expected repo dir %q, got %q", resolvedRepo, LinkRepoDir())
+	}
+}
+
+func TestReconfigureMenuNavigation(t *testing.T) {
+	setupInstalledHome(t)
+	m := NewModel()
+	m.Screen = ScreenMainMenu
+	options := m.GetCurrentOptions()
+	for i, opt := range options {
+		if strings.Contains(opt, "Reconfigure") {
+			m.Cursor = i
+		}
+	}
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenReconfigure {
+		t.Fatalf("expected ScreenReconfigure, got %v", m.Screen)
+	}
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenReconfigureWM {
+		t.Fatalf("expected ScreenReconfigureWM, got %v", m.Screen)
+	}
+
+	// First option is Tmux; the fake install already has its config
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenInstalling || cmd == nil {
+		t.Fatalf("expected installation to start, got screen %v", m.Screen)
+	}
+	if m.Choices.WindowMgr != "tmux" || len(m.Steps) != 1 || m.Steps[0].ID != "reconfigwm" {
+		t.Errorf("unexpected reconfigure setup: %+v %v", m.Choices, stepIDs(m.Steps))
+	}
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if result.(Model).Screen != ScreenInstalling {
+		t.Error("Esc must not leave the installing screen")
+	}
+}
+
+func TestSetupReconfigureSteps_Shell(t *testing.T) {
+	home := setupInstalledHome(t)
+	if err := system.PatchZshForWM(filepath.Join(home, ".zshrc"), "zellij", true); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel()
+	m.SystemInfo = &system.SystemInfo{UserShell: "zsh"}
+	m.Choices = UserChoices{Shell: "fish"}
+	m.SetupReconfigureSteps("shell")
+
+	if got := strings.Join(stepIDs(m.Steps), ","); got != "backup,clone,shell,reconfigshell,setshell,cleanup" {
+		t.Errorf("unexpected steps: %s", got)
+	}
+	if m.Choices.WindowMgr != "zellij" {
+		t.Errorf("expected the zsh multiplexer to carry over, got %q", m.Choices.WindowMgr)
+	}
+	// Old zsh config and the tmux default shell are backed up; fish has no config yet
+	if got := strings.Join(m.ExistingConfigs, ","); got != "zsh,tmux" {
+		t.Errorf("unexpected backup keys: %s", got)
+	}
+}
+
+func TestCurrentWM(t *testing.T) {
+	home := setupInstalledHome(t)
+	zshrc := filepath.Join(home, ".zshrc")
+
+	system.PatchZshForWM(zshrc, "herdr", true)
+	if got := currentWM("zsh"); got != "herdr" {
+		t.Errorf("expected herdr, got %q", got)
+	}
+	system.PatchZshForWM(zshrc, "none", true)
+	if got := currentWM("zsh"); got != "none" {
+		t.Errorf("expected none, got %q", got)
+	}
+	// No fish config: fall back to the deployed multiplexer
+	if got := currentWM("fish"); got != "tmux" {
+		t.Errorf("expected tmux fallback, got %q", got)
+	}
+}
+
+func TestStepReconfigureShell(t *testing.T) {
+	home := setupInstalledHome(t)
+	system.SetTmuxDefaultShell(filepath.Join(home, ".tmux.conf"), "/bin/zsh")
+
+	m := &Model{
+		SystemInfo: &system.SystemInfo{},
+		Choices:    UserChoices{Shell: "nushell"},
+	}
+	if err := stepReconfigureShell(m); err != nil {
+		t.Fatalf("stepReconfigureShell failed: %v", err)
+	}
+
+	tmux, _ := os.ReadFile(filepath.Join(home, ".tmux.conf"))
+	if strings.Contains(string(tmux), "zsh") || !strings.Contains(string(tmux), `default-shell "`) {
+		t.Errorf("expected tmux default shell rewritten for nu, got:\n%s", tmux)
+	}
+	if strings.Count(string(tmux), "set -g default-shell") != 1 {
+		t.Errorf("expected a single default-shell line, got:\n%s", tmux)
+	}
+	// zellij was never deployed and must not be created
+	if system.FileExists(wmConfigPath("zellij")) {
+		t.Error("zellij config should not be created")
+	}
+}
+
+func TestInstalledShells_IgnoresDistroBashrc(t *testing.T) {
+	home := setupInstalledHome(t)
+	bashrc := filepath.Join(home, ".bashrc")
+
+	os.WriteFile(bashrc, []byte("# distro default\nalias ll='ls -l'\n"), 0644)
+	if got := strings.Join(installedShells(), ","); got != "zsh" {
+		t.Errorf("a distro .bashrc must not count as installed, got %s", got)
+	}
+
+	shipped, err := os.ReadFile("../../../GentlemanBash/.bashrc")
+	if err != nil {
+		t.Skipf("shipped .bashrc not available: %v", err)
+	}
+	os.WriteFile(bashrc, shipped, 0644)
+	if got := strings.Join(installedShells(), ","); got != "zsh,bash" {
+		t.Errorf("expected zsh,bash, got %s", got)
+	}
+	m := &Model{SystemInfo: &system.SystemInfo{UserShell: "bash"}}
+	if got := currentShellChoice(m); got != "bash" {
+		t.Errorf("expected bash, got %q", got)
+	}
+}
+
+func TestStepApplyTheme(t *testing.T) {
+	home := setupInstalledHome(t)
+	starship, err := os.ReadFile("../../../starship.toml")
+	if err != nil {
+		t.Skipf("shipped starship.toml not available: %v", err)
+	}
+	os.MkdirAll(filepath.Join(home, ".config"), 0755)
+	os.WriteFile(filepath.Join(home, ".config", "starship.toml"), starship, 0644)
+
+	m := NewModel()
+	m.Choices = UserChoices{Theme: "tokyonight"}
+	m.SetupReconfigureSteps("theme")
+	if got := strings.Join(stepIDs(m.Steps), ","); got != "theme" {
+		t.Fatalf("expected only the theme step, got %s", got)
+	}
+	if err := stepApplyTheme(&m); err != nil {
+		t.Fatalf("stepApplyTheme: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(home, ".config", "starship.toml"))
+	if !strings.Contains(string(data), `palette = "tokyonight"`) {
+		t.Errorf("expected the starship palette switched, got:\n%s", data)
+	}
+	tmux, _ := os.ReadFile(filepath.Join(home, ".tmux.conf"))
+	if !strings.HasPrefix(string(tmux), "set -g mouse on\n") || !strings.Contains(string(tmux), "tokyo-night-tmux") {
+		t.Errorf("expected the tmux theme added, got:\n%s", tmux)
+	}
+	// Configs that were never deployed are not created
+	if system.FileExists(filepath.Join(home, ".config", "alacritty", "alacritty.toml")) {
+		t.Error("theme must not create configs for tools that are not installed")
+	}
+
+	m.Choices.Theme = "solarized"
+	if err := stepApplyTheme(&m); err == nil {
+		t.Error("expected an unknown theme to fail")
+	}
+}
+
+func TestReconfigureThemeScreen(t *testing.T) {
+	setupInstalledHome(t)
+	m := NewModel()
+	m.Screen = ScreenReconfigureTheme
+	options := m.GetCurrentOptions()
+	for i, opt := range options {
+		if opt == "Rosé Pine" {
+			m.Cursor = i
+		}
+	}
+
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenInstalling || cmd == nil {
+		t.Fatalf("expected installation to start, got screen %v", m.Screen)
+	}
+	if m.Choices.Theme != "rose-pine" || m.Reconfiguring != "theme" {
+		t.Errorf("unexpected reconfigure setup: %+v", m.Choices)
+	}
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// ghosttyShadersDir is where the shaders directory is deployed
+func ghosttyShadersDir() string {
+	return filepath.Join(filepath.Dir(terminalConfigPath("ghostty")), "shaders")
+}
+
+// CurrentGhosttyShaders returns the shaders the installed Ghostty config enables, in order
+func CurrentGhosttyShaders() ([]string, error) {
+	data, err := os.ReadFile(terminalConfigPath("ghostty"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no Ghostty config at %s; install Ghostty first", terminalConfigPath("ghostty"))
+		}
+		return nil, err
+	}
+	return system.EnabledShaders(string(data)), nil
+}
+
+// ResolveShader maps a shader name to its file. Shaders outside the catalog
+// are accepted when they exist in the deployed shaders directory.
+func ResolveShader(name string) (string, error) {
+	if s, ok := system.LookupShader(name); ok {
+		return s.File, nil
+	}
+	file := system.ShaderFile(name)
+	if system.FileExists(filepath.Join(ghosttyShadersDir(), file)) {
+		return file, nil
+	}
+	return "", fmt.Errorf("unknown shader: %s (run 'gentleman.dots ghostty shaders' for the list)", name)
+}
+
+// stepApplyShaders writes m.Choices.Shaders into the Ghostty config
+func stepApplyShaders(m *Model) error {
+	stepID := "shaders"
+	path := terminalConfigPath("ghostty")
+	if !system.FileExists(path) {
+		return wrapStepError(stepID, "Ghostty Shaders",
+			"Ghostty config not found",
+			fmt.Errorf("%s does not exist; install Ghostty first", path))
+	}
+
+	SendLog(stepID, "Writing custom-shader entries...")
+	if err := localizeConfig(path); err != nil {
+		return wrapStepError(stepID, "Ghostty Shaders", "Failed to prepare the Ghostty config", err)
+	}
+	if err := system.SetGhosttyShaders(path, m.Choices.Shaders); err != nil {
+		return wrapStepError(stepID, "Ghostty Shaders", "Failed to write the Ghostty shaders", err)
+	}
+
+	if len(m.Choices.Shaders) == 0 {
+		SendLog(stepID, "✓ Ghostty shaders disabled")
+	} else {
+		SendLog(stepID, "✓ Ghostty shaders: "+strings.Join(m.Choices.Shaders, ", "))
+	}
+	SendLog(stepID, "Reload the config in Ghostty (ctrl+shift+, or cmd+shift+, on macOS) to preview")
+	return nil
+}
+
+// shaderPosition returns the 1-based order of file in the selection, 0 when disabled
+func shaderPosition(selection []string, file string) int {
+	for i, f := range selection {
+		if f == file {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// toggleShader enables file at the end of the order, or disables it
+func toggleShader(selection []string, file string) []string {
+	if pos := shaderPosition(selection, file); pos > 0 {
+		return append(append([]string{}, selection[:pos-1]...), selection[pos:]...)
+	}
+	return append(append([]string{}, selection...), file)
+}
+
+// moveShader moves an enabled shader delta places in the order.
+// Ghostty runs shaders in order, each one on the output of the previous.
+func moveShader(selection []string, file string, delta int) []string {
+	pos := shaderPosition(selection, file)
+	to := pos - 1 + delta
+	if pos == 0 || to < 0 || to >= len(selection) {
+		return selection
+	}
+	moved := append([]string{}, selection...)
+	moved[pos-1], moved[to] = moved[to], moved[pos-1]
+	return moved
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestToggleAndMoveShader(t *testing.T) {
+	sel := toggleShader(nil, "a.glsl")
+	sel = toggleShader(sel, "b.glsl")
+	sel = toggleShader(sel, "c.glsl")
+	sel = moveShader(sel, "c.glsl", -1)
+	if got := strings.Join(sel, ","); got != "a.glsl,c.glsl,b.glsl" {
+		t.Errorf("unexpected order %s", got)
+	}
+	if got := moveShader(sel, "a.glsl", -1); got[0] != "a.glsl" {
+		t.Error("moving the first shader up must be a no-op")
+	}
+	sel = toggleShader(sel, "c.glsl")
+	if got := strings.Join(sel, ","); got != "a.glsl,b.glsl" {
+		t.Errorf("unexpected order after disabling %s", got)
+	}
+}
+
+func TestShaderPicker_AppliesSelection(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	shipped, err := os.ReadFile("../../../GentlemanGhostty/config")
+	if err != nil {
+		t.Skipf("shipped ghostty config not available: %v", err)
+	}
+	path := filepath.Join(home, ".config", "ghostty", "config")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, shipped, 0644)
+
+	m := NewModel()
+	m.Screen = ScreenReconfigure
+	for i, opt := range m.GetCurrentOptions() {
+		if strings.Contains(opt, "Shaders") {
+			m.Cursor = i
+		}
+	}
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenReconfigureShaders || m.ShaderNotice != "" {
+		t.Fatalf("expected the shader picker, got screen %v (%s)", m.Screen, m.ShaderNotice)
+	}
+	if strings.Join(m.Choices.Shaders, ",") != "cursor_smear_gentleman.glsl" {
+		t.Fatalf("expected the shipped shader preselected, got %v", m.Choices.Shaders)
+	}
+
+	// Enable the second catalog entry and run it before the shipped one
+	for _, key := range []string{"j", "x", "K"} {
+		result, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = result.(Model)
+	}
+	second := system.GhosttyShaders[1].File
+	if got := strings.Join(m.Choices.Shaders, ","); got != second+",cursor_smear_gentleman.glsl" {
+		t.Fatalf("unexpected selection %s", got)
+	}
+	if view := m.View(); !strings.Contains(view, "[2] cursor_smear_gentleman") || !strings.Contains(view, system.GhosttyShaders[1].Description) {
+		t.Errorf("expected the order and description in the picker:\n%s", view)
+	}
+
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenInstalling || cmd == nil || stepIDs(m.Steps)[0] != "shaders" {
+		t.Fatalf("expected the shaders step to start, got screen %v", m.Screen)
+	}
+	if err := stepApplyShaders(&m); err != nil {
+		t.Fatalf("stepApplyShaders: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if got := strings.Join(system.EnabledShaders(string(data)), ","); got != second+",cursor_smear_gentleman.glsl" {
+		t.Errorf("unexpected shaders in config: %s", got)
+	}
+}
+
+func TestShaderPicker_WithoutGhostty(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := NewModel()
+	m.Screen = ScreenReconfigure
+	for i, opt := range m.GetCurrentOptions() {
+		if strings.Contains(opt, "Shaders") {
+			m.Cursor = i
+		}
+	}
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.ShaderNotice == "" {
+		t.Fatal("expected a notice without a Ghostty config")
+	}
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if result.(Model).Screen != ScreenReconfigureShaders {
+		t.Error("Enter must not start a step without a Ghostty config")
+	}
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// ValidThemes lists the palettes accepted by `set theme` and --theme
+var ValidThemes = themeIDs()
+
+func themeIDs() []string {
+	ids := make([]string, 0, len(system.Palettes))
+	for _, p := range system.Palettes {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+// themeConfigPath returns the deployed config a theme target rewrites
+func themeConfigPath(target string) string {
+	home := os.Getenv("HOME")
+	switch target {
+	case "alacritty", "wezterm", "kitty", "ghostty":
+		return terminalConfigPath(target)
+	case "tmux", "zellij", "herdr":
+		return wmConfigPath(target)
+	case "starship":
+		return filepath.Join(home, ".config", "starship.toml")
+	case "nvim":
+		return filepath.Join(home, ".config", "nvim", "lua", "plugins", "colorscheme.lua")
+	}
+	return ""
+}
+
+// themePlan recolors every deployed config, so all tools switch together
+func themePlan(m *Model) (*stepPlan, error) {
+	p := &stepPlan{ID: "theme", Name: "Apply Theme"}
+	palette, ok := system.LookupPalette(m.Choices.Theme)
+	if !ok {
+		return nil, wrapStepError(p.ID, p.Name,
+			"Unknown theme "+m.Choices.Theme,
+			fmt.Errorf("valid themes: %v", ValidThemes))
+	}
+
+	applied := 0
+	for _, target := range system.ThemeTargets {
+		path := themeConfigPath(target)
+		if !system.FileExists(path) {
+			continue
+		}
+		applied++
+		target := target
+		p.Actions = append(p.Actions, stepAction{
+			Kind: actionPatch,
+			Log:  fmt.Sprintf("Applying %s to %s...", palette.Name, target),
+			Fail: fmt.Sprintf("Failed to apply the theme to %s", target),
+			Apply: func() error {
+				if err := localizeConfig(path); err != nil {
+					return err
+				}
+				return system.ApplyTheme(target, path, palette)
+			},
+		})
+	}
+	if applied == 0 {
+		p.Actions = append(p.Actions, stepAction{Kind: actionNote, Log: "No Gentleman configs found, nothing to recolor"})
+	}
+
+	// The tmux theme is a plugin; fetch it now instead of on the next prefix+I
+	if system.FileExists(wmConfigPath("tmux")) {
+		p.Actions = append(p.Actions, stepAction{
+			Kind: actionPatch,
+			Log:  "Installing the tmux plugins...",
+			Apply: func() error {
+				_, err := installTmuxPlugins(p.ID)
+				return err
+			},
+			Optional: true,
+			Fail:     "Could not install the tmux plugins; press prefix + I inside tmux",
+		})
+	}
+
+	p.Done = []string{fmt.Sprintf("✓ Theme set to %s", palette.Name)}
+	return p, nil
+}
+
+// stepApplyTheme rewrites the color sections of every deployed config
+func stepApplyTheme(m *Model) error {
+	p, err := themePlan(m)
+	if err != nil {
+		return err
+	}
+	return runPlan(p)
+}
+
+// themePickerOptions lists the palettes by name for the theme screen
+func themePickerOptions() []string {
+	options := make([]string, 0, len(system.Palettes)+2)
+	for _, p := range system.Palettes {
+		options = append(options, p.Name)
+	}
+	return append(options, "─────────────", "← Back")
+}
+
+// themeByName maps a theme screen option back to its palette ID
+func themeByName(name string) string {
+	for _, p := range system.Palettes {
+		if p.Name == name {
+			return p.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// tmuxPluginsDir is where TPM loads plugins from
+func tmuxPluginsDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".tmux", "plugins")
+}
+
+// tmuxLockPath records the commit every installed plugin is at
+func tmuxLockPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".tmux", "tmux-lock.json")
+}
+
+// tmuxPluginPins merges the local lock with the one shipped in the
+// repository; shipped pins win so every machine gets the same commits
+func tmuxPluginPins() (system.TmuxLock, error) {
+	lock, err := system.ReadTmuxLock(tmuxLockPath())
+	if err != nil {
+		return nil, err
+	}
+	shipped, err := system.ReadTmuxLock(filepath.Join(repoPath(), "GentlemanTmux", "tmux-lock.json"))
+	if err != nil {
+		return nil, err
+	}
+	for name, entry := range shipped {
+		lock[name] = entry
+	}
+	return lock, nil
+}
+
+// LockTmuxPlugins pins every @plugin of repoDir's GentlemanTmux/tmux.conf to
+// its current branch head in the shipped GentlemanTmux/tmux-lock.json
+func LockTmuxPlugins(repoDir string) (system.TmuxLock, error) {
+	dir := filepath.Join(repoDir, "GentlemanTmux")
+	data, err := os.ReadFile(filepath.Join(dir, "tmux.conf"))
+	if err != nil {
+		return nil, err
+	}
+	lock := system.TmuxLock{}
+	for _, p := range system.ParseTmuxPlugins(string(data)) {
+		entry, err := system.ResolveTmuxPlugin(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.Name, err)
+		}
+		lock[p.Name] = entry
+	}
+	return lock, system.WriteTmuxLock(filepath.Join(dir, "tmux-lock.json"), lock)
+}
+
+// installTmuxPlugins installs every @plugin of the deployed tmux.conf at its
+// pinned commit, or at its branch head when it has no pin yet, and records
+// the result in the lock. It returns the plugins that failed.
+func installTmuxPlugins(stepID string) ([]string, error) {
+	data, err := os.ReadFile(wmConfigPath("tmux"))
+	if err != nil {
+		return nil, err
+	}
+	pins, err := tmuxPluginPins()
+	if err != nil {
+		return nil, err
+	}
+
+	lock := system.TmuxLock{}
+	var failed []string
+	var errs []error
+	for _, p := range system.ParseTmuxPlugins(string(data)) {
+		pin := pins[p.Name]
+		if p.Branch != "" && pin.Branch != p.Branch {
+			pin = system.TmuxLockEntry{} // tmux.conf moved the plugin to another branch
+		}
+		if pin.Commit != "" {
+			SendLog(stepID, fmt.Sprintf("Installing %s at %s...", p.Name, shortCommit(pin.Commit)))
+		} else {
+			SendLog(stepID, fmt.Sprintf("Installing %s at its branch head (not pinned by GentlemanTmux/tmux-lock.json)...", p.Name))
+		}
+		entry, err := system.InstallTmuxPlugin(filepath.Join(tmuxPluginsDir(), p.Name), p, pin.Commit, func(line string) {
+			SendLog(stepID, line)
+		})
+		if err != nil {
+			SendLog(stepID, fmt.Sprintf("✗ %s: %v", p.Name, err))
+			failed = append(failed, p.Name)
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+			if pin.Commit != "" {
+				lock[p.Name] = pin
+			}
+			continue
+		}
+		lock[p.Name] = entry
+		SendLog(stepID, fmt.Sprintf("✓ %s (%s)", p.Name, shortCommit(entry.Commit)))
+	}
+
+	if err := system.WriteTmuxLock(tmuxLockPath(), lock); err != nil {
+		errs = append(errs, fmt.Errorf("writing %s: %w", tmuxLockPath(), err))
+	}
+	return failed, errors.Join(errs...)
+}
+
+// shortCommit abbreviates a commit hash for logs
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
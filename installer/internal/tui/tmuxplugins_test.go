@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+func TestInstallTmuxPlugins_ReportsEachFailure(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	plugin := filepath.Join(t.TempDir(), "tmux-good")
+	os.MkdirAll(plugin, 0755)
+	os.WriteFile(filepath.Join(plugin, "good.tmux"), []byte("#!/bin/sh\n"), 0755)
+	for _, args := range [][]string{{"init", "-q", "-b", "main"}, {"add", "-A"}, {"commit", "-q", "-m", "init"}} {
+		cmd := exec.Command("git", append([]string{"-C", plugin}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	conf := "set -g @plugin '" + plugin + "'\nset -g @plugin '" + filepath.Join(t.TempDir(), "tmux-missing") + "'\n"
+	os.WriteFile(wmConfigPath("tmux"), []byte(conf), 0644)
+
+	failed, err := installTmuxPlugins("wm")
+	if len(failed) != 1 || failed[0] != "tmux-missing" || err == nil || !strings.Contains(err.Error(), "tmux-missing") {
+		t.Fatalf("expected only tmux-missing to fail, got %v, %v", failed, err)
+	}
+	if !system.FileExists(filepath.Join(tmuxPluginsDir(), "tmux-good", "good.tmux")) {
+		t.Error("the good plugin should be installed")
+	}
+	lock, _ := system.ReadTmuxLock(tmuxLockPath())
+	if len(lock) != 1 || len(lock["tmux-good"].Commit) != 40 || lock["tmux-good"].Branch != "main" {
+		t.Errorf("expected the good plugin in the lock, got %+v", lock)
+	}
+}
+
+func TestLockTmuxPlugins(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	plugin := filepath.Join(t.TempDir(), "tmux-good")
+	os.MkdirAll(plugin, 0755)
+	os.WriteFile(filepath.Join(plugin, "good.tmux"), []byte("#!/bin/sh\n"), 0755)
+	for _, args := range [][]string{{"init", "-q", "-b", "main"}, {"add", "-A"}, {"commit", "-q", "-m", "init"}} {
+		cmd := exec.Command("git", append([]string{"-C", plugin}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	repo := t.TempDir()
+	os.MkdirAll(filepath.Join(repo, "GentlemanTmux"), 0755)
+	os.WriteFile(filepath.Join(repo, "GentlemanTmux", "tmux.conf"), []byte("set -g @plugin '"+plugin+"'\n"), 0644)
+
+	if _, err := LockTmuxPlugins(repo); err != nil {
+		t.Fatalf("LockTmuxPlugins: %v", err)
+	}
+	lock, _ := system.ReadTmuxLock(filepath.Join(repo, "GentlemanTmux", "tmux-lock.json"))
+	if len(lock) != 1 || len(lock["tmux-good"].Commit) != 40 || lock["tmux-good"].Branch != "main" {
+		t.Errorf("expected the plugin pinned in the shipped lock, got %+v", lock)
+	}
+}
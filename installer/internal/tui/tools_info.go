@@ -159,6 +159,23 @@ func GetShellInfo() map[string]ToolInfo {
 			},
 			Website: "https://www.nushell.sh",
 		},
+		"bash": {
+			Name:        "Bash",
+			Description: "Bourne Again SHell - the default almost everywhere",
+			Pros: []string{
+				"Installed on virtually every Linux server",
+				"The POSIX-ish standard your scripts already target",
+				"Works where other shells are not allowed",
+				"Starship, zoxide, atuin and fzf all support it",
+				"Zero surprises when you SSH into a new box",
+			},
+			Cons: []string{
+				"Weak defaults (no autosuggestions or highlighting)",
+				"Completion depends on bash-completion and carapace",
+				"macOS ships an ancient 3.2 (install a newer one)",
+			},
+			Website: "https://www.gnu.org/software/bash/",
+		},
 	}
 }
 
@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// terminalSettingNames returns the `set terminal.<key>` settings
+func terminalSettingNames() []string {
+	names := make([]string, 0, len(system.TerminalSettings))
+	for _, s := range system.TerminalSettings {
+		names = append(names, "terminal."+s.Key)
+	}
+	return names
+}
+
+// deployedTerminals returns the terminals whose Gentleman config is present
+func deployedTerminals() []string {
+	var terminals []string
+	for _, terminal := range []string{"alacritty", "wezterm", "kitty", "ghostty"} {
+		if system.FileExists(terminalConfigPath(terminal)) {
+			terminals = append(terminals, terminal)
+		}
+	}
+	return terminals
+}
+
+// currentTerminalSettings reads the settings of the first deployed terminal,
+// filling unset ones with the terminal defaults
+func currentTerminalSettings() (map[string]float64, string, error) {
+	terminals := deployedTerminals()
+	if len(terminals) == 0 {
+		return nil, "", fmt.Errorf("no terminal config found; install a terminal first")
+	}
+	data, err := os.ReadFile(terminalConfigPath(terminals[0]))
+	if err != nil {
+		return nil, "", err
+	}
+	values := system.TerminalSettingValues(terminals[0], string(data))
+	for _, s := range system.TerminalSettings {
+		if _, ok := values[s.Key]; !ok {
+			values[s.Key] = s.Default
+		}
+	}
+	return values, terminals[0], nil
+}
+
+// adjustSetting moves a value by steps increments, kept inside the setting's range
+func adjustSetting(s system.TerminalSetting, value float64, steps int) float64 {
+	v := value + float64(steps)*s.Step
+	v = math.Round(v*100) / 100 // no 0.8500000001 from repeated steps
+	return math.Max(s.Min, math.Min(s.Max, v))
+}
+
+// stepTuneTerminal writes m.Choices.TerminalSettings into every deployed terminal config
+func stepTuneTerminal(m *Model) error {
+	stepID := "tuneterminal"
+	terminals := deployedTerminals()
+	if len(terminals) == 0 {
+		return wrapStepError(stepID, "Terminal Settings",
+			"No terminal config found",
+			fmt.Errorf("install Alacritty, WezTerm, Kitty or Ghostty first"))
+	}
+
+	for _, terminal := range terminals {
+		path := terminalConfigPath(terminal)
+		if err := localizeConfig(path); err != nil {
+			return wrapStepError(stepID, "Terminal Settings",
+				fmt.Sprintf("Failed to prepare the %s config", terminal),
+				err)
+		}
+		for _, s := range system.TerminalSettings {
+			value, ok := m.Choices.TerminalSettings[s.Key]
+			if !ok {
+				continue
+			}
+			SendLog(stepID, fmt.Sprintf("Setting %s %s to %s...", terminal, s.Key, system.FormatSetting(value)))
+			if err := system.TuneTerminal(terminal, path, s.Key, value); err != nil {
+				return wrapStepError(stepID, "Terminal Settings",
+					fmt.Sprintf("Failed to set %s in the %s config", s.Key, terminal),
+					err)
+			}
+		}
+	}
+
+	SendLog(stepID, "✓ Terminal settings updated")
+	return nil
+}
@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAdjustSetting(t *testing.T) {
+	opacity, _ := system.LookupTerminalSetting("opacity")
+	v := 0.95
+	for i := 0; i < 3; i++ {
+		v = adjustSetting(opacity, v, -1)
+	}
+	if v != 0.8 {
+		t.Errorf("expected 0.8 after three steps down, got %v", v)
+	}
+	if got := adjustSetting(opacity, 0.95, 2); got != 1 {
+		t.Errorf("expected the value clamped to 1, got %v", got)
+	}
+}
+
+func TestTerminalTuningScreen(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configs := map[string]string{"kitty": "GentlemanKitty/kitty.conf", "ghostty": "GentlemanGhostty/config"}
+	for terminal, rel := range configs {
+		data, err := os.ReadFile(filepath.Join("..", "..", "..", rel))
+		if err != nil {
+			t.Skipf("shipped %s config not available: %v", terminal, err)
+		}
+		path := terminalConfigPath(terminal)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, data, 0644)
+	}
+
+	m := NewModel()
+	m.Screen = ScreenReconfigure
+	for i, opt := range m.GetCurrentOptions() {
+		if strings.Contains(opt, "Terminal Settings") {
+			m.Cursor = i
+		}
+	}
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenReconfigureTerminal || m.TuningNotice != "" {
+		t.Fatalf("expected the tuning screen, got %v (%s)", m.Screen, m.TuningNotice)
+	}
+	if m.TuningSource != "kitty" || m.TuningValues["font-size"] != 14 || m.TuningValues["padding"] != 0 {
+		t.Fatalf("unexpected current values from %s: %v", m.TuningSource, m.TuningValues)
+	}
+
+	// Font size up twice; nothing else changes
+	for _, key := range []string{"l", "l"} {
+		result, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = result.(Model)
+	}
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenInstalling || cmd == nil {
+		t.Fatalf("expected the step to start, got screen %v", m.Screen)
+	}
+	if len(m.Choices.TerminalSettings) != 1 || m.Choices.TerminalSettings["font-size"] != 16 {
+		t.Fatalf("expected only the font size to be written, got %v", m.Choices.TerminalSettings)
+	}
+	if err := stepTuneTerminal(&m); err != nil {
+		t.Fatalf("stepTuneTerminal: %v", err)
+	}
+	kitty, _ := os.ReadFile(terminalConfigPath("kitty"))
+	ghostty, _ := os.ReadFile(terminalConfigPath("ghostty"))
+	if !strings.Contains(string(kitty), "font_size        16.0") || !strings.Contains(string(ghostty), "font-size = 16\n") {
+		t.Errorf("expected both terminals at 16pt")
+	}
+	if !strings.Contains(string(kitty), "# background_blur 20") {
+		t.Error("untouched settings must keep their comments")
+	}
+}
+
+func TestTerminalTuningScreen_NoTerminal(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if _, _, err := currentTerminalSettings(); err == nil {
+		t.Fatal("expected an error without terminal configs")
+	}
+	m := &Model{Choices: UserChoices{TerminalSettings: map[string]float64{"font-size": 16}}}
+	if err := stepTuneTerminal(m); err == nil {
+		t.Error("expected the step to fail without terminal configs")
+	}
+}
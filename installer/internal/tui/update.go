@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -46,6 +47,7 @@ type (
 	execFinishedMsg struct {
 		stepID string
 		err    error
+		finish func() error // in-process remainder of the step
 	}
 )
 
@@ -92,12 +94,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case installStartMsg:
 		// Start the installation process
+		m.StepStarted = time.Now()
 		return m, m.runNextStep()
 
 	case stepProgressMsg:
-		// Update progress
+		// Update progress (log-only messages leave it untouched)
 		for i := range m.Steps {
-			if m.Steps[i].ID == msg.stepID {
+			if m.Steps[i].ID == msg.stepID && msg.progress > 0 {
 				m.Steps[i].Progress = msg.progress
 				break
 			}
@@ -125,12 +128,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.Steps[i].Status = StatusDone
 				m.Steps[i].Progress = 1.0
+				recordStepTiming(msg.stepID, &m, time.Since(m.StepStarted))
 				break
 			}
 		}
 		m.CurrentStep++
+		m.StepStarted = time.Now()
 		return m, m.runNextStep()
 
+	case preflightDoneMsg:
+		// Ignore results that arrive after the user left the screen
+		if m.Screen == ScreenPreflight {
+			m.PreflightChecks = msg.checks
+			m.PreflightRunning = false
+		}
+		return m, nil
+
 	case installCompleteMsg:
 		m.TotalTime = msg.totalTime
 		m.Screen = ScreenComplete
@@ -142,6 +155,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case execFinishedMsg:
 		// Interactive process finished (sudo commands, chsh, etc)
+		if msg.err == nil && msg.finish != nil {
+			stepID, finish := msg.stepID, msg.finish
+			return m, func() tea.Msg {
+				return execFinishedMsg{stepID: stepID, err: finish()}
+			}
+		}
 		for i := range m.Steps {
 			if m.Steps[i].ID == msg.stepID {
 				if msg.err != nil {
@@ -153,18 +172,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.ErrorMsg = fmt.Sprintf("Step '%s' failed:\n%s", m.Steps[i].Name, msg.err.Error())
 					return m, nil
 				}
+				// Not recorded: the duration includes waiting at the password prompt
 				m.Steps[i].Status = StatusDone
 				m.Steps[i].Progress = 1.0
 				break
 			}
 		}
 		m.CurrentStep++
+		m.StepStarted = time.Now()
 		return m, m.runNextStep()
 
 	case needsExecProcessMsg:
 		// This step needs to run with tea.ExecProcess for interactive input
 		return m, tea.ExecProcess(msg.cmd, func(err error) tea.Msg {
-			return execFinishedMsg{stepID: msg.stepID, err: err}
+			return execFinishedMsg{stepID: msg.stepID, err: err, finish: msg.finish}
 		})
 	}
 
@@ -191,6 +212,7 @@ func describeInteractiveStepError(stepID string, err error) error {
 // This suspends the TUI and gives full terminal control to the process
 func execInteractiveCmd(stepID string, name string, args ...string) tea.Cmd {
 	c := exec.Command(name, args...)
+	c.Env = append(os.Environ(), system.NetworkEnv()...)
 	return tea.ExecProcess(c, func(err error) tea.Msg {
 		return execFinishedMsg{stepID: stepID, err: err}
 	})
@@ -270,7 +292,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case ScreenMainMenu:
 		return m.handleMainMenuKeys(key)
 
-	case ScreenOSSelect, ScreenTerminalSelect, ScreenFontSelect, ScreenShellSelect, ScreenWMSelect, ScreenNvimSelect, ScreenGhosttyWarning:
+	case ScreenOSSelect, ScreenTerminalSelect, ScreenFontSelect, ScreenShellSelect, ScreenWMSelect, ScreenNvimSelect, ScreenGhosttyWarning, ScreenNixMode:
 		return m.handleSelectionKeys(key)
 
 	case ScreenLearnTerminals, ScreenLearnShells, ScreenLearnWM, ScreenLearnNvim:
@@ -303,6 +325,12 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case ScreenKeymapsGhosttyCat:
 		return m.handleGhosttyKeymapCategoryKeys(key)
 
+	case ScreenKeymapsHerdr:
+		return m.handleHerdrKeymapsMenuKeys(key)
+
+	case ScreenKeymapsHerdrCat:
+		return m.handleHerdrKeymapCategoryKeys(key)
+
 	case ScreenLearnLazyVim:
 		return m.handleLazyVimMenuKeys(key)
 
@@ -312,12 +340,27 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case ScreenBackupConfirm:
 		return m.handleBackupConfirmKeys(key)
 
+	case ScreenPreflight:
+		return m.handlePreflightKeys(key)
+
 	case ScreenRestoreBackup:
 		return m.handleRestoreBackupKeys(key)
 
 	case ScreenRestoreConfirm:
 		return m.handleRestoreConfirmKeys(key)
 
+	case ScreenReconfigure, ScreenReconfigureWM, ScreenReconfigureShell, ScreenReconfigureTheme, ScreenReconfigureLayout:
+		return m.handleReconfigureKeys(key)
+
+	case ScreenReconfigureShaders:
+		return m.handleShaderKeys(key)
+
+	case ScreenReconfigureTerminal:
+		return m.handleTuningKeys(key)
+
+	case ScreenReconfigureHerdr:
+		return m.handleHerdrKeysScreenKeys(key)
+
 	// Trainer screens
 	case ScreenTrainerMenu:
 		return m.handleTrainerMenuKeys(key)
@@ -359,7 +402,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) handleEscape() (tea.Model, tea.Cmd) {
 	switch m.Screen {
 	// Installation wizard screens - go back through the flow
-	case ScreenOSSelect, ScreenTerminalSelect, ScreenFontSelect, ScreenShellSelect, ScreenWMSelect, ScreenNvimSelect:
+	case ScreenOSSelect, ScreenTerminalSelect, ScreenFontSelect, ScreenShellSelect, ScreenWMSelect, ScreenNvimSelect, ScreenNixMode:
 		return m.goBackInstallStep()
 	case ScreenGhosttyWarning:
 		// Go back to terminal selection
@@ -369,6 +412,12 @@ func (m Model) handleEscape() (tea.Model, tea.Cmd) {
 		// Go back to Nvim selection (not abort)
 		m.Screen = ScreenNvimSelect
 		m.Cursor = 0
+	case ScreenPreflight:
+		// Back to whichever screen started the checks
+		m.Screen = m.PrevScreen
+		m.Cursor = 0
+		m.PreflightChecks = nil
+		m.PreflightRunning = false
 	// Content/Learn screens
 	case ScreenKeymapCategory:
 		m.Screen = ScreenKeymaps
@@ -382,6 +431,9 @@ func (m Model) handleEscape() (tea.Model, tea.Cmd) {
 	case ScreenKeymapsGhosttyCat:
 		m.Screen = ScreenKeymapsGhostty
 		m.GhosttyKeymapScroll = 0
+	case ScreenKeymapsHerdrCat:
+		m.Screen = ScreenKeymapsHerdr
+		m.HerdrKeymapScroll = 0
 	case ScreenLazyVimTopic:
 		m.Screen = ScreenLearnLazyVim
 		m.LazyVimScroll = 0
@@ -392,7 +444,7 @@ func (m Model) handleEscape() (tea.Model, tea.Cmd) {
 	case ScreenKeymaps:
 		m.Screen = ScreenKeymapsMenu
 		m.Cursor = 0
-	case ScreenKeymapsTmux, ScreenKeymapsZellij, ScreenKeymapsGhostty:
+	case ScreenKeymapsTmux, ScreenKeymapsZellij, ScreenKeymapsGhostty, ScreenKeymapsHerdr:
 		m.Screen = ScreenKeymapsMenu
 		m.Cursor = 0
 	case ScreenKeymapsMenu, ScreenLearnLazyVim:
@@ -402,6 +454,13 @@ func (m Model) handleEscape() (tea.Model, tea.Cmd) {
 	case ScreenRestoreBackup, ScreenRestoreConfirm:
 		m.Screen = ScreenMainMenu
 		m.Cursor = 0
+	// Reconfigure screens
+	case ScreenReconfigure:
+		m.Screen = ScreenMainMenu
+		m.Cursor = 0
+	case ScreenReconfigureWM, ScreenReconfigureShell, ScreenReconfigureTheme, ScreenReconfigureShaders, ScreenReconfigureTerminal, ScreenReconfigureLayout, ScreenReconfigureHerdr:
+		m.Screen = ScreenReconfigure
+		m.Cursor = 0
 	// Trainer screens
 	case ScreenTrainerMenu:
 		// Save stats and return to main menu
@@ -447,6 +506,7 @@ func (m Model) handleMainMenuKeys(key string) (tea.Model, tea.Cmd) {
 		switch {
 		case strings.Contains(selected, "Start Installation"):
 			m.Screen = ScreenOSSelect
+			m.loadEstimateInputs()
 			// Pre-select detected OS
 			if m.SystemInfo.OS == system.OSLinux {
 				m.Cursor = 1 // Linux is second option
@@ -480,6 +540,9 @@ func (m Model) handleMainMenuKeys(key string) (tea.Model, tea.Cmd) {
 		case strings.Contains(selected, "Restore from Backup") && hasRestoreOption:
 			m.Screen = ScreenRestoreBackup
 			m.Cursor = 0
+		case strings.Contains(selected, "Reconfigure"):
+			m.Screen = ScreenReconfigure
+			m.Cursor = 0
 		case strings.Contains(selected, "Exit"):
 			m.Quitting = true
 			return m, tea.Quit
@@ -515,6 +578,16 @@ func (m Model) handleSelectionKeys(key string) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case "left", "h", "right", "l":
+		// The font screen picks the nerd-fonts release inline
+		if m.Screen == ScreenFontSelect {
+			delta := 1
+			if key == "left" || key == "h" {
+				delta = -1
+			}
+			m.Choices.FontVersion = cycleFontVersion(selectedFontVersion(m.Choices), delta)
+		}
+
 	case "esc", "backspace":
 		// Go back to previous installation step
 		return m.goBackInstallStep()
@@ -547,6 +620,8 @@ func (m Model) goBackInstallStep() (tea.Model, tea.Cmd) {
 		m.Cursor = 0
 		// Reset font choice
 		m.Choices.InstallFont = false
+		m.Choices.Font = ""
+		m.Choices.FontVersion = ""
 
 	case ScreenShellSelect:
 		// Termux: go back to OS selection (skipped terminal and font)
@@ -570,6 +645,11 @@ func (m Model) goBackInstallStep() (tea.Model, tea.Cmd) {
 		m.Screen = ScreenWMSelect
 		m.Cursor = 0
 		m.Choices.InstallNvim = false
+
+	case ScreenNixMode:
+		m.Screen = ScreenNvimSelect
+		m.Cursor = 0
+		m.Choices.NixMode = false
 	}
 
 	return m, nil
@@ -666,7 +746,14 @@ func (m Model) handleSelection() (tea.Model, tea.Cmd) {
 		m.Cursor = 0
 
 	case ScreenFontSelect:
-		m.Choices.InstallFont = m.Cursor == 0
+		m.Choices.InstallFont = m.Cursor != 1
+		m.Choices.Font = ""
+		if m.Cursor == 0 {
+			// Named, so the Termux default cannot replace the font the option shows
+			m.Choices.Font = nerdFonts[0].ID
+		} else if font, ok := lookupNerdFont(options[m.Cursor]); ok {
+			m.Choices.Font = font.ID
+		}
 		m.Screen = ScreenShellSelect
 		m.Cursor = 0
 
@@ -695,19 +782,95 @@ func (m Model) handleSelection() (tea.Model, tea.Cmd) {
 
 	case ScreenNvimSelect:
 		m.Choices.InstallNvim = m.Cursor == 0
-		// Detect existing configs before proceeding
-		m.ExistingConfigs = system.DetectExistingConfigs()
-		if len(m.ExistingConfigs) > 0 {
-			// Show backup confirmation screen
-			m.Screen = ScreenBackupConfirm
+		// On Nix, offer a home-manager module instead of imperative installs
+		if nixDetected(m.SystemInfo) {
+			m.Screen = ScreenNixMode
 			m.Cursor = 0
-		} else {
-			// No existing configs, proceed directly
+			return m, nil
+		}
+		return m.startInstallation()
+
+	case ScreenNixMode:
+		m.Choices.NixMode = m.Cursor == 0
+		if m.Choices.NixMode {
+			// The module lives outside $HOME's configs, nothing to back up
+			return m.beginPreflight(ScreenNixMode)
+		}
+		return m.startInstallation()
+	}
+
+	return m, nil
+}
+
+// startInstallation asks about backups when configs exist, otherwise starts installing
+func (m Model) startInstallation() (tea.Model, tea.Cmd) {
+	// Detect existing configs before proceeding
+	m.ExistingConfigs = system.DetectExistingConfigs()
+	if len(m.ExistingConfigs) > 0 {
+		// Show backup confirmation screen
+		m.Screen = ScreenBackupConfirm
+		m.Cursor = 0
+		return m, nil
+	}
+
+	// No existing configs, proceed directly
+	return m.beginPreflight(m.Screen)
+}
+
+// beginPreflight shows the pre-flight screen and starts the checks.
+// from is where Esc returns to.
+func (m Model) beginPreflight(from Screen) (tea.Model, tea.Cmd) {
+	m.PrevScreen = from
+	m.Screen = ScreenPreflight
+	m.Cursor = 0
+	m.PreflightChecks = nil
+	m.PreflightRunning = true
+	m.PreflightConfirm = false
+	return m, preflightCmd(m)
+}
+
+func (m Model) handlePreflightKeys(key string) (tea.Model, tea.Cmd) {
+	if m.PreflightRunning {
+		return m, nil
+	}
+	options := m.GetCurrentOptions()
+
+	switch key {
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+		m.PreflightConfirm = false
+	case "down", "j":
+		if m.Cursor < len(options)-1 {
+			m.Cursor++
+		}
+		m.PreflightConfirm = false
+	case "enter", " ":
+		switch {
+		case m.Cursor == 0 && preflightHasFailures(m.PreflightChecks) && !m.PreflightConfirm:
+			// Failed checks: the first Enter only asks for confirmation
+			m.PreflightConfirm = true
+		case m.Cursor == 0: // Start installation
 			m.SetupInstallSteps()
 			m.Screen = ScreenInstalling
 			m.CurrentStep = 0
 			return m, func() tea.Msg { return installStartMsg{} }
+		case m.Cursor == 1: // Run checks again
+			return m.beginPreflight(m.PrevScreen)
+		case options[m.Cursor] == preflightAllowScriptsOption:
+			system.SetAllowInstallScripts(true)
+			return m.beginPreflight(m.PrevScreen)
+		case options[m.Cursor] == preflightAllowUnpinnedOption:
+			system.SetAllowUnpinned(true)
+			return m.beginPreflight(m.PrevScreen)
+		default: // Cancel - abort the entire wizard
+			m.Screen = ScreenMainMenu
+			m.Cursor = 0
+			m.Choices = UserChoices{}
 		}
+	case "backspace":
+		return m.handleEscape()
 	}
 
 	return m, nil
@@ -882,6 +1045,10 @@ func (m Model) handleToolKeymapsMenuKeys(key string) (tea.Model, tea.Cmd) {
 		case 3: // Ghostty
 			m.Screen = ScreenKeymapsGhostty
 			m.Cursor = 0
+		case 4: // Herdr, re-read so edits from the Herdr keys screen show up
+			m.HerdrKeymapCategories = GetHerdrKeymaps()
+			m.Screen = ScreenKeymapsHerdr
+			m.Cursor = 0
 		}
 	}
 
@@ -1098,6 +1265,76 @@ func (m Model) handleGhosttyKeymapCategoryKeys(key string) (tea.Model, tea.Cmd)
 	return m, nil
 }
 
+// handleHerdrKeymapsMenuKeys handles Herdr keymap category selection
+func (m Model) handleHerdrKeymapsMenuKeys(key string) (tea.Model, tea.Cmd) {
+	options := m.GetCurrentOptions()
+
+	switch key {
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+			if strings.HasPrefix(options[m.Cursor], "───") && m.Cursor > 0 {
+				m.Cursor--
+			}
+		}
+	case "down", "j":
+		if m.Cursor < len(options)-1 {
+			m.Cursor++
+			if strings.HasPrefix(options[m.Cursor], "───") && m.Cursor < len(options)-1 {
+				m.Cursor++
+			}
+		}
+	case "enter", " ":
+		selected := options[m.Cursor]
+		if strings.Contains(selected, "Back") {
+			m.Screen = ScreenKeymapsMenu
+			m.Cursor = 0
+			return m, nil
+		}
+		if strings.HasPrefix(selected, "───") {
+			return m, nil
+		}
+
+		// Select category and show keymaps
+		m.HerdrSelectedCategory = m.Cursor
+		m.Screen = ScreenKeymapsHerdrCat
+		m.HerdrKeymapScroll = 0
+	}
+
+	return m, nil
+}
+
+// handleHerdrKeymapCategoryKeys handles scrolling in Herdr keymap category view
+func (m Model) handleHerdrKeymapCategoryKeys(key string) (tea.Model, tea.Cmd) {
+	category := m.HerdrKeymapCategories[m.HerdrSelectedCategory]
+
+	visibleItems := m.Height - 9
+	if visibleItems < 5 {
+		visibleItems = 5
+	}
+
+	maxScroll := len(category.Keymaps) - visibleItems
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+
+	switch key {
+	case "up", "k":
+		if m.HerdrKeymapScroll > 0 {
+			m.HerdrKeymapScroll--
+		}
+	case "down", "j":
+		if m.HerdrKeymapScroll < maxScroll {
+			m.HerdrKeymapScroll++
+		}
+	case "enter", " ", "q", "esc":
+		m.Screen = ScreenKeymapsHerdr
+		m.HerdrKeymapScroll = 0
+	}
+
+	return m, nil
+}
+
 func (m Model) handleLazyVimMenuKeys(key string) (tea.Model, tea.Cmd) {
 	options := m.GetCurrentOptions()
 
@@ -1196,16 +1433,10 @@ func (m Model) handleBackupConfirmKeys(key string) (tea.Model, tea.Cmd) {
 		switch m.Cursor {
 		case 0: // Install with Backup
 			m.Choices.CreateBackup = true
-			m.SetupInstallSteps()
-			m.Screen = ScreenInstalling
-			m.CurrentStep = 0
-			return m, func() tea.Msg { return installStartMsg{} }
+			return m.beginPreflight(ScreenBackupConfirm)
 		case 1: // Install without Backup
 			m.Choices.CreateBackup = false
-			m.SetupInstallSteps()
-			m.Screen = ScreenInstalling
-			m.CurrentStep = 0
-			return m, func() tea.Msg { return installStartMsg{} }
+			return m.beginPreflight(ScreenBackupConfirm)
 		case 2: // Cancel - abort the entire wizard
 			m.Screen = ScreenMainMenu
 			m.Cursor = 0
@@ -1311,6 +1542,248 @@ func (m Model) handleRestoreConfirmKeys(key string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleReconfigureKeys handles the Reconfigure menu and its setting pickers
+func (m Model) handleReconfigureKeys(key string) (tea.Model, tea.Cmd) {
+	options := m.GetCurrentOptions()
+
+	switch key {
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+			// Skip separator
+			if strings.HasPrefix(options[m.Cursor], "───") && m.Cursor > 0 {
+				m.Cursor--
+			}
+		}
+	case "down", "j":
+		if m.Cursor < len(options)-1 {
+			m.Cursor++
+			// Skip separator
+			if strings.HasPrefix(options[m.Cursor], "───") && m.Cursor < len(options)-1 {
+				m.Cursor++
+			}
+		}
+	case "enter", " ":
+		selected := options[m.Cursor]
+		if strings.HasPrefix(selected, "───") {
+			return m, nil
+		}
+		if strings.Contains(selected, "Back") {
+			return m.handleEscape()
+		}
+
+		switch m.Screen {
+		case ScreenReconfigure:
+			switch {
+			case strings.Contains(selected, "Window Manager"):
+				m.Screen = ScreenReconfigureWM
+				m.Cursor = 0
+			case strings.Contains(selected, "Shell"):
+				m.Screen = ScreenReconfigureShell
+				m.Cursor = 0
+			case strings.Contains(selected, "Theme"):
+				m.Screen = ScreenReconfigureTheme
+				m.Cursor = 0
+			case strings.Contains(selected, "Shaders"):
+				shaders, err := CurrentGhosttyShaders()
+				m.Choices = UserChoices{Shaders: shaders}
+				m.ShaderNotice = ""
+				if err != nil {
+					m.ShaderNotice = err.Error()
+				}
+				m.Screen = ScreenReconfigureShaders
+				m.Cursor = 0
+			case strings.Contains(selected, "Terminal Settings"):
+				values, source, err := currentTerminalSettings()
+				m.TuningValues, m.TuningBase, m.TuningSource = values, map[string]float64{}, source
+				for key, v := range values {
+					m.TuningBase[key] = v
+				}
+				m.TuningNotice = ""
+				if err != nil {
+					m.TuningNotice = err.Error()
+				}
+				m.Screen = ScreenReconfigureTerminal
+				m.Cursor = 0
+			case strings.Contains(selected, "Zellij Layout"):
+				layouts, current, err := InstalledZellijLayouts()
+				m.ZellijLayouts = layouts
+				m.Choices = UserChoices{ZellijLayout: current}
+				m.LayoutNotice = ""
+				if err != nil {
+					m.LayoutNotice = err.Error()
+				}
+				m.Screen = ScreenReconfigureLayout
+				m.Cursor = 0
+			case strings.Contains(selected, "Herdr Keys"):
+				values, err := currentHerdrKeys()
+				m.HerdrKeyValues, m.HerdrKeyBase = values, map[string]string{}
+				for key, v := range values {
+					m.HerdrKeyBase[key] = v
+				}
+				m.HerdrNotice = ""
+				if err != nil {
+					m.HerdrNotice = err.Error()
+				}
+				m.Screen = ScreenReconfigureHerdr
+				m.Cursor = 0
+			}
+		case ScreenReconfigureWM:
+			m.Choices = UserChoices{WindowMgr: strings.ToLower(selected)}
+			m.SetupReconfigureSteps("wm")
+			m.Screen = ScreenInstalling
+			m.CurrentStep = 0
+			return m, func() tea.Msg { return installStartMsg{} }
+		case ScreenReconfigureShell:
+			m.Choices = UserChoices{Shell: strings.ToLower(selected)}
+			m.SetupReconfigureSteps("shell")
+			m.Screen = ScreenInstalling
+			m.CurrentStep = 0
+			return m, func() tea.Msg { return installStartMsg{} }
+		case ScreenReconfigureTheme:
+			m.Choices = UserChoices{Theme: themeByName(selected)}
+			m.SetupReconfigureSteps("theme")
+			m.Screen = ScreenInstalling
+			m.CurrentStep = 0
+			return m, func() tea.Msg { return installStartMsg{} }
+		case ScreenReconfigureLayout:
+			m.Choices = UserChoices{ZellijLayout: layoutByOption(selected)}
+			m.SetupReconfigureSteps("layout")
+			m.Screen = ScreenInstalling
+			m.CurrentStep = 0
+			return m, func() tea.Msg { return installStartMsg{} }
+		}
+	}
+
+	return m, nil
+}
+
+// handleShaderKeys handles the Ghostty shader picker: x toggles, K/J reorder
+func (m Model) handleShaderKeys(key string) (tea.Model, tea.Cmd) {
+	shaders := system.GhosttyShaders
+
+	switch key {
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if m.Cursor < len(shaders)-1 {
+			m.Cursor++
+		}
+	case "x":
+		m.Choices.Shaders = toggleShader(m.Choices.Shaders, shaders[m.Cursor].File)
+	case "K", "shift+up":
+		m.Choices.Shaders = moveShader(m.Choices.Shaders, shaders[m.Cursor].File, -1)
+	case "J", "shift+down":
+		m.Choices.Shaders = moveShader(m.Choices.Shaders, shaders[m.Cursor].File, 1)
+	case "c":
+		m.Choices.Shaders = nil
+	case "enter":
+		if m.ShaderNotice != "" {
+			return m, nil
+		}
+		m.SetupReconfigureSteps("shaders")
+		m.Screen = ScreenInstalling
+		m.CurrentStep = 0
+		return m, func() tea.Msg { return installStartMsg{} }
+	}
+
+	return m, nil
+}
+
+// handleTuningKeys handles the terminal tuning screen: ←/→ change the highlighted setting
+func (m Model) handleTuningKeys(key string) (tea.Model, tea.Cmd) {
+	settings := system.TerminalSettings
+
+	switch key {
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if m.Cursor < len(settings)-1 {
+			m.Cursor++
+		}
+	case "left", "h", "right", "l":
+		if m.TuningNotice != "" {
+			return m, nil
+		}
+		steps := 1
+		if key == "left" || key == "h" {
+			steps = -1
+		}
+		s := settings[m.Cursor]
+		m.TuningValues[s.Key] = adjustSetting(s, m.TuningValues[s.Key], steps)
+	case "enter":
+		if m.TuningNotice != "" {
+			return m, nil
+		}
+		changed := map[string]float64{}
+		for key, v := range m.TuningValues {
+			if v != m.TuningBase[key] {
+				changed[key] = v
+			}
+		}
+		if len(changed) == 0 {
+			return m.handleEscape()
+		}
+		m.Choices = UserChoices{TerminalSettings: changed}
+		m.SetupReconfigureSteps("terminal")
+		m.Screen = ScreenInstalling
+		m.CurrentStep = 0
+		return m, func() tea.Msg { return installStartMsg{} }
+	}
+
+	return m, nil
+}
+
+// handleHerdrKeysScreenKeys handles the Herdr keys screen: ←/→ cycle the highlighted binding
+func (m Model) handleHerdrKeysScreenKeys(key string) (tea.Model, tea.Cmd) {
+	bindings := system.HerdrBindings
+
+	switch key {
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if m.Cursor < len(bindings)-1 {
+			m.Cursor++
+		}
+	case "left", "h", "right", "l":
+		if m.HerdrNotice != "" {
+			return m, nil
+		}
+		steps := 1
+		if key == "left" || key == "h" {
+			steps = -1
+		}
+		b := bindings[m.Cursor]
+		m.HerdrKeyValues[b.Key] = cycleHerdrKey(b, m.HerdrKeyBase[b.Key], m.HerdrKeyValues[b.Key], steps)
+	case "enter":
+		if m.HerdrNotice != "" {
+			return m, nil
+		}
+		changed := map[string]string{}
+		for key, v := range m.HerdrKeyValues {
+			if v != m.HerdrKeyBase[key] {
+				changed[key] = v
+			}
+		}
+		if len(changed) == 0 {
+			return m.handleEscape()
+		}
+		m.Choices = UserChoices{HerdrKeys: changed}
+		m.SetupReconfigureSteps("herdrkeys")
+		m.Screen = ScreenInstalling
+		m.CurrentStep = 0
+		return m, func() tea.Msg { return installStartMsg{} }
+	}
+
+	return m, nil
+}
+
 // runNextStep starts the next installation step
 func (m Model) runNextStep() tea.Cmd {
 	if m.CurrentStep >= len(m.Steps) {
@@ -79,8 +79,8 @@ func TestHandleBackupConfirmKeys(t *testing.T) {
 			t.Error("CreateBackup should be true when selecting backup option")
 		}
 
-		if newModel.Screen != ScreenInstalling {
-			t.Errorf("Expected ScreenInstalling, got %v", newModel.Screen)
+		if newModel.Screen != ScreenPreflight {
+			t.Errorf("Expected ScreenPreflight, got %v", newModel.Screen)
 		}
 	})
 
@@ -246,7 +246,7 @@ func TestHandleMainMenuWithRestore(t *testing.T) {
 		m.AvailableBackups = []system.BackupInfo{
 			{Path: "/test/backup1"},
 		}
-		// Options: Start, Learn, Keymaps, LazyVim, Vim Trainer, Restore, Exit
+		// Options: Start, Learn, Keymaps, LazyVim, Vim Trainer, Restore, Reconfigure, Exit
 		// Restore is at index 5
 		m.Cursor = 5
 
@@ -262,9 +262,9 @@ func TestHandleMainMenuWithRestore(t *testing.T) {
 		m := NewModel()
 		m.Screen = ScreenMainMenu
 		m.AvailableBackups = []system.BackupInfo{} // No backups
-		// Options without restore: Start, Learn, Keymaps, LazyVim, Vim Trainer, Exit
-		// Exit is at index 5
-		m.Cursor = 5
+		// Options without restore: Start, Learn, Keymaps, LazyVim, Vim Trainer, Reconfigure, Exit
+		// Exit is at index 6
+		m.Cursor = 6
 
 		_, cmd := m.handleMainMenuKeys("enter")
 
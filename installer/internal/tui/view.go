@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
 	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/tui/trainer"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -62,7 +63,7 @@ func (m Model) View() string {
 		s.WriteString(m.renderWelcome())
 	case ScreenMainMenu:
 		s.WriteString(m.renderMainMenu())
-	case ScreenOSSelect, ScreenTerminalSelect, ScreenFontSelect, ScreenShellSelect, ScreenWMSelect, ScreenNvimSelect, ScreenGhosttyWarning:
+	case ScreenOSSelect, ScreenTerminalSelect, ScreenFontSelect, ScreenShellSelect, ScreenWMSelect, ScreenNvimSelect, ScreenGhosttyWarning, ScreenNixMode:
 		s.WriteString(m.renderSelection())
 	case ScreenLearnTerminals:
 		s.WriteString(m.renderLearnTerminals())
@@ -90,12 +91,18 @@ func (m Model) View() string {
 		s.WriteString(m.renderGhosttyKeymapsMenu())
 	case ScreenKeymapsGhosttyCat:
 		s.WriteString(m.renderGhosttyKeymapCategory())
+	case ScreenKeymapsHerdr:
+		s.WriteString(m.renderHerdrKeymapsMenu())
+	case ScreenKeymapsHerdrCat:
+		s.WriteString(m.renderHerdrKeymapCategory())
 	case ScreenLearnLazyVim:
 		s.WriteString(m.renderLazyVimMenu())
 	case ScreenLazyVimTopic:
 		s.WriteString(m.renderLazyVimTopic())
 	case ScreenBackupConfirm:
 		s.WriteString(m.renderBackupConfirm())
+	case ScreenPreflight:
+		s.WriteString(m.renderPreflight())
 	case ScreenRestoreBackup:
 		s.WriteString(m.renderRestoreBackup())
 	case ScreenRestoreConfirm:
@@ -119,6 +126,15 @@ func (m Model) View() string {
 		s.WriteString(m.renderTrainerResult())
 	case ScreenTrainerBossResult:
 		s.WriteString(m.renderTrainerBossResult())
+	// Reconfigure screens
+	case ScreenReconfigure, ScreenReconfigureWM, ScreenReconfigureShell, ScreenReconfigureTheme, ScreenReconfigureLayout:
+		s.WriteString(m.renderReconfigure())
+	case ScreenReconfigureShaders:
+		s.WriteString(m.renderShaderPicker())
+	case ScreenReconfigureTerminal:
+		s.WriteString(m.renderTerminalTuning())
+	case ScreenReconfigureHerdr:
+		s.WriteString(m.renderHerdrKeys())
 	}
 
 	// Leader mode indicator
@@ -222,8 +238,30 @@ func (m Model) renderSelection() string {
 		s.WriteString("\n")
 	}
 
+	if m.Screen == ScreenFontSelect {
+		s.WriteString("\n")
+		s.WriteString(InfoStyle.Render("Version: ◂ " + selectedFontVersion(m.Choices) + " ▸"))
+		if m.Choices.OS == "mac" {
+			s.WriteString(MutedStyle.Render("  (Homebrew installs the latest cask)"))
+		}
+		s.WriteString("\n")
+	}
+
+	// Cost of the highlighted option and of the whole install with it
+	if option, total, ok := m.selectionEstimate(); ok {
+		s.WriteString("\n")
+		s.WriteString(InfoStyle.Render("This option: " + option.String()))
+		s.WriteString("\n")
+		s.WriteString(MutedStyle.Render("Total so far: " + total.String()))
+		s.WriteString("\n")
+	}
+
+	help := "↑/k up • ↓/j down • [Enter] select • [Esc] back"
+	if m.Screen == ScreenFontSelect {
+		help = "↑/k up • ↓/j down • ←/→ version • [Enter] select • [Esc] back"
+	}
 	s.WriteString("\n")
-	s.WriteString(HelpStyle.Render("↑/k up • ↓/j down • [Enter] select • [Esc] back"))
+	s.WriteString(HelpStyle.Render(help))
 
 	return s.String()
 }
@@ -243,7 +281,7 @@ func (m Model) renderStepProgress() string {
 		currentIdx = 3
 	case ScreenWMSelect:
 		currentIdx = 4
-	case ScreenNvimSelect:
+	case ScreenNvimSelect, ScreenNixMode:
 		currentIdx = 5
 	}
 
@@ -886,6 +924,103 @@ func (m Model) renderGhosttyKeymapCategory() string {
 	return s.String()
 }
 
+// renderHerdrKeymapsMenu renders the Herdr keymap categories menu
+func (m Model) renderHerdrKeymapsMenu() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render(m.GetScreenTitle()))
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render("Select a category to view Herdr keybindings"))
+	s.WriteString("\n\n")
+
+	// Menu
+	options := m.GetCurrentOptions()
+	for i, opt := range options {
+		if strings.HasPrefix(opt, "───") {
+			s.WriteString(MutedStyle.Render(opt))
+			s.WriteString("\n")
+			continue
+		}
+
+		cursor := "  "
+		style := UnselectedStyle
+		if i == m.Cursor {
+			cursor = "▸ "
+			style = SelectedStyle
+		}
+		s.WriteString(style.Render(cursor + opt))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(HelpStyle.Render("↑/k up • ↓/j down • [Enter] select • [Esc/q] back"))
+
+	return s.String()
+}
+
+// renderHerdrKeymapCategory renders a specific Herdr keymap category
+func (m Model) renderHerdrKeymapCategory() string {
+	var s strings.Builder
+
+	if m.HerdrSelectedCategory >= len(m.HerdrKeymapCategories) {
+		return ErrorStyle.Render("Category not found")
+	}
+
+	category := m.HerdrKeymapCategories[m.HerdrSelectedCategory]
+
+	s.WriteString(TitleStyle.Render(category.Name))
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render(category.Description))
+	s.WriteString("\n\n")
+
+	// Table header
+	header := fmt.Sprintf("%-18s %-6s %s", "Keys", "Mode", "Description")
+	s.WriteString(SubtitleStyle.Render(header))
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render(strings.Repeat("─", 60)))
+	s.WriteString("\n")
+
+	// Calculate visible items
+	visibleItems := m.Height - 9
+	if visibleItems < 5 {
+		visibleItems = 5
+	}
+	if visibleItems > len(category.Keymaps) {
+		visibleItems = len(category.Keymaps)
+	}
+
+	// Keymaps with scrolling
+	start := m.HerdrKeymapScroll
+	end := start + visibleItems
+	if end > len(category.Keymaps) {
+		end = len(category.Keymaps)
+		start = end - visibleItems
+		if start < 0 {
+			start = 0
+		}
+	}
+
+	for i := start; i < end; i++ {
+		km := category.Keymaps[i]
+		s.WriteString(KeyStyle.Render(km.Keys))
+		s.WriteString(MutedStyle.Render(fmt.Sprintf(" %-6s ", km.Mode)))
+		s.WriteString(InfoStyle.Render(km.Description))
+		s.WriteString("\n")
+	}
+
+	// Scroll indicator
+	if len(category.Keymaps) > visibleItems {
+		s.WriteString("\n")
+		scrollInfo := fmt.Sprintf("Showing %d-%d of %d", start+1, end, len(category.Keymaps))
+		s.WriteString(MutedStyle.Render(scrollInfo))
+	}
+
+	s.WriteString("\n\n")
+	s.WriteString(HelpStyle.Render("↑/k up • ↓/j down • [Enter/Esc/q] back"))
+
+	return s.String()
+}
+
 func (m Model) renderLazyVimMenu() string {
 	var s strings.Builder
 
@@ -1014,7 +1149,11 @@ var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "
 func (m Model) renderInstalling() string {
 	var s strings.Builder
 
-	s.WriteString(TitleStyle.Render("🚀 Installing Gentleman.Dots"))
+	if m.Reconfiguring != "" {
+		s.WriteString(TitleStyle.Render("🔧 Reconfiguring Gentleman.Dots"))
+	} else {
+		s.WriteString(TitleStyle.Render("🚀 Installing Gentleman.Dots"))
+	}
 	s.WriteString("\n\n")
 
 	// Progress steps
@@ -1047,7 +1186,11 @@ func (m Model) renderInstalling() string {
 
 		// Show current step description
 		if i == m.CurrentStep && step.Status == StatusRunning {
-			s.WriteString(MutedStyle.Render("   " + step.Description))
+			desc := "   " + step.Description
+			if step.Progress > 0 && step.Progress < 1 {
+				desc += fmt.Sprintf(" (%d%%)", int(step.Progress*100))
+			}
+			s.WriteString(MutedStyle.Render(desc))
 			s.WriteString("\n")
 		}
 	}
@@ -1065,6 +1208,10 @@ func (m Model) renderInstalling() string {
 }
 
 func (m Model) renderComplete() string {
+	if m.Reconfiguring != "" {
+		return m.renderReconfigureComplete()
+	}
+
 	var s strings.Builder
 
 	s.WriteString(SuccessStyle.Render("✨ Installation Complete! ✨"))
@@ -1082,7 +1229,7 @@ func (m Model) renderComplete() string {
 	}
 
 	if m.Choices.InstallFont {
-		items = append(items, "Font: Iosevka Term Nerd Font")
+		items = append(items, "Font: "+FontDescription(m.Choices))
 	}
 	if m.Choices.InstallNvim {
 		items = append(items, "Editor: Neovim with Gentleman config")
@@ -1104,6 +1251,17 @@ func (m Model) renderComplete() string {
 	s.WriteString(TitleStyle.Render("Next Step"))
 	s.WriteString("\n\n")
 
+	if m.Choices.NixMode {
+		s.WriteString(InfoStyle.Render("Import the generated module into your home-manager config:"))
+		s.WriteString("\n")
+		s.WriteString(HighlightStyle.Render(fmt.Sprintf("   imports = [ %s ];", NixOutputDir())))
+		s.WriteString("\n")
+		s.WriteString(InfoStyle.Render("then run `home-manager switch` and set your login shell in configuration.nix."))
+		s.WriteString("\n\n")
+		s.WriteString(HelpStyle.Render("Press [Enter] or [q] to exit"))
+		return s.String()
+	}
+
 	s.WriteString(InfoStyle.Render("To use your new shell now, run:"))
 	s.WriteString("\n")
 	s.WriteString(HighlightStyle.Render(fmt.Sprintf("   exec %s", shellCmd)))
@@ -1114,6 +1272,243 @@ func (m Model) renderComplete() string {
 	return s.String()
 }
 
+func (m Model) renderReconfigure() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render(m.GetScreenTitle()))
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render(m.GetScreenDescription()))
+	s.WriteString("\n\n")
+	if m.Screen == ScreenReconfigureLayout && m.LayoutNotice != "" {
+		s.WriteString(WarningStyle.Render("⚠️  " + m.LayoutNotice))
+		s.WriteString("\n\n")
+	}
+
+	options := m.GetCurrentOptions()
+	for i, opt := range options {
+		if strings.HasPrefix(opt, "───") {
+			s.WriteString(MutedStyle.Render(opt))
+			s.WriteString("\n")
+			continue
+		}
+
+		cursor := "  "
+		style := UnselectedStyle
+		if i == m.Cursor {
+			cursor = "▸ "
+			style = SelectedStyle
+		}
+		s.WriteString(style.Render(cursor + opt))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(HelpStyle.Render("↑/k up • ↓/j down • [Enter] select • [Esc] back"))
+
+	return s.String()
+}
+
+func (m Model) renderShaderPicker() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render(m.GetScreenTitle()))
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render(m.GetScreenDescription()))
+	s.WriteString("\n\n")
+	if m.ShaderNotice != "" {
+		s.WriteString(WarningStyle.Render("⚠️  " + m.ShaderNotice))
+		s.WriteString("\n\n")
+	}
+
+	shaders := system.GhosttyShaders
+
+	// Keep the cursor inside the visible window
+	visibleItems := m.Height - 14
+	if visibleItems < 5 {
+		visibleItems = 5
+	}
+	if visibleItems > len(shaders) {
+		visibleItems = len(shaders)
+	}
+	start := m.Cursor - visibleItems/2
+	if start > len(shaders)-visibleItems {
+		start = len(shaders) - visibleItems
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	for i := start; i < start+visibleItems; i++ {
+		shader := shaders[i]
+		mark := "[ ]"
+		if pos := shaderPosition(m.Choices.Shaders, shader.File); pos > 0 {
+			mark = fmt.Sprintf("[%d]", pos)
+		}
+		cursor := "  "
+		style := UnselectedStyle
+		if i == m.Cursor {
+			cursor = "▸ "
+			style = SelectedStyle
+		}
+		s.WriteString(style.Render(fmt.Sprintf("%s%s %-28s", cursor, mark, shader.Name())))
+		s.WriteString(MutedStyle.Render(" " + shader.Tags()))
+		s.WriteString("\n")
+	}
+	if len(shaders) > visibleItems {
+		s.WriteString(MutedStyle.Render(fmt.Sprintf("Showing %d-%d of %d", start+1, start+visibleItems, len(shaders))))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(InfoStyle.Render(shaders[m.Cursor].Description))
+	s.WriteString("\n")
+	order := "none"
+	if len(m.Choices.Shaders) > 0 {
+		order = strings.Join(m.Choices.Shaders, " → ")
+	}
+	s.WriteString(MutedStyle.Render("Order: " + order))
+	s.WriteString("\n\n")
+	s.WriteString(HelpStyle.Render("↑/k ↓/j move • x toggle • K/J reorder • c clear • [Enter] apply • [Esc] back"))
+
+	return s.String()
+}
+
+func (m Model) renderTerminalTuning() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render(m.GetScreenTitle()))
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render(m.GetScreenDescription()))
+	s.WriteString("\n\n")
+	if m.TuningNotice != "" {
+		s.WriteString(WarningStyle.Render("⚠️  " + m.TuningNotice))
+		s.WriteString("\n\n")
+		s.WriteString(HelpStyle.Render("[Esc] back"))
+		return s.String()
+	}
+
+	for i, setting := range system.TerminalSettings {
+		cursor := "  "
+		style := UnselectedStyle
+		if i == m.Cursor {
+			cursor = "▸ "
+			style = SelectedStyle
+		}
+		value := system.FormatSetting(m.TuningValues[setting.Key])
+		if m.TuningValues[setting.Key] != m.TuningBase[setting.Key] {
+			value += " *"
+		}
+		s.WriteString(style.Render(fmt.Sprintf("%s%-22s ◂ %s ▸", cursor, setting.Label, value)))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render(fmt.Sprintf("Current values from %s; installed: %s", m.TuningSource, strings.Join(deployedTerminals(), ", "))))
+	s.WriteString("\n\n")
+	s.WriteString(HelpStyle.Render("↑/k ↓/j move • ←/h →/l change • [Enter] apply • [Esc] back"))
+
+	return s.String()
+}
+
+func (m Model) renderHerdrKeys() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render(m.GetScreenTitle()))
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render(m.GetScreenDescription()))
+	s.WriteString("\n\n")
+	if m.HerdrNotice != "" {
+		s.WriteString(WarningStyle.Render("⚠️  " + m.HerdrNotice))
+		s.WriteString("\n\n")
+		s.WriteString(HelpStyle.Render("[Esc] back"))
+		return s.String()
+	}
+
+	for i, b := range system.HerdrBindings {
+		cursor := "  "
+		style := UnselectedStyle
+		if i == m.Cursor {
+			cursor = "▸ "
+			style = SelectedStyle
+		}
+		value := m.HerdrKeyValues[b.Key]
+		if value != m.HerdrKeyBase[b.Key] {
+			value += " *"
+		}
+		s.WriteString(style.Render(fmt.Sprintf("%s%-22s ◂ %s ▸", cursor, b.Label, value)))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render("prefix+ means after the prefix; prefix+shift+j/k stay free for Herdr's pane swap"))
+	s.WriteString("\n\n")
+	s.WriteString(HelpStyle.Render("↑/k ↓/j move • ←/h →/l change • [Enter] apply • [Esc] back"))
+
+	return s.String()
+}
+
+func (m Model) renderReconfigureComplete() string {
+	var s strings.Builder
+
+	s.WriteString(SuccessStyle.Render("✨ Reconfiguration Complete! ✨"))
+	s.WriteString("\n\n")
+
+	switch m.Reconfiguring {
+	case "wm":
+		s.WriteString(InfoStyle.Render(fmt.Sprintf("  • Window Manager: %s", m.Choices.WindowMgr)))
+		s.WriteString("\n")
+		if m.Choices.Shell != "" {
+			s.WriteString(InfoStyle.Render(fmt.Sprintf("  • Default shell inside it: %s", m.Choices.Shell)))
+			s.WriteString("\n")
+		}
+	case "shell":
+		s.WriteString(InfoStyle.Render(fmt.Sprintf("  • Shell: %s", m.Choices.Shell)))
+		s.WriteString("\n")
+		s.WriteString(InfoStyle.Render(fmt.Sprintf("  • Window Manager: %s", m.Choices.WindowMgr)))
+		s.WriteString("\n")
+		if m.BackupDir != "" {
+			s.WriteString(InfoStyle.Render(fmt.Sprintf("  • Backup: %s", m.BackupDir)))
+			s.WriteString("\n")
+		}
+	case "theme":
+		if p, ok := system.LookupPalette(m.Choices.Theme); ok {
+			s.WriteString(InfoStyle.Render(fmt.Sprintf("  • Color Theme: %s", p.Name)))
+			s.WriteString("\n")
+		}
+	case "shaders":
+		shaders := "none"
+		if len(m.Choices.Shaders) > 0 {
+			shaders = strings.Join(m.Choices.Shaders, " → ")
+		}
+		s.WriteString(InfoStyle.Render(fmt.Sprintf("  • Ghostty shaders: %s", shaders)))
+		s.WriteString("\n")
+	case "layout":
+		s.WriteString(InfoStyle.Render(fmt.Sprintf("  • Zellij layout: %s", m.Choices.ZellijLayout)))
+		s.WriteString("\n")
+	case "herdrkeys":
+		for _, b := range system.HerdrBindings {
+			if v, ok := m.Choices.HerdrKeys[b.Key]; ok {
+				s.WriteString(InfoStyle.Render(fmt.Sprintf("  • Herdr %s: %s", strings.ToLower(b.Label), v)))
+				s.WriteString("\n")
+			}
+		}
+	case "terminal":
+		for _, setting := range system.TerminalSettings {
+			if v, ok := m.Choices.TerminalSettings[setting.Key]; ok {
+				s.WriteString(InfoStyle.Render(fmt.Sprintf("  • %s: %s", setting.Label, system.FormatSetting(v))))
+				s.WriteString("\n")
+			}
+		}
+	}
+
+	s.WriteString("\n")
+	s.WriteString(InfoStyle.Render("Open a new terminal window for the change to take effect."))
+	s.WriteString("\n\n")
+	s.WriteString(HelpStyle.Render("Press [Enter] or [q] to exit"))
+
+	return s.String()
+}
+
 func (m Model) renderError() string {
 	var s strings.Builder
 
@@ -1190,6 +1585,65 @@ func (m Model) renderBackupConfirm() string {
 	return s.String()
 }
 
+func (m Model) renderPreflight() string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render(m.GetScreenTitle()))
+	s.WriteString("\n")
+	s.WriteString(MutedStyle.Render(m.GetScreenDescription()))
+	s.WriteString("\n\n")
+
+	if total := estimateInstall(&m); !total.IsZero() {
+		s.WriteString(InfoStyle.Render("Estimated total: " + total.String()))
+		s.WriteString("\n\n")
+	}
+
+	if m.PreflightRunning {
+		s.WriteString(InfoStyle.Render("⏳ Running checks..."))
+		s.WriteString("\n")
+		return s.String()
+	}
+
+	for _, check := range m.PreflightChecks {
+		var line string
+		switch check.Status {
+		case CheckPass:
+			line = SuccessStyle.Render("  ✓ " + check.Name)
+		case CheckWarn:
+			line = WarningStyle.Render("  ⚠ " + check.Name)
+		default:
+			line = ErrorStyle.Render("  ✗ " + check.Name)
+		}
+		s.WriteString(line)
+		if check.Detail != "" {
+			s.WriteString(MutedStyle.Render("  " + check.Detail))
+		}
+		s.WriteString("\n")
+		if check.Status != CheckPass && check.Remediation != "" {
+			s.WriteString(InfoStyle.Render("      → " + check.Remediation))
+			s.WriteString("\n")
+		}
+	}
+	s.WriteString("\n")
+
+	options := m.GetCurrentOptions()
+	for i, opt := range options {
+		cursor := "  "
+		style := UnselectedStyle
+		if i == m.Cursor {
+			cursor = "▸ "
+			style = SelectedStyle
+		}
+		s.WriteString(style.Render(cursor + opt))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(HelpStyle.Render("↑/k up • ↓/j down • [Enter] select • [Esc] back"))
+
+	return s.String()
+}
+
 func (m Model) renderRestoreBackup() string {
 	var s strings.Builder
 
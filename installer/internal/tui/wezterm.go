@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// weztermChoices resolves the shell and multiplexer the WezTerm config should
+// reflect, reading the installed setup for whichever of the two is not chosen
+func weztermChoices(m *Model) system.WeztermChoices {
+	shell := m.Choices.Shell
+	if shell == "" {
+		shell = currentShellChoice(m)
+	}
+	wm := m.Choices.WindowMgr
+	if wm == "" && shell != "" {
+		wm = currentWM(shell)
+	}
+	c := system.WeztermChoices{Multiplexer: wm}
+	if shell != "" {
+		c.Shell = loginShellPath(m, shellBinary(shell))
+	}
+	return c
+}
+
+// applyWeztermChoices rewrites the installer section of a deployed WezTerm config
+func applyWeztermChoices(m *Model, stepID string) error {
+	path := terminalConfigPath("wezterm")
+	if !system.FileExists(path) {
+		return nil
+	}
+	c := weztermChoices(m)
+	SendLog(stepID, fmt.Sprintf("Updating WezTerm for %s...", describeWeztermChoices(c)))
+	if err := localizeConfig(path); err != nil {
+		return err
+	}
+	return system.SetWeztermChoices(path, c)
+}
+
+// describeWeztermChoices is the log summary of c
+func describeWeztermChoices(c system.WeztermChoices) string {
+	shell := "the default shell"
+	if c.Shell != "" {
+		shell = c.Shell
+	}
+	switch c.Multiplexer {
+	case "tmux", "zellij", "herdr":
+		return shell + " with " + c.Multiplexer
+	}
+	return shell + " with WezTerm panes"
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+func TestSetShellPlan_WeztermFollowsChoices(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "fish"), []byte("#!/bin/sh\n"), 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	config := terminalConfigPath("wezterm")
+	os.MkdirAll(filepath.Dir(config), 0755)
+	os.WriteFile(config, []byte("local config = {}\n\nreturn config\n"), 0644)
+
+	m := NewModel()
+	m.Choices.Shell = "fish"
+	m.Choices.WindowMgr = "tmux"
+	p, err := setShellPlan(&m)
+	if err != nil {
+		t.Fatalf("setShellPlan: %v", err)
+	}
+	before, _, _ := splitPlan(p)
+	if err := runActions(p, before); err != nil {
+		t.Fatalf("runActions: %v", err)
+	}
+
+	data, _ := os.ReadFile(config)
+	body, ok := system.BlockBody(string(data), system.SyntaxLua, system.BlockWezterm)
+	if !ok || !strings.Contains(body, `config.default_prog = { "`+filepath.Join(bin, "fish")+`", "-l" }`) {
+		t.Errorf("expected default_prog for fish, got:\n%s", data)
+	}
+	if strings.Contains(body, "config.leader") {
+		t.Errorf("Ctrl+a belongs to tmux, got:\n%s", body)
+	}
+}
+
+func TestApplyWeztermChoices_NoConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := NewModel()
+	m.Choices.Shell = "bash"
+	if err := applyWeztermChoices(&m, "setshell"); err != nil {
+		t.Fatalf("expected nothing to do without a WezTerm config, got %v", err)
+	}
+	if system.FileExists(terminalConfigPath("wezterm")) {
+		t.Error("the WezTerm config must not be created")
+	}
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+)
+
+// zellijLayoutsDir is where the shipped layouts are deployed
+func zellijLayoutsDir() string {
+	return filepath.Join(filepath.Dir(wmConfigPath("zellij")), "layouts")
+}
+
+// InstalledZellijLayouts returns the deployed layouts and the one config.kdl starts with
+func InstalledZellijLayouts() (layouts []string, current string, err error) {
+	path := wmConfigPath("zellij")
+	if !system.FileExists(path) {
+		return nil, "", fmt.Errorf("no Zellij config at %s; install Zellij first", path)
+	}
+	layouts, err = system.ZellijLayouts(zellijLayoutsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", fmt.Errorf("no layouts in %s; reinstall Zellij", zellijLayoutsDir())
+		}
+		return nil, "", err
+	}
+	current, err = system.ZellijOption(path, "default_layout")
+	return layouts, current, err
+}
+
+// layoutPickerOptions lists the layouts with the one in use marked
+func layoutPickerOptions(layouts []string, current string) []string {
+	options := make([]string, 0, len(layouts)+2)
+	for _, layout := range layouts {
+		if layout == current {
+			layout += " (current)"
+		}
+		options = append(options, layout)
+	}
+	return append(options, "─────────────", "← Back")
+}
+
+// layoutByOption maps a layout screen option back to the layout name
+func layoutByOption(option string) string {
+	return strings.TrimSuffix(option, " (current)")
+}
+
+// stepApplyZellijLayout writes m.Choices.ZellijLayout as zellij's default_layout
+func stepApplyZellijLayout(m *Model) error {
+	stepID := "zellijlayout"
+	layout := m.Choices.ZellijLayout
+	path := wmConfigPath("zellij")
+	if !system.FileExists(path) {
+		return wrapStepError(stepID, "Zellij Layout",
+			"Zellij config not found",
+			fmt.Errorf("%s does not exist; install Zellij first", path))
+	}
+	if !system.FileExists(filepath.Join(zellijLayoutsDir(), layout+".kdl")) {
+		return wrapStepError(stepID, "Zellij Layout",
+			"Layout not found",
+			fmt.Errorf("%s.kdl is not in %s", layout, zellijLayoutsDir()))
+	}
+
+	SendLog(stepID, "Setting default_layout in config.kdl...")
+	if err := localizeConfig(path); err != nil {
+		return wrapStepError(stepID, "Zellij Layout", "Failed to prepare the Zellij config", err)
+	}
+	if err := system.SetZellijDefaultLayout(path, layout); err != nil {
+		return wrapStepError(stepID, "Zellij Layout", "Failed to write the default layout", err)
+	}
+
+	SendLog(stepID, "✓ Zellij starts with the "+layout+" layout")
+	SendLog(stepID, "Running sessions keep their layout; new sessions use it")
+	return nil
+}
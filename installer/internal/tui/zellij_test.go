@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/Gentleman.Dots/installer/internal/system"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLayoutPicker_AppliesSelection(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	zellijDir := filepath.Join(home, ".config", "zellij")
+	if err := system.CopyDir("../../../GentlemanZellij/zellij", zellijDir); err != nil {
+		t.Skipf("shipped zellij config not available: %v", err)
+	}
+
+	m := NewModel()
+	m.Screen = ScreenReconfigure
+	for i, opt := range m.GetCurrentOptions() {
+		if strings.Contains(opt, "Zellij Layout") {
+			m.Cursor = i
+		}
+	}
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenReconfigureLayout || m.LayoutNotice != "" {
+		t.Fatalf("expected the layout picker, got screen %v (%s)", m.Screen, m.LayoutNotice)
+	}
+	options := m.GetCurrentOptions()
+	if !containsValue(options, "work_kanagawa (current)") || !containsValue(options, "work_sakura") {
+		t.Fatalf("expected the shipped layouts with the current one marked, got %v", options)
+	}
+
+	for i, opt := range options {
+		if opt == "work_sakura" {
+			m.Cursor = i
+		}
+	}
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.Screen != ScreenInstalling || cmd == nil || stepIDs(m.Steps)[0] != "zellijlayout" {
+		t.Fatalf("expected the layout step to start, got screen %v", m.Screen)
+	}
+	if err := stepApplyZellijLayout(&m); err != nil {
+		t.Fatalf("stepApplyZellijLayout: %v", err)
+	}
+	if layout, _ := system.ZellijOption(filepath.Join(zellijDir, "config.kdl"), "default_layout"); layout != "work_sakura" {
+		t.Errorf("expected default_layout work_sakura, got %q", layout)
+	}
+
+	m.Choices.ZellijLayout = "missing"
+	if err := stepApplyZellijLayout(&m); err == nil {
+		t.Error("expected an error for a layout that is not deployed")
+	}
+}
+
+func TestLayoutPicker_WithoutZellij(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if _, _, err := InstalledZellijLayouts(); err == nil {
+		t.Fatal("expected an error without a Zellij config")
+	}
+
+	m := NewModel()
+	m.Screen = ScreenReconfigure
+	for i, opt := range m.GetCurrentOptions() {
+		if strings.Contains(opt, "Zellij Layout") {
+			m.Cursor = i
+		}
+	}
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	if m.LayoutNotice == "" || !strings.Contains(m.View(), m.LayoutNotice) {
+		t.Fatal("expected a notice without a Zellij config")
+	}
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if result.(Model).Screen != ScreenReconfigureLayout {
+		t.Error("Enter must not start a step without a Zellij config")
+	}
+}